@@ -2,7 +2,7 @@ package common
 
 import (
 	"context"
-	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -11,60 +11,315 @@ import (
 	"strings"
 
 	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
 	"github.com/cloudforet-io/cfctl/pkg/format"
-	"github.com/jhump/protoreflect/grpcreflect"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
-	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 	"gopkg.in/yaml.v3"
 )
 
+// APIResourceOptions holds the filters and output format of api_resources
+type APIResourceOptions struct {
+	Verb     string // Only show resources supporting the verb
+	Resource string // Only show the resource, case insensitive
+	Output   string // table, wide, json, yaml or name
+}
+
+// APIResource is a resource of a service in json and yaml output
+type APIResource struct {
+	Service  string            `json:"service" yaml:"service"`
+	Resource string            `json:"resource" yaml:"resource"`
+	Package  string            `json:"package" yaml:"package"`
+	Verbs    []string          `json:"verbs" yaml:"verbs"`
+	Aliases  map[string]string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Methods  []MethodInfo      `json:"methods,omitempty" yaml:"methods,omitempty"`
+}
+
+// MethodInfo is the proto level metadata of a verb
+type MethodInfo struct {
+	Service         string `json:"-" yaml:"-"`
+	Resource        string `json:"-" yaml:"-"`
+	Verb            string `json:"verb" yaml:"verb"`
+	Deprecated      bool   `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	ServerStreaming bool   `json:"server_streaming,omitempty" yaml:"server_streaming,omitempty"`
+	ClientStreaming bool   `json:"client_streaming,omitempty" yaml:"client_streaming,omitempty"`
+	Description     string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
 // FetchApiResourcesCmd provides api-resources command for the given service
 func FetchApiResourcesCmd(serviceName string) *cobra.Command {
-	return &cobra.Command{
+	options := APIResourceOptions{}
+
+	cmd := &cobra.Command{
 		Use:   "api_resources",
 		Short: fmt.Sprintf("Displays supported API resources for the %s service", serviceName),
+		Example: fmt.Sprintf(`  # List resources supporting the list verb
+  $ cfctl %[1]s api_resources --verb list
+
+  # Print resource names for scripts
+  $ cfctl %[1]s api_resources -o name`, serviceName),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return ListAPIResources(serviceName)
+			return ListAPIResources(serviceName, options)
 		},
 	}
+
+	cmd.Flags().StringVar(&options.Verb, "verb", "", "Only show resources supporting the verb")
+	cmd.Flags().StringVar(&options.Resource, "resource", "", "Only show the given resource")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "table", "Output format (table, wide, json, yaml, name)")
+
+	return cmd
+}
+
+func ListAPIResources(serviceName string, options APIResourceOptions) error {
+	data, methods, err := fetchAPIResources(serviceName)
+	if err != nil {
+		return err
+	}
+
+	data = filterAPIResources(data, options.Verb, options.Resource)
+
+	sort.Slice(data, func(i, j int) bool {
+		return data[i][0] < data[j][0]
+	})
+
+	return printAPIResources(data, methods, options.Output)
 }
 
-func ListAPIResources(serviceName string) error {
+// fetchAPIResources returns the resources and methods of a service in the current environment
+func fetchAPIResources(serviceName string) ([][]string, []MethodInfo, error) {
 	setting, err := configs.SetSettingFile()
 	if err != nil {
-		return fmt.Errorf("failed to load setting: %v", err)
+		return nil, nil, fmt.Errorf("failed to load setting: %v", err)
+	}
+
+	envSetting := setting.Environments[setting.Environment]
+	resolver, err := endpoints.NewResolver(setting.Environment, envSetting.Endpoint, envSetting.Endpoints)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	//endpoint, err := getServiceEndpoint(setting, serviceName)
-	endpoint, err := configs.GetServiceEndpoint(setting, serviceName)
+	target, err := resolver.Resolve(serviceName)
 	if err != nil {
-		return fmt.Errorf("failed to get endpoint for service %s: %v", serviceName, err)
+		return nil, nil, fmt.Errorf("failed to get endpoint for service %s: %v", serviceName, err)
 	}
+	endpoint := target.String()
 
 	shortNamesMap, err := loadShortNames()
 	if err != nil {
-		return fmt.Errorf("failed to load short names: %v", err)
+		return nil, nil, fmt.Errorf("failed to load short names: %v", err)
 	}
 
-	data, err := FetchServiceResources(serviceName, endpoint, shortNamesMap, setting)
+	data, methods, err := FetchServiceResources(serviceName, endpoint, shortNamesMap, setting)
 	if err != nil {
-		return fmt.Errorf("failed to fetch resources for service %s: %v", serviceName, err)
+		return nil, nil, fmt.Errorf("failed to fetch resources for service %s: %v", serviceName, err)
 	}
 
-	sort.Slice(data, func(i, j int) bool {
-		return data[i][0] < data[j][0]
-	})
+	return data, methods, nil
+}
 
-	format.RenderTable(data)
+// filterAPIResources keeps the rows of the resource and reduces the verbs of each row to the given verb
+func filterAPIResources(data [][]string, verb, resource string) [][]string {
+	if verb == "" && resource == "" {
+		return data
+	}
+
+	filtered := [][]string{}
+	for _, row := range data {
+		if resource != "" && !strings.EqualFold(row[2], resource) {
+			continue
+		}
+		if verb != "" {
+			if !containsString(strings.Split(row[1], ", "), verb) {
+				continue
+			}
+			row = append([]string{row[0], verb}, row[2:]...)
+		}
+		filtered = append(filtered, row)
+	}
+
+	return filtered
+}
+
+// printAPIResources prints the resource rows in the given output format
+func printAPIResources(data [][]string, methods []MethodInfo, output string) error {
+	switch output {
+	case "", "table":
+		format.RenderTable(markVerbs(data, methods))
+	case "wide":
+		renderMethodTable(data, methods)
+	case "name":
+		seen := make(map[string]bool)
+		for _, row := range data {
+			if !seen[row[2]] {
+				seen[row[2]] = true
+				fmt.Println(row[2])
+			}
+		}
+	case "json":
+		jsonBytes, err := json.MarshalIndent(groupAPIResources(data, methods), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal resources: %v", err)
+		}
+		fmt.Println(string(jsonBytes))
+	case "yaml":
+		yamlBytes, err := yaml.Marshal(groupAPIResources(data, methods))
+		if err != nil {
+			return fmt.Errorf("failed to marshal resources: %v", err)
+		}
+		fmt.Print(string(yamlBytes))
+	default:
+		return fmt.Errorf("unsupported output format: %s (use table, wide, json, yaml or name)", output)
+	}
 
 	return nil
 }
 
+// groupAPIResources merges the rows of each resource, verbs with a short name are listed as aliases
+func groupAPIResources(data [][]string, methods []MethodInfo) []APIResource {
+	var resources []APIResource
+	index := make(map[string]int)
+
+	for _, row := range data {
+		key := row[0] + "/" + row[2]
+		i, ok := index[key]
+		if !ok {
+			resource := APIResource{Service: row[0], Resource: row[2], Verbs: []string{}}
+			if len(row) > 4 {
+				resource.Package = row[4]
+			}
+			resources = append(resources, resource)
+			i = len(resources) - 1
+			index[key] = i
+		}
+
+		verbs := strings.Split(row[1], ", ")
+		resources[i].Verbs = append(resources[i].Verbs, verbs...)
+		if row[3] != "" {
+			if resources[i].Aliases == nil {
+				resources[i].Aliases = make(map[string]string)
+			}
+			resources[i].Aliases[row[3]] = row[1]
+		}
+	}
+
+	for i := range resources {
+		sort.Strings(resources[i].Verbs)
+		for _, method := range methods {
+			if method.Service == resources[i].Service && method.Resource == resources[i].Resource &&
+				containsString(resources[i].Verbs, method.Verb) {
+				resources[i].Methods = append(resources[i].Methods, method)
+			}
+		}
+	}
+
+	return resources
+}
+
+// methodInfos collects the metadata of the methods of a service descriptor.
+// Descriptions are only available when the server includes source info in its descriptors.
+func methodInfos(serviceName, resourceName string, serviceDesc *desc.ServiceDescriptor) []MethodInfo {
+	var infos []MethodInfo
+	for _, method := range serviceDesc.GetMethods() {
+		info := MethodInfo{
+			Service:         serviceName,
+			Resource:        resourceName,
+			Verb:            method.GetName(),
+			Deprecated:      method.GetMethodOptions().GetDeprecated(),
+			ServerStreaming: method.IsServerStreaming(),
+			ClientStreaming: method.IsClientStreaming(),
+		}
+		if sourceInfo := method.GetSourceInfo(); sourceInfo != nil {
+			info.Description = strings.TrimSpace(sourceInfo.GetLeadingComments())
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// findMethodInfo returns the metadata of a verb of the resource
+func findMethodInfo(methods []MethodInfo, service, resource, verb string) (MethodInfo, bool) {
+	for _, method := range methods {
+		if method.Service == service && method.Resource == resource && method.Verb == verb {
+			return method, true
+		}
+	}
+	return MethodInfo{}, false
+}
+
+// markVerbs annotates streaming and deprecated verbs in the verb column
+// Example:
+//
+//	list, stat, watch (stream), update_legacy (deprecated)
+func markVerbs(data [][]string, methods []MethodInfo) [][]string {
+	marked := make([][]string, 0, len(data))
+	for _, row := range data {
+		verbs := strings.Split(row[1], ", ")
+		for i, verb := range verbs {
+			info, ok := findMethodInfo(methods, row[0], row[2], verb)
+			if !ok {
+				continue
+			}
+			var notes []string
+			if info.ServerStreaming || info.ClientStreaming {
+				notes = append(notes, "stream")
+			}
+			if info.Deprecated {
+				notes = append(notes, "deprecated")
+			}
+			if len(notes) > 0 {
+				verbs[i] = fmt.Sprintf("%s (%s)", verb, strings.Join(notes, ", "))
+			}
+		}
+		markedRow := append([]string{}, row...)
+		markedRow[1] = strings.Join(verbs, ", ")
+		marked = append(marked, markedRow)
+	}
+	return marked
+}
+
+// renderMethodTable prints one row per verb with its streaming type, deprecation and description
+func renderMethodTable(data [][]string, methods []MethodInfo) {
+	table := pterm.TableData{{"Service", "Resource", "Verb", "Streaming", "Deprecated", "Description"}}
+	for _, row := range data {
+		for _, verb := range strings.Split(row[1], ", ") {
+			info, _ := findMethodInfo(methods, row[0], row[2], verb)
+
+			streaming := ""
+			switch {
+			case info.ClientStreaming && info.ServerStreaming:
+				streaming = "bidi"
+			case info.ServerStreaming:
+				streaming = "server"
+			case info.ClientStreaming:
+				streaming = "client"
+			}
+
+			deprecated := ""
+			if info.Deprecated {
+				deprecated = "yes"
+			}
+
+			description := strings.Split(info.Description, "\n")[0]
+			table = append(table, []string{row[0], row[2], verb, streaming, deprecated, description})
+		}
+	}
+
+	pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+}
+
+// containsString reports whether the list contains the value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
 func loadShortNames() (map[string]string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -87,47 +342,34 @@ func loadShortNames() (map[string]string, error) {
 	return shortNamesMap, nil
 }
 
-func FetchServiceResources(serviceName, endpoint string, shortNamesMap map[string]string, config *configs.Environments) ([][]string, error) {
-	parts := strings.Split(endpoint, "://")
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid endpoint format: %s", endpoint)
-	}
-	scheme := parts[0]
-	hostPort := parts[1]
-
-	var opts []grpc.DialOption
-	if scheme == "grpc+ssl" {
-		tlsConfig := &tls.Config{
-			InsecureSkipVerify: false,
-		}
-		creds := credentials.NewTLS(tlsConfig)
-		opts = append(opts, grpc.WithTransportCredentials(creds))
-	} else if scheme == "grpc" {
-		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	} else {
-		return nil, fmt.Errorf("unsupported scheme: %s", scheme)
+func FetchServiceResources(serviceName, endpoint string, shortNamesMap map[string]string, config *configs.Environments) ([][]string, []MethodInfo, error) {
+	target, err := endpoints.ParseTarget(endpoint)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	conn, err := grpc.Dial(hostPort, opts...)
+	conn, err := configs.Dial(target.HostPort, target.Credentials())
 	if err != nil {
-		return nil, fmt.Errorf("connection failed: unable to connect to %s: %v", endpoint, err)
+		return nil, nil, fmt.Errorf("connection failed: unable to connect to %s: %v", endpoint, err)
 	}
 	defer conn.Close()
 
-	ctx := metadata.AppendToOutgoingContext(context.Background(), "token", config.Environments[config.Environment].Token)
+	env := config.Environments[config.Environment]
+	authKey, authValue := env.Auth.Metadata(env.Token)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), authKey, authValue)
 
-	refClient := grpcreflect.NewClient(ctx, grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+	refClient := configs.NewReflectionClient(ctx, conn)
 	defer refClient.Reset()
 
 	services, err := refClient.ListServices()
 	if err != nil {
-		return nil, fmt.Errorf("failed to list services: %v", err)
+		return nil, nil, fmt.Errorf("failed to list services: %v", err)
 	}
 
 	// Load short names from setting.yaml
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %v", err)
+		return nil, nil, fmt.Errorf("failed to get home directory: %v", err)
 	}
 
 	settingPath := filepath.Join(home, ".cfctl", "setting.yaml")
@@ -148,6 +390,7 @@ func FetchServiceResources(serviceName, endpoint string, shortNamesMap map[strin
 
 	data := [][]string{}
 	resourceData := make(map[string][][]string)
+	var methods []MethodInfo
 
 	for _, s := range services {
 		if strings.HasPrefix(s, "grpc.reflection.") {
@@ -165,6 +408,7 @@ func FetchServiceResources(serviceName, endpoint string, shortNamesMap map[strin
 				}
 
 				resourceName := s[strings.LastIndex(s, ".")+1:]
+				methods = append(methods, methodInfos(displayServiceName, resourceName, serviceDesc)...)
 				verbs := []string{}
 				for _, method := range serviceDesc.GetMethods() {
 					verbs = append(verbs, method.GetName())
@@ -176,6 +420,7 @@ func FetchServiceResources(serviceName, endpoint string, shortNamesMap map[strin
 					strings.Join(verbs, ", "),
 					resourceName,
 					"",
+					s[:strings.LastIndex(s, ".")],
 				})
 				continue
 			}
@@ -190,6 +435,8 @@ func FetchServiceResources(serviceName, endpoint string, shortNamesMap map[strin
 		}
 
 		resourceName := s[strings.LastIndex(s, ".")+1:]
+		packageName := s[:strings.LastIndex(s, ".")]
+		methods = append(methods, methodInfos(serviceName, resourceName, serviceDesc)...)
 		verbs := []string{}
 		for _, method := range serviceDesc.GetMethods() {
 			verbs = append(verbs, method.GetName())
@@ -206,7 +453,7 @@ func FetchServiceResources(serviceName, endpoint string, shortNamesMap map[strin
 				verb := parts[0]
 				usedVerbs[verb] = true
 				// Add a row for the verb with short name
-				resourceRows = append(resourceRows, []string{serviceName, verb, resourceName, shortName})
+				resourceRows = append(resourceRows, []string{serviceName, verb, resourceName, shortName, packageName})
 			}
 		}
 
@@ -219,7 +466,7 @@ func FetchServiceResources(serviceName, endpoint string, shortNamesMap map[strin
 		}
 
 		if len(remainingVerbs) > 0 {
-			resourceRows = append([][]string{{serviceName, strings.Join(remainingVerbs, ", "), resourceName, ""}}, resourceRows...)
+			resourceRows = append([][]string{{serviceName, strings.Join(remainingVerbs, ", "), resourceName, "", packageName}}, resourceRows...)
 		}
 
 		resourceData[resourceName] = resourceRows
@@ -237,5 +484,5 @@ func FetchServiceResources(serviceName, endpoint string, shortNamesMap map[strin
 		data = append(data, resourceData[resource]...)
 	}
 
-	return data, nil
+	return data, methods, nil
 }
@@ -0,0 +1,129 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// methodsCacheTTL is how long the cached methods of a service are used before they are fetched again
+const methodsCacheTTL = 24 * time.Hour
+
+// CompleteServiceArgs completes the verb and resource arguments of a service command
+// from the descriptors of the service
+func CompleteServiceArgs(serviceName string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		resources, err := completionResources(serviceName)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		var completions []string
+		if len(args) == 0 {
+			verbs := map[string]bool{"api_resources": true}
+			for _, resourceVerbs := range resources {
+				for _, verb := range resourceVerbs {
+					verbs[verb] = true
+				}
+			}
+			if verbs["list"] {
+				verbs["watch"] = true
+			}
+			for verb := range verbs {
+				completions = append(completions, verb)
+			}
+		} else {
+			verb := args[0]
+			if verb == "watch" {
+				verb = "list"
+			}
+			for resource, resourceVerbs := range resources {
+				if containsString(resourceVerbs, verb) {
+					completions = append(completions, resource)
+				}
+			}
+		}
+
+		sort.Strings(completions)
+		return filterPrefix(completions, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completionResources returns the verbs of each resource of a service
+func completionResources(serviceName string) (map[string][]string, error) {
+	methods, err := ServiceMethods(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make(map[string][]string)
+	for resource, resourceMethods := range methods {
+		for _, method := range resourceMethods {
+			resources[resource] = append(resources[resource], method.Verb)
+		}
+	}
+	return resources, nil
+}
+
+// ServiceMethods returns the methods of each resource of a service.
+// They are cached per environment since completion runs on every key press.
+func ServiceMethods(serviceName string) (map[string][]MethodInfo, error) {
+	setting, err := configs.SetSettingFile()
+	if err != nil {
+		return nil, err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	cachePath := filepath.Join(home, ".cfctl", "cache", setting.Environment, "methods", serviceName+".yaml")
+
+	if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < methodsCacheTTL {
+		if data, err := os.ReadFile(cachePath); err == nil {
+			var methods map[string][]MethodInfo
+			if err := yaml.Unmarshal(data, &methods); err == nil {
+				return methods, nil
+			}
+		}
+	}
+
+	_, infos, err := fetchAPIResources(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	methods := make(map[string][]MethodInfo)
+	for _, info := range infos {
+		methods[info.Resource] = append(methods[info.Resource], info)
+	}
+
+	if data, err := yaml.Marshal(methods); err == nil {
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+			_ = os.WriteFile(cachePath, data, 0644)
+		}
+	}
+
+	return methods, nil
+}
+
+// filterPrefix returns the values starting with the prefix
+func filterPrefix(values []string, prefix string) []string {
+	var filtered []string
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			filtered = append(filtered, value)
+		}
+	}
+	return filtered
+}
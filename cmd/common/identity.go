@@ -0,0 +1,419 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// IdentityServiceAccountCmd provides the service account onboarding commands for the identity service
+func IdentityServiceAccountCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "service-account",
+		Short: "Onboard service accounts",
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a service account and its secret in one flow",
+		Example: `  # Walk through the provider schema
+  $ cfctl identity service-account create --provider aws --interactive
+
+  # Create without prompts
+  $ cfctl identity service-account create --provider aws --name prod --project project-123456789012 \
+      -d account_id=123456789012 --secret-schema aws_access_key \
+      -s aws_access_key_id=AKIA... -s aws_secret_access_key=...`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			provider, _ := cmd.Flags().GetString("provider")
+			name, _ := cmd.Flags().GetString("name")
+			projectID, _ := cmd.Flags().GetString("project")
+			trustedAccountID, _ := cmd.Flags().GetString("trusted-account")
+			secretSchemaID, _ := cmd.Flags().GetString("secret-schema")
+			dataArgs, _ := cmd.Flags().GetStringArray("data")
+			secretDataArgs, _ := cmd.Flags().GetStringArray("secret-data")
+			interactive, _ := cmd.Flags().GetBool("interactive")
+
+			data, err := parseKeyValues(dataArgs)
+			if err != nil {
+				return err
+			}
+			secretData, err := parseKeyValues(secretDataArgs)
+			if err != nil {
+				return err
+			}
+
+			if interactive {
+				if name == "" {
+					if err := survey.AskOne(&survey.Input{Message: "Service account name:"}, &name, survey.WithValidator(survey.Required)); err != nil {
+						return err
+					}
+				}
+				if projectID == "" {
+					if err := survey.AskOne(&survey.Input{Message: "Project id (or name:<project name>):"}, &projectID, survey.WithValidator(survey.Required)); err != nil {
+						return err
+					}
+				}
+			}
+			if name == "" || projectID == "" {
+				return fmt.Errorf("--name and --project are required without --interactive")
+			}
+
+			// Service account data follows the provider schema
+			accountSchema, err := fetchProviderSchema(provider, "SERVICE_ACCOUNT")
+			if err != nil {
+				return err
+			}
+			if accountSchema != nil {
+				if err := fillSchemaValues(accountSchema, data, interactive); err != nil {
+					return err
+				}
+			}
+
+			// Pick one of the secret schemas of the provider
+			if secretSchemaID == "" && interactive {
+				secretSchemaID, err = selectSecretSchema(provider, accountSchema)
+				if err != nil {
+					return err
+				}
+			}
+			if secretSchemaID != "" {
+				secretSchema, err := fetchSchema(secretSchemaID)
+				if err != nil {
+					return err
+				}
+				if err := fillSchemaValues(secretSchema, secretData, interactive); err != nil {
+					return err
+				}
+			}
+
+			params := map[string]interface{}{
+				"name":       name,
+				"provider":   provider,
+				"project_id": projectID,
+				"data":       data,
+			}
+			if trustedAccountID != "" {
+				params["trusted_account_id"] = trustedAccountID
+			}
+			if secretSchemaID != "" {
+				params["secret_schema_id"] = secretSchemaID
+				params["secret_data"] = secretData
+			}
+			paramBytes, err := json.Marshal(params)
+			if err != nil {
+				return fmt.Errorf("failed to marshal service account: %v", err)
+			}
+
+			account, err := transport.FetchService("identity", "create", "ServiceAccount", &transport.FetchOptions{
+				JSONParameter: string(paramBytes),
+			})
+			if err != nil {
+				return err
+			}
+			if account == nil {
+				return fmt.Errorf("failed to create service account")
+			}
+
+			accountID, _ := account["service_account_id"].(string)
+			pterm.Success.Printf("Service account %s (%s) created\n", name, accountID)
+
+			steps := []string{
+				fmt.Sprintf("1. Check the account: cfctl identity get ServiceAccount -p service_account_id=%s", accountID),
+				"2. Find a collector for the provider: cfctl inventory list Collector -c collector_id,name,provider",
+				"3. Run the collector: cfctl inventory collect <collector_id> --watch",
+			}
+			pterm.DefaultBox.WithTitle("Next Steps").
+				WithTitleTopCenter().
+				WithRightPadding(4).
+				WithLeftPadding(4).
+				Println(strings.Join(steps, "\n\n"))
+
+			return nil
+		},
+	}
+
+	createCmd.Flags().String("provider", "", "Provider of the service account (aws, google_cloud, azure, ...)")
+	createCmd.Flags().String("name", "", "Service account name")
+	createCmd.Flags().String("project", "", "Project id of the service account")
+	createCmd.Flags().String("trusted-account", "", "Trusted account id")
+	createCmd.Flags().String("secret-schema", "", "Secret schema id")
+	createCmd.Flags().StringArrayP("data", "d", []string{}, "Service account data (-d key=value -d ...)")
+	createCmd.Flags().StringArrayP("secret-data", "s", []string{}, "Secret data (-s key=value -s ...)")
+	createCmd.Flags().BoolP("interactive", "i", false, "Prompt for values using the provider schema")
+	createCmd.MarkFlagRequired("provider")
+
+	cmd.AddCommand(createCmd)
+	return cmd
+}
+
+// fetchProviderSchema returns the first schema of the given type for a provider
+func fetchProviderSchema(provider, schemaType string) (map[string]interface{}, error) {
+	resp, err := transport.FetchService("identity", "list", "Schema", &transport.FetchOptions{
+		Parameters: []string{fmt.Sprintf("provider=%s", provider), fmt.Sprintf("schema_type=%s", schemaType)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results, _ := resp["results"].([]interface{})
+	if len(results) == 0 {
+		return nil, nil
+	}
+	schema, _ := results[0].(map[string]interface{})
+	return schema, nil
+}
+
+func fetchSchema(schemaID string) (map[string]interface{}, error) {
+	schema, err := transport.FetchService("identity", "get", "Schema", &transport.FetchOptions{
+		Parameters: []string{fmt.Sprintf("schema_id=%s", schemaID)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("failed to get schema %s", schemaID)
+	}
+	return schema, nil
+}
+
+// selectSecretSchema prompts for one of the secret schemas related to the account schema
+func selectSecretSchema(provider string, accountSchema map[string]interface{}) (string, error) {
+	var options []string
+	if accountSchema != nil {
+		if related, ok := accountSchema["related_schemas"].([]interface{}); ok {
+			for _, id := range related {
+				options = append(options, fmt.Sprintf("%v", id))
+			}
+		}
+	}
+	if len(options) == 0 {
+		resp, err := transport.FetchService("identity", "list", "Schema", &transport.FetchOptions{
+			Parameters: []string{fmt.Sprintf("provider=%s", provider), "schema_type=SECRET"},
+		})
+		if err != nil {
+			return "", err
+		}
+		results, _ := resp["results"].([]interface{})
+		for _, result := range results {
+			if schema, ok := result.(map[string]interface{}); ok {
+				options = append(options, fmt.Sprintf("%v", schema["schema_id"]))
+			}
+		}
+	}
+	if len(options) == 0 {
+		pterm.Warning.Printf("No secret schema found for provider %s\n", provider)
+		return "", nil
+	}
+
+	const skip = "(no secret)"
+	selected := ""
+	prompt := &survey.Select{
+		Message: "Secret schema:",
+		Options: append(options, skip),
+	}
+	if err := survey.AskOne(prompt, &selected); err != nil {
+		return "", err
+	}
+	if selected == skip {
+		return "", nil
+	}
+	return selected, nil
+}
+
+// fillSchemaValues prompts for and validates values against a JSON schema
+func fillSchemaValues(schema map[string]interface{}, values map[string]interface{}, interactive bool) error {
+	jsonSchema, _ := schema["schema"].(map[string]interface{})
+	properties, _ := jsonSchema["properties"].(map[string]interface{})
+
+	required := make(map[string]bool)
+	if requiredList, ok := jsonSchema["required"].([]interface{}); ok {
+		for _, r := range requiredList {
+			required[fmt.Sprintf("%v", r)] = true
+		}
+	}
+
+	// Required fields first, then in name order
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if required[keys[i]] != required[keys[j]] {
+			return required[keys[i]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	for _, key := range keys {
+		property, _ := properties[key].(map[string]interface{})
+		propType, _ := property["type"].(string)
+
+		if _, exists := values[key]; !exists && interactive {
+			title, _ := property["title"].(string)
+			if title == "" {
+				title = key
+			}
+			message := title + ":"
+			if !required[key] {
+				message = title + " (optional):"
+			}
+
+			var answer string
+			var prompt survey.Prompt = &survey.Input{Message: message}
+			if isSecretProperty(key, property) {
+				prompt = &survey.Password{Message: message}
+			}
+			var opts []survey.AskOpt
+			if required[key] {
+				opts = append(opts, survey.WithValidator(survey.Required))
+			}
+			if err := survey.AskOne(prompt, &answer, opts...); err != nil {
+				return err
+			}
+			if answer != "" {
+				values[key] = answer
+			}
+		}
+
+		value, exists := values[key]
+		if !exists {
+			if required[key] {
+				return fmt.Errorf("missing required field '%s'", key)
+			}
+			continue
+		}
+
+		converted, err := convertSchemaValue(key, value, propType)
+		if err != nil {
+			return err
+		}
+		values[key] = converted
+	}
+
+	return nil
+}
+
+// convertSchemaValue converts string input to the JSON schema type of a property
+func convertSchemaValue(key string, value interface{}, propType string) (interface{}, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	switch propType {
+	case "integer":
+		n, err := strconv.Atoi(str)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s' must be an integer", key)
+		}
+		return n, nil
+	case "number":
+		n, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s' must be a number", key)
+		}
+		return n, nil
+	case "boolean":
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s' must be true or false", key)
+		}
+		return b, nil
+	}
+	return str, nil
+}
+
+func isSecretProperty(key string, property map[string]interface{}) bool {
+	if f, ok := property["format"].(string); ok && f == "password" {
+		return true
+	}
+	lower := strings.ToLower(key)
+	return strings.Contains(lower, "secret") || strings.Contains(lower, "password") || strings.Contains(lower, "private_key")
+}
+
+// parseKeyValues parses key=value arguments into a map
+func parseKeyValues(args []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid format '%s'. Use key=value", arg)
+		}
+		values[parts[0]] = parts[1]
+	}
+	return values, nil
+}
+
+// IdentityWhoamiCmd provides the whoami command for the identity service
+func IdentityWhoamiCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Show the identity of the current token",
+		Example: `  # Show the user and scope of the current token
+  $ cfctl identity whoami
+
+  # Show the role and effective permissions
+  $ cfctl identity whoami --permissions`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			withPermissions, _ := cmd.Flags().GetBool("permissions")
+
+			identity, err := transport.CurrentIdentity(withPermissions)
+			if err != nil {
+				return err
+			}
+
+			scope := "DOMAIN"
+			if identity.WorkspaceID != "" {
+				scope = "WORKSPACE"
+			}
+
+			data := [][]string{
+				{"Field", "Value"},
+				{"user_id", identity.UserID},
+				{"owner_type", identity.OwnerType},
+				{"domain_id", identity.DomainID},
+				{"workspace_id", identity.WorkspaceID},
+				{"scope", scope},
+				{"role_type", identity.RoleType},
+			}
+			if identity.ExpiresAt > 0 {
+				data = append(data, []string{"expires_at", time.Unix(int64(identity.ExpiresAt), 0).Format(time.RFC3339)})
+			}
+			if withPermissions {
+				data = append(data,
+					[]string{"role_id", identity.RoleID},
+					[]string{"role_name", identity.RoleName},
+				)
+			}
+			pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+
+			if withPermissions {
+				fmt.Println()
+				pterm.DefaultSection.Println("Permissions")
+				if len(identity.Permissions) == 0 {
+					pterm.Info.Println("The role has no explicit permissions (access is defined by its role type).")
+					return nil
+				}
+				permissions := append([]string{}, identity.Permissions...)
+				sort.Strings(permissions)
+				for _, permission := range permissions {
+					fmt.Printf("  %s\n", permission)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("permissions", false, "Show the bound role and its effective permissions")
+
+	return cmd
+}
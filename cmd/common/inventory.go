@@ -0,0 +1,200 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// InventoryCollectCmd provides the collect command for the inventory service
+func InventoryCollectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "collect <collector_id>",
+		Short: "Run a collector and optionally follow the resulting job",
+		Example: `  # Run a collector with all of its secrets
+  $ cfctl inventory collect collector-123456789012
+
+  # Run a collector for one secret and wait for the job to finish
+  $ cfctl inventory collect collector-123456789012 --secret secret-123456789012 --watch`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			collectorID := args[0]
+			secretID, _ := cmd.Flags().GetString("secret")
+			watch, _ := cmd.Flags().GetBool("watch")
+			interval, _ := cmd.Flags().GetDuration("interval")
+			parameters, _ := cmd.Flags().GetStringArray("parameter")
+
+			parameters = append(parameters, fmt.Sprintf("collector_id=%s", collectorID))
+			if secretID != "" {
+				parameters = append(parameters, fmt.Sprintf("secret_id=%s", secretID))
+			}
+
+			job, err := transport.FetchService("inventory", "collect", "Collector", &transport.FetchOptions{
+				Parameters: parameters,
+			})
+			if err != nil {
+				return err
+			}
+			if job == nil {
+				return fmt.Errorf("failed to run collector %s", collectorID)
+			}
+
+			jobID, _ := job["job_id"].(string)
+			pterm.Success.Printf("Collector %s started job %s\n", collectorID, jobID)
+
+			if !watch {
+				pterm.Info.Printf("Follow the job with: cfctl jobs tail %s\n", jobID)
+				return nil
+			}
+
+			return TailJob("inventory", jobID, interval)
+		},
+	}
+
+	cmd.Flags().String("secret", "", "Collect only with this secret id")
+	cmd.Flags().BoolP("watch", "w", false, "Wait for the job to finish and summarize the results")
+	cmd.Flags().Duration("interval", 3*time.Second, "Polling interval for --watch")
+	cmd.Flags().StringArrayP("parameter", "p", []string{}, "Additional collect parameter (-p <key>=<value> -p ...)")
+
+	return cmd
+}
+
+// summaryGroupFields maps the --group-by values of the inventory summary to cloud service fields
+var summaryGroupFields = map[string]string{
+	"provider":            "provider",
+	"region":              "region_code",
+	"cloud_service_type":  "cloud_service_type",
+	"cloud_service_group": "cloud_service_group",
+}
+
+// InventorySummaryCmd provides the summary command for the inventory service
+func InventorySummaryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "summary",
+		Short: "Count cloud services by provider, region or type",
+		Long: `Count the cloud services of the inventory with the analyze API, grouped by
+provider, region or cloud service type, together with the number created recently.`,
+		Example: `  # Count cloud services by provider
+  $ cfctl inventory summary
+
+  # Count cloud services by region and show the ones created in the last 30 days
+  $ cfctl inventory summary --group-by region --since 30d`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			groupBy, _ := cmd.Flags().GetString("group-by")
+			since, _ := cmd.Flags().GetString("since")
+
+			field, ok := summaryGroupFields[groupBy]
+			if !ok {
+				return fmt.Errorf("unsupported --group-by %s (use provider, region, cloud_service_type, cloud_service_group)", groupBy)
+			}
+			sinceTime, err := format.ParseTimeArg(since, time.Now())
+			if err != nil {
+				return fmt.Errorf("invalid --since: %v", err)
+			}
+
+			totals, err := analyzeCloudServices(field, nil)
+			if err != nil {
+				return err
+			}
+			recent, err := analyzeCloudServices(field, []interface{}{
+				map[string]interface{}{"k": "created_at", "v": sinceTime.UTC().Format(time.RFC3339), "o": "datetime_gte"},
+			})
+			if err != nil {
+				return err
+			}
+
+			total := 0
+			for _, count := range totals {
+				total += count
+			}
+			if total == 0 {
+				pterm.Info.Println("No cloud services found.")
+				return nil
+			}
+
+			groups := make([]string, 0, len(totals))
+			for group := range totals {
+				groups = append(groups, group)
+			}
+			sort.Slice(groups, func(i, j int) bool {
+				if totals[groups[i]] != totals[groups[j]] {
+					return totals[groups[i]] > totals[groups[j]]
+				}
+				return groups[i] < groups[j]
+			})
+
+			table := pterm.TableData{{groupBy, "Count", "Share", fmt.Sprintf("New (%s)", since)}}
+			for _, group := range groups {
+				name := group
+				if name == "" {
+					name = "-"
+				}
+				table = append(table, []string{
+					name,
+					fmt.Sprintf("%d", totals[group]),
+					fmt.Sprintf("%.1f%%", float64(totals[group])*100/float64(total)),
+					fmt.Sprintf("+%d", recent[group]),
+				})
+			}
+			table = append(table, []string{"Total", fmt.Sprintf("%d", total), "100.0%", ""})
+
+			return pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+		},
+	}
+
+	cmd.Flags().String("group-by", "provider", "Group by provider, region, cloud_service_type or cloud_service_group")
+	cmd.Flags().String("since", "7d", "Count the cloud services created since then as new (e.g. 7d, 24h, 2024-01-02)")
+
+	return cmd
+}
+
+// analyzeCloudServices counts the cloud services matching the filter by the values of a field
+func analyzeCloudServices(field string, filter []interface{}) (map[string]int, error) {
+	query := map[string]interface{}{
+		"group_by": []string{field},
+		"fields": map[string]interface{}{
+			"count": map[string]interface{}{"operator": "count"},
+		},
+	}
+	if len(filter) > 0 {
+		query["filter"] = filter
+	}
+	paramBytes, err := json.Marshal(map[string]interface{}{"query": query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal analyze query: %v", err)
+	}
+
+	resp, err := transport.FetchService("inventory", "analyze", "CloudService", &transport.FetchOptions{
+		JSONParameter: string(paramBytes),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("no response")
+	}
+
+	counts := make(map[string]int)
+	results, _ := resp["results"].([]interface{})
+	for _, result := range results {
+		row, ok := result.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		group := fmt.Sprintf("%v", row[field])
+		if row[field] == nil {
+			group = ""
+		}
+		if count, ok := row["count"].(float64); ok {
+			counts[group] += int(count)
+		}
+	}
+	return counts, nil
+}
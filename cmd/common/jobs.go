@@ -0,0 +1,149 @@
+package common
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+)
+
+// jobFinishedStatuses are the job statuses which end tailing
+var jobFinishedStatuses = map[string]bool{
+	"SUCCESS":  true,
+	"FAILURE":  true,
+	"CANCELED": true,
+	"TIMEOUT":  true,
+}
+
+// jobTaskCountFields are the resource counts reported by job tasks
+var jobTaskCountFields = []string{"created_count", "updated_count", "deleted_count", "disconnected_count", "failure_count"}
+
+// TailJob polls a job, rendering task progress and errors until it finishes
+func TailJob(serviceName, jobID string, interval time.Duration) error {
+	var progressbar *pterm.ProgressbarPrinter
+	seenTasks := make(map[string]bool)
+
+	for {
+		job, err := transport.FetchService(serviceName, "get", "Job", &transport.FetchOptions{
+			Parameters: []string{fmt.Sprintf("job_id=%s", jobID)},
+		})
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			return fmt.Errorf("failed to get job %s", jobID)
+		}
+
+		total := jobCount(job, "total_tasks")
+		remained := jobCount(job, "remained_tasks")
+		status, _ := job["status"].(string)
+
+		if progressbar == nil && total > 0 {
+			progressbar, _ = pterm.DefaultProgressbar.
+				WithTotal(total).
+				WithTitle(fmt.Sprintf("Job %s", jobID)).
+				Start()
+		}
+		if progressbar != nil {
+			if done := total - remained; done > progressbar.Current {
+				progressbar.Add(done - progressbar.Current)
+			}
+		}
+
+		printJobTaskErrors(serviceName, jobID, seenTasks)
+
+		if jobFinishedStatuses[status] {
+			if progressbar != nil {
+				progressbar.Stop()
+			}
+			printJobStatistics(serviceName, job)
+			if status != "SUCCESS" {
+				return fmt.Errorf("job %s finished with status %s", jobID, status)
+			}
+			return nil
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// printJobTaskErrors prints errors of failed tasks which were not printed yet
+func printJobTaskErrors(serviceName, jobID string, seenTasks map[string]bool) {
+	resp, err := transport.FetchService(serviceName, "list", "JobTask", &transport.FetchOptions{
+		Parameters: []string{fmt.Sprintf("job_id=%s", jobID), "status=FAILURE"},
+	})
+	if err != nil || resp == nil {
+		return
+	}
+
+	results, _ := resp["results"].([]interface{})
+	for _, result := range results {
+		task, ok := result.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		taskID, _ := task["job_task_id"].(string)
+		if seenTasks[taskID] {
+			continue
+		}
+		seenTasks[taskID] = true
+
+		errors, _ := task["errors"].([]interface{})
+		if len(errors) == 0 {
+			pterm.Error.Printf("Task %s failed\n", taskID)
+			continue
+		}
+		for _, e := range errors {
+			if errMap, ok := e.(map[string]interface{}); ok {
+				pterm.Error.Printf("Task %s: %v\n", taskID, errMap["message"])
+			} else {
+				pterm.Error.Printf("Task %s: %v\n", taskID, e)
+			}
+		}
+	}
+}
+
+// printJobStatistics prints the final statistics of a job and the resource counts of its tasks
+func printJobStatistics(serviceName string, job map[string]interface{}) {
+	data := [][]string{{"Field", "Value"}}
+	for _, field := range []string{"status", "total_tasks", "success_tasks", "failure_tasks", "created_at", "finished_at"} {
+		if val, ok := job[field]; ok {
+			data = append(data, []string{field, fmt.Sprintf("%v", val)})
+		}
+	}
+
+	jobID, _ := job["job_id"].(string)
+	resp, err := transport.FetchService(serviceName, "list", "JobTask", &transport.FetchOptions{
+		Parameters: []string{fmt.Sprintf("job_id=%s", jobID)},
+	})
+	if err == nil && resp != nil {
+		counts := make(map[string]int)
+		results, _ := resp["results"].([]interface{})
+		for _, result := range results {
+			if task, ok := result.(map[string]interface{}); ok {
+				for _, field := range jobTaskCountFields {
+					counts[field] += jobCount(task, field)
+				}
+			}
+		}
+		if len(results) > 0 {
+			for _, field := range jobTaskCountFields {
+				data = append(data, []string{field, fmt.Sprintf("%d", counts[field])})
+			}
+		}
+	}
+
+	pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+}
+
+func jobCount(job map[string]interface{}, field string) int {
+	switch v := job[field].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
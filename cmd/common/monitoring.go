@@ -0,0 +1,453 @@
+package common
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// logTimeFields and logSeverityFields are the fields checked to render log entries
+var (
+	logTimeFields     = []string{"timestamp", "time", "EventTime", "created_at"}
+	logSeverityFields = []string{"severity", "level", "log_level"}
+	logMessageFields  = []string{"message", "msg", "EventName", "event_name"}
+)
+
+// MonitoringLogsCmd provides the logs command for the monitoring service
+func MonitoringLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs <resource_id>",
+		Short: "Show logs of a resource",
+		Example: `  # Show logs of the last hour
+  $ cfctl monitoring logs server-123456789012 --data-source-id ds-123456789012
+
+  # Follow new log entries
+  $ cfctl monitoring logs server-123456789012 --data-source-id ds-123456789012 --since 10m --follow`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resourceID := args[0]
+			dataSourceID, _ := cmd.Flags().GetString("data-source-id")
+			since, _ := cmd.Flags().GetString("since")
+			follow, _ := cmd.Flags().GetBool("follow")
+			interval, _ := cmd.Flags().GetDuration("interval")
+
+			start, err := format.ParseTimeArg(since, time.Now())
+			if err != nil {
+				return err
+			}
+
+			for {
+				end := time.Now()
+				if err := printLogWindow(resourceID, dataSourceID, start, end); err != nil {
+					return err
+				}
+
+				if !follow {
+					return nil
+				}
+
+				// The next window starts where the previous one ended
+				start = end
+				time.Sleep(interval)
+			}
+		},
+	}
+
+	cmd.Flags().String("data-source-id", "", "Log data source id")
+	cmd.Flags().String("since", "1h", "Show logs since a relative duration (1h, 7d) or timestamp")
+	cmd.Flags().BoolP("follow", "F", false, "Follow new log entries")
+	cmd.Flags().Duration("interval", 5*time.Second, "Polling interval for --follow")
+
+	return cmd
+}
+
+// printLogWindow fetches and prints log entries of a resource in the given time window
+func printLogWindow(resourceID, dataSourceID string, start, end time.Time) error {
+	parameters := []string{
+		fmt.Sprintf("resource_id=%s", resourceID),
+		fmt.Sprintf("start=%s", start.UTC().Format(time.RFC3339)),
+		fmt.Sprintf("end=%s", end.UTC().Format(time.RFC3339)),
+	}
+	if dataSourceID != "" {
+		parameters = append(parameters, fmt.Sprintf("data_source_id=%s", dataSourceID))
+	}
+
+	resp, err := transport.FetchService("monitoring", "list", "Log", &transport.FetchOptions{
+		Parameters: parameters,
+	})
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return nil
+	}
+
+	entries, _ := resp["results"].([]interface{})
+	type logLine struct {
+		time  time.Time
+		entry map[string]interface{}
+	}
+	lines := make([]logLine, 0, len(entries))
+	for _, e := range entries {
+		if entry, ok := e.(map[string]interface{}); ok {
+			t, _ := lookupLogTime(entry)
+			lines = append(lines, logLine{time: t, entry: entry})
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].time.Before(lines[j].time)
+	})
+
+	for _, line := range lines {
+		printLogEntry(line.time, line.entry)
+	}
+
+	return nil
+}
+
+func printLogEntry(t time.Time, entry map[string]interface{}) {
+	timestamp := "-"
+	if !t.IsZero() {
+		timestamp = t.Local().Format("2006-01-02 15:04:05")
+	}
+
+	severity := lookupLogString(entry, logSeverityFields)
+	message := lookupLogString(entry, logMessageFields)
+	if message == "" {
+		jsonBytes, _ := json.Marshal(entry)
+		message = string(jsonBytes)
+	}
+
+	fmt.Printf("%s %s %s\n", pterm.FgGray.Sprint(timestamp), colorSeverity(severity), message)
+}
+
+func colorSeverity(severity string) string {
+	if severity == "" {
+		return ""
+	}
+
+	label := fmt.Sprintf("%-7s", strings.ToUpper(severity))
+	switch strings.ToUpper(severity) {
+	case "CRITICAL", "FATAL", "ERROR":
+		return pterm.FgRed.Sprint(label)
+	case "WARN", "WARNING":
+		return pterm.FgYellow.Sprint(label)
+	case "INFO", "NOTICE":
+		return pterm.FgCyan.Sprint(label)
+	default:
+		return pterm.FgGray.Sprint(label)
+	}
+}
+
+func lookupLogTime(entry map[string]interface{}) (time.Time, bool) {
+	for _, field := range logTimeFields {
+		if t, ok := format.ParseTimestamp(entry[field]); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func lookupLogString(entry map[string]interface{}, fields []string) string {
+	for _, field := range fields {
+		if val, ok := entry[field].(string); ok && val != "" {
+			return val
+		}
+	}
+	return ""
+}
+
+// MonitoringMetricCmd provides the metric command for the monitoring service
+func MonitoringMetricCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metric",
+		Short: "Query metric data of resources",
+	}
+
+	getCmd := &cobra.Command{
+		Use:   "get",
+		Short: "Show the time series of a metric",
+		Example: `  # Show the average cpu usage of the last hour in 5 minute steps
+  $ cfctl monitoring metric get --resource server-123456789012 --metric cpu --start -1h --step 5m
+
+  # Compare several resources as sparklines
+  $ cfctl monitoring metric get --resource server-a --resource server-b --metric cpu -o sparkline`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resources, _ := cmd.Flags().GetStringArray("resource")
+			metric, _ := cmd.Flags().GetString("metric")
+			dataSourceID, _ := cmd.Flags().GetString("data-source-id")
+			resourceType, _ := cmd.Flags().GetString("resource-type")
+			startArg, _ := cmd.Flags().GetString("start")
+			endArg, _ := cmd.Flags().GetString("end")
+			step, _ := cmd.Flags().GetDuration("step")
+			stat, _ := cmd.Flags().GetString("stat")
+			output, _ := cmd.Flags().GetString("output")
+
+			now := time.Now()
+			start, err := format.ParseTimeArg(startArg, now)
+			if err != nil {
+				return err
+			}
+			end, err := format.ParseTimeArg(endArg, now)
+			if err != nil {
+				return err
+			}
+
+			// Assemble the nested query message which is hard to express with -p
+			query := map[string]interface{}{
+				"resource_type": resourceType,
+				"resource_ids":  resources,
+				"metric":        metric,
+				"start":         start.UTC().Format(time.RFC3339),
+				"end":           end.UTC().Format(time.RFC3339),
+				"period":        int(step.Seconds()),
+				"stat":          stat,
+			}
+			if dataSourceID != "" {
+				query["data_source_id"] = dataSourceID
+			}
+			queryBytes, err := json.Marshal(query)
+			if err != nil {
+				return fmt.Errorf("failed to marshal metric query: %v", err)
+			}
+
+			resp, err := transport.FetchService("monitoring", "get_data", "Metric", &transport.FetchOptions{
+				JSONParameter: string(queryBytes),
+			})
+			if err != nil {
+				return err
+			}
+			if resp == nil {
+				return nil
+			}
+
+			labels, _ := resp["labels"].([]interface{})
+			values, _ := resp["resource_values"].(map[string]interface{})
+			series := make(map[string][]float64)
+			for _, resource := range resources {
+				points, _ := values[resource].([]interface{})
+				for _, point := range points {
+					v, _ := point.(float64)
+					series[resource] = append(series[resource], v)
+				}
+			}
+
+			switch output {
+			case "sparkline":
+				for _, resource := range resources {
+					fmt.Printf("%s %s\n", pterm.FgCyan.Sprintf("%-30s", resource), format.Sparkline(series[resource]))
+				}
+			case "csv":
+				writer := csv.NewWriter(os.Stdout)
+				writer.Write(append([]string{"time"}, resources...))
+				writer.WriteAll(metricRows(labels, resources, series, true))
+			case "table":
+				data := [][]string{append([]string{"time"}, resources...)}
+				data = append(data, metricRows(labels, resources, series, false)...)
+				pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+			default:
+				return fmt.Errorf("unsupported output format '%s' (use table, csv, sparkline)", output)
+			}
+
+			return nil
+		},
+	}
+
+	getCmd.Flags().StringArray("resource", []string{}, "Resource id (--resource <id> --resource ...)")
+	getCmd.Flags().String("metric", "", "Metric name (e.g. cpu)")
+	getCmd.Flags().String("data-source-id", "", "Metric data source id")
+	getCmd.Flags().String("resource-type", "inventory.Server", "Resource type of the resources")
+	getCmd.Flags().String("start", "-1h", "Start time as a relative duration (-1h, -7d) or timestamp")
+	getCmd.Flags().String("end", "now", "End time as a relative duration or timestamp")
+	getCmd.Flags().Duration("step", 5*time.Minute, "Period between data points")
+	getCmd.Flags().String("stat", "AVERAGE", "Statistic (AVERAGE, MAX, MIN, SUM)")
+	getCmd.Flags().StringP("output", "o", "table", "Output format (table, csv, sparkline)")
+	getCmd.MarkFlagRequired("resource")
+	getCmd.MarkFlagRequired("metric")
+
+	cmd.AddCommand(getCmd)
+	return cmd
+}
+
+// metricRows converts metric series to rows of time and one value per resource
+func metricRows(labels []interface{}, resources []string, series map[string][]float64, raw bool) [][]string {
+	rows := make([][]string, 0, len(labels))
+	for i, label := range labels {
+		timestamp := fmt.Sprintf("%v", label)
+		if t, ok := format.ParseTimestamp(label); ok && !raw {
+			timestamp = t.Local().Format("2006-01-02 15:04:05")
+		}
+
+		row := []string{timestamp}
+		for _, resource := range resources {
+			value := ""
+			if i < len(series[resource]) {
+				if raw {
+					value = fmt.Sprintf("%v", series[resource][i])
+				} else {
+					value = format.FormatNumber(series[resource][i])
+				}
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// alertTransitions maps alert subcommands to the state they set
+var alertTransitions = map[string]string{
+	"ack":     "ACKNOWLEDGED",
+	"resolve": "RESOLVED",
+}
+
+// MonitoringAlertCmd provides bulk alert state transitions for the monitoring service
+func MonitoringAlertCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alert",
+		Short: "Acknowledge or resolve alerts in bulk",
+	}
+
+	for _, action := range []string{"ack", "resolve"} {
+		cmd.AddCommand(alertTransitionCmd(action, alertTransitions[action]))
+	}
+
+	return cmd
+}
+
+func alertTransitionCmd(action, state string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s [alert_id...]", action),
+		Short: fmt.Sprintf("Set alerts to %s", state),
+		Example: fmt.Sprintf(`  # %[1]s alerts by id
+  $ cfctl monitoring alert %[1]s alert-123456789012 alert-210987654321
+
+  # %[1]s all triggered alerts of a project older than 7 days
+  $ cfctl monitoring alert %[1]s --query state=TRIGGERED,project_id=project-123456789012 --older-than 7d`, action),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query, _ := cmd.Flags().GetString("query")
+			olderThan, _ := cmd.Flags().GetString("older-than")
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			alerts, err := selectAlerts(args, query, olderThan)
+			if err != nil {
+				return err
+			}
+			if len(alerts) == 0 {
+				pterm.Info.Println("No alerts matched.")
+				return nil
+			}
+
+			// Confirmation summary
+			data := [][]string{{"alert_id", "title", "state", "created_at"}}
+			for _, alert := range alerts {
+				data = append(data, []string{
+					fmt.Sprintf("%v", alert["alert_id"]),
+					fmt.Sprintf("%v", alert["title"]),
+					fmt.Sprintf("%v", alert["state"]),
+					fmt.Sprintf("%v", alert["created_at"]),
+				})
+			}
+			pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+
+			if !yes {
+				confirmed := false
+				prompt := &survey.Confirm{
+					Message: fmt.Sprintf("Set %d alert(s) to %s?", len(alerts), state),
+				}
+				if err := survey.AskOne(prompt, &confirmed); err != nil {
+					return err
+				}
+				if !confirmed {
+					pterm.Info.Println("Cancelled.")
+					return nil
+				}
+			}
+
+			failed := 0
+			for _, alert := range alerts {
+				alertID := fmt.Sprintf("%v", alert["alert_id"])
+				_, err := transport.FetchService("monitoring", "update", "Alert", &transport.FetchOptions{
+					Parameters: []string{fmt.Sprintf("alert_id=%s", alertID), fmt.Sprintf("state=%s", state)},
+				})
+				if err != nil {
+					pterm.Error.Printf("Failed to update %s: %v\n", alertID, err)
+					failed++
+				}
+			}
+
+			pterm.Success.Printf("%d alert(s) set to %s\n", len(alerts)-failed, state)
+			if failed > 0 {
+				return fmt.Errorf("%d alert(s) failed to update", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("query", "", "Select alerts by fields (--query state=TRIGGERED,project_id=<id>)")
+	cmd.Flags().String("older-than", "", "Select alerts created before a duration ago (e.g. 7d, 12h)")
+	cmd.Flags().BoolP("yes", "y", false, "Skip the confirmation")
+
+	return cmd
+}
+
+// selectAlerts gets alerts by id or lists alerts matching the query
+func selectAlerts(ids []string, query, olderThan string) ([]map[string]interface{}, error) {
+	var filters []interface{}
+	if len(ids) > 0 {
+		filters = append(filters, map[string]interface{}{"k": "alert_id", "v": ids, "o": "in"})
+	}
+	if query != "" {
+		for _, condition := range strings.Split(query, ",") {
+			parts := strings.SplitN(condition, "=", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid query '%s'. Use key=value[,key=value]", condition)
+			}
+			filters = append(filters, map[string]interface{}{"k": parts[0], "v": parts[1], "o": "eq"})
+		}
+	}
+	if olderThan != "" {
+		before, err := format.ParseTimeArg(olderThan, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, map[string]interface{}{"k": "created_at", "v": before.UTC().Format(time.RFC3339), "o": "datetime_lt"})
+	}
+	if len(filters) == 0 {
+		return nil, fmt.Errorf("give alert ids or select alerts with --query/--older-than")
+	}
+
+	paramBytes, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{"filter": filters},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal alert query: %v", err)
+	}
+
+	resp, err := transport.FetchService("monitoring", "list", "Alert", &transport.FetchOptions{
+		JSONParameter: string(paramBytes),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []map[string]interface{}
+	if results, ok := resp["results"].([]interface{}); ok {
+		for _, result := range results {
+			if alert, ok := result.(map[string]interface{}); ok {
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+	return alerts, nil
+}
@@ -0,0 +1,112 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NotificationSendTestCmd provides the send-test command for the notification service
+func NotificationSendTestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "send-test",
+		Short: "Send a test notification through a protocol",
+		Example: `  # Send a test message through the channel of a project
+  $ cfctl notification send-test --channel pc-123456789012 --message "Hello from cfctl"
+
+  # Send a test message through a protocol with explicit channel data
+  $ cfctl notification send-test --protocol protocol-123456789012 -d webhook_url=https://hooks.slack.com/... --message "test"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			protocolID, _ := cmd.Flags().GetString("protocol")
+			channelID, _ := cmd.Flags().GetString("channel")
+			message, _ := cmd.Flags().GetString("message")
+			dataArgs, _ := cmd.Flags().GetStringArray("data")
+			level, _ := cmd.Flags().GetString("level")
+
+			data := make(map[string]interface{})
+
+			// Use the protocol and data of an existing channel
+			if channelID != "" {
+				channel, err := transport.FetchService("notification", "get", "ProjectChannel", &transport.FetchOptions{
+					Parameters: []string{fmt.Sprintf("project_channel_id=%s", channelID)},
+				})
+				if err != nil {
+					return err
+				}
+				if channel == nil {
+					return fmt.Errorf("failed to get channel %s", channelID)
+				}
+				if protocolID == "" {
+					protocolID, _ = channel["protocol_id"].(string)
+				}
+				if channelData, ok := channel["data"].(map[string]interface{}); ok {
+					data = channelData
+				}
+			}
+
+			if protocolID == "" {
+				return fmt.Errorf("either --protocol or --channel is required")
+			}
+
+			for _, arg := range dataArgs {
+				parts := strings.SplitN(arg, "=", 2)
+				if len(parts) != 2 {
+					return fmt.Errorf("invalid data format '%s'. Use key=value", arg)
+				}
+				data[parts[0]] = parts[1]
+			}
+
+			protocol, err := transport.FetchService("notification", "get", "Protocol", &transport.FetchOptions{
+				Parameters: []string{fmt.Sprintf("protocol_id=%s", protocolID)},
+			})
+			if err != nil {
+				return err
+			}
+			if protocol == nil {
+				return fmt.Errorf("failed to get protocol %s", protocolID)
+			}
+			pterm.Info.Printf("Protocol: %v (%v)\n", protocol["name"], protocol["state"])
+
+			params := map[string]interface{}{
+				"protocol_id":        protocolID,
+				"data":               data,
+				"notification_type":  "INFO",
+				"notification_level": level,
+				"message": map[string]interface{}{
+					"title":       "[cfctl] Test notification",
+					"description": message,
+					"occurred_at": time.Now().UTC().Format(time.RFC3339),
+				},
+			}
+			paramBytes, err := json.Marshal(params)
+			if err != nil {
+				return fmt.Errorf("failed to marshal notification: %v", err)
+			}
+
+			start := time.Now()
+			_, err = transport.FetchService("notification", "push", "Notification", &transport.FetchOptions{
+				JSONParameter: string(paramBytes),
+			})
+			if err != nil {
+				pterm.Error.Printf("Delivery failed: %v\n", err)
+				return err
+			}
+
+			pterm.Success.Printf("Test notification delivered through %s in %s\n", protocolID, time.Since(start).Round(time.Millisecond))
+			return nil
+		},
+	}
+
+	cmd.Flags().String("protocol", "", "Protocol id to send through")
+	cmd.Flags().String("channel", "", "Project channel id whose protocol and data are used")
+	cmd.Flags().StringP("message", "m", "This is a test notification sent by cfctl.", "Message body")
+	cmd.Flags().StringArrayP("data", "d", []string{}, "Channel data for the protocol (-d key=value -d ...)")
+	cmd.Flags().String("level", "ALL", "Notification level")
+
+	return cmd
+}
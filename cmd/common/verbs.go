@@ -0,0 +1,50 @@
+package common
+
+import (
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+)
+
+// verbAliases map verbs of kubectl and other CLIs to the standard verbs of SpaceONE
+var verbAliases = map[string]string{
+	"describe": "get",
+	"ls":       "list",
+	"rm":       "delete",
+	"del":      "delete",
+	"edit":     "update",
+}
+
+// ResolveVerb returns the standard verb of an aliased verb like ls or rm.
+// Verbs which are methods of the service or aliases of the setting file are kept,
+// so that a service with its own describe or edit method is called as it is.
+func ResolveVerb(serviceName, verb, resource string) string {
+	standard, ok := verbAliases[verb]
+	if !ok {
+		return verb
+	}
+
+	if aliases, err := configs.ListAliases(); err == nil {
+		if serviceAliases, ok := aliases[serviceName].(map[string]interface{}); ok {
+			if _, ok := serviceAliases[verb]; ok {
+				return verb
+			}
+		}
+	}
+
+	methods, err := ServiceMethods(serviceName)
+	if err != nil {
+		return standard
+	}
+
+	candidates := methods[resource]
+	if candidates == nil {
+		for _, resourceMethods := range methods {
+			candidates = append(candidates, resourceMethods...)
+		}
+	}
+	for _, method := range candidates {
+		if method.Verb == verb {
+			return verb
+		}
+	}
+	return standard
+}
@@ -3,6 +3,7 @@
 package other
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -11,7 +12,7 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
 	"github.com/cloudforet-io/cfctl/pkg/format"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -20,28 +21,7 @@ import (
 	"github.com/pterm/pterm"
 )
 
-var endpoints string
-
-func loadEndpointsFromCache(currentEnv string) (map[string]string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("unable to find home directory: %v", err)
-	}
-
-	// Read from environment-specific cache file
-	cacheFile := filepath.Join(home, ".cfctl", "cache", currentEnv, "endpoints.yaml")
-	data, err := os.ReadFile(cacheFile)
-	if err != nil {
-		return nil, err
-	}
-
-	var endpoints map[string]string
-	if err := yaml.Unmarshal(data, &endpoints); err != nil {
-		return nil, err
-	}
-
-	return endpoints, nil
-}
+var apiResourceServices string
 
 var ApiResourcesCmd = &cobra.Command{
 	Use:   "api_resources",
@@ -53,8 +33,17 @@ var ApiResourcesCmd = &cobra.Command{
   $ cfctl api_resources -s identity
 
   # List API resources for multiple services
-  $ cfctl api_resources -s identity,inventory,repository`,
+  $ cfctl api_resources -s identity,inventory,repository
+
+  # List API resources of every service sorted by resource
+  $ cfctl api_resources --all --sort-by resource`,
 	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		if all && apiResourceServices != "" {
+			pterm.Error.Println("--all cannot be used together with --service")
+			return
+		}
+
 		home, err := os.UserHomeDir()
 		if err != nil {
 			log.Fatalf("Unable to find home directory: %v", err)
@@ -82,19 +71,20 @@ var ApiResourcesCmd = &cobra.Command{
 			return
 		}
 
-		// Try to load endpoints from cache first
-		endpointsMap, err := loadEndpointsFromCache(currentEnv)
+		// Use the cached endpoints of the environment or fetch them from the identity service
+		endpointName, ok := envConfig["endpoint"].(string)
+		if !ok || endpointName == "" {
+			return
+		}
+
+		resolver, err := endpoints.NewResolver(currentEnv, endpointName, mainV.GetStringMapString(fmt.Sprintf("environments.%s.endpoints", currentEnv)))
 		if err != nil {
-			// If cache loading fails, fall back to fetching from identity service
-			endpointName, ok := envConfig["endpoint"].(string)
-			if !ok || endpointName == "" {
-				return
-			}
+			log.Fatalf("Failed to resolve endpoints of '%s': %v", endpointName, err)
+		}
 
-			endpointsMap, err = configs.FetchEndpointsMap(endpointName)
-			if err != nil {
-				log.Fatalf("Failed to fetch endpointsMap from '%s': %v", endpointName, err)
-			}
+		endpointsMap, err := resolver.Endpoints()
+		if err != nil {
+			log.Fatalf("Failed to fetch endpointsMap from '%s': %v", endpointName, err)
 		}
 
 		// Load short names configuration
@@ -114,8 +104,8 @@ var ApiResourcesCmd = &cobra.Command{
 		}
 
 		// Process endpoints provided via flag
-		if endpoints != "" {
-			selectedEndpoints := strings.Split(endpoints, ",")
+		if apiResourceServices != "" {
+			selectedEndpoints := strings.Split(apiResourceServices, ",")
 			for i := range selectedEndpoints {
 				selectedEndpoints[i] = strings.TrimSpace(selectedEndpoints[i])
 			}
@@ -129,7 +119,10 @@ var ApiResourcesCmd = &cobra.Command{
 				}
 
 				result, err := format.FetchServiceResources(endpointName, serviceEndpoint, shortNamesMap)
-				if err != nil {
+				var unresolved *format.UnresolvedServicesError
+				if errors.As(err, &unresolved) {
+					pterm.Warning.Printf("Skipped services of %s: %v\n", endpointName, err)
+				} else if err != nil {
 					log.Printf("Error processing service %s: %v", endpointName, err)
 					continue
 				}
@@ -137,53 +130,104 @@ var ApiResourcesCmd = &cobra.Command{
 				allData = append(allData, result...)
 			}
 
-			sort.Slice(allData, func(i, j int) bool {
-				return allData[i][0] < allData[j][0]
-			})
+			sortBy, _ := cmd.Flags().GetString("sort-by")
+			if err := sortAPIResources(allData, sortBy); err != nil {
+				pterm.Error.Println(err)
+				return
+			}
 
 			renderTable(allData)
 			return
 		}
 
 		// If no specific endpoints are provided, list all services
-		var wg sync.WaitGroup
-		dataChan := make(chan [][]string, len(endpointsMap))
-		errorChan := make(chan error, len(endpointsMap))
-
-		for service, endpoint := range endpointsMap {
-			wg.Add(1)
-			go func(service, endpoint string) {
-				defer wg.Done()
-				result, err := format.FetchServiceResources(service, endpoint, shortNamesMap)
-				if err != nil {
-					errorChan <- fmt.Errorf("Error processing service %s: %v", service, err)
-					return
-				}
-				dataChan <- result
-			}(service, endpoint)
+		allData, unreachable := fetchAllServiceResources(endpointsMap, shortNamesMap)
+
+		sortBy, _ := cmd.Flags().GetString("sort-by")
+		if err := sortAPIResources(allData, sortBy); err != nil {
+			pterm.Error.Println(err)
+			return
 		}
 
-		wg.Wait()
-		close(dataChan)
-		close(errorChan)
+		renderTable(allData)
+		renderUnreachableServices(unreachable)
+	},
+}
 
-		if len(errorChan) > 0 {
-			for err := range errorChan {
-				log.Println(err)
+// fetchAllServiceResources fetches the resources of all services concurrently.
+// Services which could not be reached are returned with their error instead of failing the listing.
+func fetchAllServiceResources(endpointsMap, shortNamesMap map[string]string) ([][]string, map[string]error) {
+	var (
+		wg          sync.WaitGroup
+		mu          sync.Mutex
+		allData     [][]string
+		unreachable = make(map[string]error)
+	)
+
+	progress := format.NewProgress("Fetching API resources", len(endpointsMap))
+	for service, endpoint := range endpointsMap {
+		wg.Add(1)
+		go func(service, endpoint string) {
+			defer wg.Done()
+			result, err := format.FetchServiceResources(service, endpoint, shortNamesMap)
+
+			mu.Lock()
+			defer mu.Unlock()
+			var unresolved *format.UnresolvedServicesError
+			if errors.As(err, &unresolved) {
+				// The resources which were resolved are listed and the others reported after the table
+				unreachable[service] = err
+			} else if err != nil {
+				unreachable[service] = err
+				progress.Fail(service, err)
+				return
 			}
-		}
+			allData = append(allData, result...)
+			progress.Succeed()
+		}(service, endpoint)
+	}
+
+	wg.Wait()
+	progress.Stop()
+	return allData, unreachable
+}
 
-		var allData [][]string
-		for data := range dataChan {
-			allData = append(allData, data...)
+// sortAPIResources sorts the resource rows by service, resource or verb
+func sortAPIResources(data [][]string, sortBy string) error {
+	column := map[string]int{"": 0, "service": 0, "verb": 1, "resource": 2}
+	index, ok := column[sortBy]
+	if !ok {
+		return fmt.Errorf("unsupported sort key: %s (use service, resource or verb)", sortBy)
+	}
+
+	sort.SliceStable(data, func(i, j int) bool {
+		if data[i][index] != data[j][index] {
+			return data[i][index] < data[j][index]
 		}
+		return data[i][0]+data[i][2] < data[j][0]+data[j][2]
+	})
+	return nil
+}
 
-		sort.Slice(allData, func(i, j int) bool {
-			return allData[i][0] < allData[j][0]
-		})
+// renderUnreachableServices lists the services whose resources could not be fetched
+func renderUnreachableServices(unreachable map[string]error) {
+	if len(unreachable) == 0 {
+		return
+	}
 
-		renderTable(allData)
-	},
+	services := make([]string, 0, len(unreachable))
+	for service := range unreachable {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	table := pterm.TableData{{"Service", "Error"}}
+	for _, service := range services {
+		table = append(table, []string{service, unreachable[service].Error()})
+	}
+
+	pterm.Warning.Printf("%d of the services were unreachable\n", len(unreachable))
+	pterm.DefaultTable.WithHasHeader().WithData(table).Render()
 }
 
 func renderTable(data [][]string) {
@@ -269,5 +313,7 @@ func splitIntoLinesWithComma(text string, maxWidth int) []string {
 }
 
 func init() {
-	ApiResourcesCmd.Flags().StringVarP(&endpoints, "service", "s", "", "Specify the services to connect to, separated by commas (e.g., 'identity', 'identity,inventory')")
+	ApiResourcesCmd.Flags().StringVarP(&apiResourceServices, "service", "s", "", "Specify the services to connect to, separated by commas (e.g., 'identity', 'identity,inventory')")
+	ApiResourcesCmd.Flags().Bool("all", false, "List the resources of every service in the endpoints cache concurrently")
+	ApiResourcesCmd.Flags().String("sort-by", "service", "Sort the resources by service, resource or verb")
 }
@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/cloudforet-io/cfctl/pkg/format"
 	"github.com/cloudforet-io/cfctl/pkg/transport"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
@@ -64,7 +65,23 @@ var ApplyCmd = &cobra.Command{
         role_id: role-456
 
   # 02. Apply the configuration
-  cfctl apply -f test.yaml`,
+  cfctl apply -f test.yaml
+
+  # Apply the remaining resources when one fails and report the failures
+  cfctl apply -f test.yaml --continue-on-error
+
+  # Render the file as Go template for an environment
+  name: {{ .Values.env }}-workspace-group
+  cfctl apply -f test.yaml --values prd.yaml --set env=prd
+
+  # Keep credentials out of the file with secret references
+  secret_data:
+    password:
+      valueFrom:
+        vault: secret/data/db#password
+
+  # Review the plan and save it for CI without applying anything
+  cfctl apply -f test.yaml --dry-run --plan-out plan.json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		filename, _ := cmd.Flags().GetString("filename")
 		if filename == "" {
@@ -77,17 +94,56 @@ var ApplyCmd = &cobra.Command{
 			return fmt.Errorf("failed to read file: %v", err)
 		}
 
+		values, err := manifestValues(cmd)
+		if err != nil {
+			return err
+		}
+		data, err = renderManifest(filename, data, values)
+		if err != nil {
+			return err
+		}
+
 		// Parse all resource specs
 		resources, err := parseResourceSpecs(data)
 		if err != nil {
 			return err
 		}
 
+		continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		planOut, _ := cmd.Flags().GetString("plan-out")
+		useLock, _ := cmd.Flags().GetBool("lock")
+		forceUnlock, _ := cmd.Flags().GetBool("force-unlock")
+
+		// Keep other runs from changing the environment until this one is done
+		if useLock && !dryRun {
+			lock, err := acquireLock("apply", forceUnlock)
+			if err != nil {
+				return err
+			}
+			defer lock.release()
+		}
+
+		// Show what will change before anything is mutated
+		plan, err := applyPlan(resources)
+		if err != nil {
+			return err
+		}
+		printPlan(plan)
+		if planOut != "" {
+			if err := writePlanFile(planOut, plan); err != nil {
+				return err
+			}
+		}
+		if dryRun {
+			return nil
+		}
+
 		// Process each resource sequentially
+		progress := format.NewProgress("Applying resources", len(resources))
 		var lastResponse map[string]interface{}
 		for i, resource := range resources {
-			pterm.Info.Printf("Applying resource %d/%d: %s/%s\n",
-				i+1, len(resources), resource.Service, resource.Resource)
+			item := fmt.Sprintf("%d/%s/%s/%s", i+1, resource.Service, resource.Verb, resource.Resource)
 
 			// Convert spec to parameters
 			parameters := convertSpecToParameters(resource.Spec, lastResponse)
@@ -98,18 +154,73 @@ var ApplyCmd = &cobra.Command{
 
 			response, err := transport.FetchService(resource.Service, resource.Verb, resource.Resource, options)
 			if err != nil {
+				progress.Fail(item, err)
+				if continueOnError {
+					continue
+				}
+				progress.Stop()
 				pterm.Error.Printf("Failed to apply resource %d/%d: %v\n", i+1, len(resources), err)
 				return err
 			}
 
 			lastResponse = response
-			pterm.Success.Printf("Resource %d/%d applied successfully\n", i+1, len(resources))
+			progress.Succeed()
 		}
 
+		summary := progress.Stop()
+		if summary.Failed > 0 {
+			summary.PrintPartialFailure()
+			return fmt.Errorf("%d of %d resources failed to apply", summary.Failed, summary.Total)
+		}
+
+		pterm.Success.Printf("%d resources applied successfully\n", summary.Succeeded)
 		return nil
 	},
 }
 
+// applyPlan describes the calls of the resource specs as a plan. Updates are compared with the
+// current resources, values referring to earlier responses are known after apply only.
+func applyPlan(resources []ResourceSpec) ([]planAction, error) {
+	plan := make([]planAction, 0, len(resources))
+	for _, resource := range resources {
+		spec, err := normalizeSpec(resource.Spec)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range spec {
+			if v, ok := value.(string); ok && strings.HasPrefix(v, "${") && strings.HasSuffix(v, "}") {
+				spec[key] = planUnknown
+			}
+		}
+
+		idField := transport.ResourceIDField(resource.Resource)
+		id, _ := spec[idField].(string)
+		name, _ := spec["name"].(string)
+		action := planAction{Action: resource.Verb, Service: resource.Service, Resource: resource.Resource, ID: id, Name: name}
+		if id == planUnknown {
+			action.ID = ""
+		}
+
+		switch {
+		case resource.Verb == planDelete:
+		case resource.Verb == planUpdate && action.ID != "":
+			current, err := transport.FetchService(resource.Service, "get", resource.Resource, &transport.FetchOptions{
+				Parameters: []string{fmt.Sprintf("%s=%s", idField, id)},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s %s to plan its update: %v", resource.Resource, id, err)
+			}
+			delete(spec, idField)
+			action.Changes = planChanges(current, spec)
+		default:
+			delete(spec, idField)
+			action.Changes = planChanges(nil, spec)
+		}
+		plan = append(plan, action)
+	}
+	return plan, nil
+}
+
 func convertSpecToParameters(spec map[string]interface{}, lastResponse map[string]interface{}) []string {
 	var parameters []string
 
@@ -165,5 +276,11 @@ func getValueFromPath(data map[string]interface{}, path string) string {
 
 func init() {
 	ApplyCmd.Flags().StringP("filename", "f", "", "Filename to use to apply the resource")
+	ApplyCmd.Flags().Bool("continue-on-error", false, "Apply the remaining resources when a resource fails")
+	ApplyCmd.Flags().Bool("dry-run", false, "Print the plan without applying anything")
+	ApplyCmd.Flags().String("plan-out", "", "Write the plan as JSON to this file")
+	ApplyCmd.Flags().Bool("lock", false, "Lock the environment so that other apply and sync runs are refused until this one is done")
+	ApplyCmd.Flags().Bool("force-unlock", false, "Remove the lock left behind by an interrupted run before locking")
+	addManifestValueFlags(ApplyCmd)
 	ApplyCmd.MarkFlagRequired("filename")
 }
@@ -0,0 +1,110 @@
+package other
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// BenchmarkCmd represents the benchmark command
+var BenchmarkCmd = &cobra.Command{
+	Use:   "benchmark <service> <verb> <resource>",
+	Short: "Measure the latency of an API method",
+	Long: `Call an API method repeatedly against the current environment and report
+latency percentiles, error rate and throughput. Calls share a single connection.`,
+	Example: `  # Call identity.Workspace.list 100 times with 10 concurrent callers
+  $ cfctl benchmark identity list Workspace --requests 100 --concurrency 10
+
+  # Benchmark a get call and print the result as JSON
+  $ cfctl benchmark inventory get CloudService -p cloud_service_id=cloud-svc-123 -o json`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		requests, _ := cmd.Flags().GetInt("requests")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		parameters, _ := cmd.Flags().GetStringArray("parameter")
+		jsonParameter, _ := cmd.Flags().GetString("json-parameter")
+		admin, _ := cmd.Flags().GetBool("admin")
+		output, _ := cmd.Flags().GetString("output")
+
+		options := &transport.FetchOptions{
+			Parameters:    parameters,
+			JSONParameter: jsonParameter,
+			Admin:         admin,
+		}
+
+		if output != "json" {
+			pterm.Info.Printf("Sending %d requests to %s.%s.%s with %d concurrent callers\n", requests, args[0], args[2], args[1], concurrency)
+		}
+
+		result, err := transport.Benchmark(args[0], args[1], args[2], options, requests, concurrency)
+		if err != nil {
+			return err
+		}
+
+		switch output {
+		case "json":
+			data, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal result: %v", err)
+			}
+			fmt.Println(string(data))
+		case "table":
+			renderBenchmarkResult(result)
+		default:
+			return fmt.Errorf("unsupported output format: %s (use table or json)", output)
+		}
+
+		return nil
+	},
+}
+
+// renderBenchmarkResult prints the statistics of a benchmark run
+func renderBenchmarkResult(result *transport.BenchmarkResult) {
+	table := pterm.TableData{
+		{"Metric", "Value"},
+		{"Method", result.Method},
+		{"Requests", fmt.Sprintf("%d", result.Requests)},
+		{"Concurrency", fmt.Sprintf("%d", result.Concurrency)},
+		{"Duration", result.Duration.Round(time.Millisecond).String()},
+		{"Throughput", fmt.Sprintf("%.1f req/s", result.Throughput)},
+		{"Errors", fmt.Sprintf("%d (%.1f%%)", result.Errors, result.ErrorRate*100)},
+		{"Min", result.Min.Round(time.Microsecond).String()},
+		{"p50", result.P50.Round(time.Microsecond).String()},
+		{"p90", result.P90.Round(time.Microsecond).String()},
+		{"p95", result.P95.Round(time.Microsecond).String()},
+		{"p99", result.P99.Round(time.Microsecond).String()},
+		{"Max", result.Max.Round(time.Microsecond).String()},
+	}
+	pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+
+	if len(result.ErrorCodes) == 0 {
+		return
+	}
+
+	codes := make([]string, 0, len(result.ErrorCodes))
+	for code := range result.ErrorCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	errorTable := pterm.TableData{{"Status Code", "Count"}}
+	for _, code := range codes {
+		errorTable = append(errorTable, []string{code, fmt.Sprintf("%d", result.ErrorCodes[code])})
+	}
+	fmt.Println()
+	pterm.DefaultTable.WithHasHeader().WithData(errorTable).Render()
+}
+
+func init() {
+	BenchmarkCmd.Flags().IntP("requests", "n", 100, "Total number of requests")
+	BenchmarkCmd.Flags().IntP("concurrency", "c", 10, "Number of concurrent callers")
+	BenchmarkCmd.Flags().StringArrayP("parameter", "p", []string{}, "Input Parameter (-p <key>=<value> -p ...)")
+	BenchmarkCmd.Flags().StringP("json-parameter", "j", "", "JSON type parameter")
+	BenchmarkCmd.Flags().Bool("admin", false, "Call the API in admin mode with a domain scope token (or set 'mode: admin' in the environment)")
+	BenchmarkCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
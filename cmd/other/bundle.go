@@ -0,0 +1,199 @@
+package other
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// secretArgFlags are the flags whose values are left out of the command line in diagnostic bundles
+var secretArgFlags = []string{"-p", "--parameter", "-j", "--json-parameter", "-H", "--header", "--set"}
+
+var debugBundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Write a diagnostic bundle to attach to bug reports",
+	Long: `Write a zip file with the cfctl and Go version, the setting file without tokens
+and passwords, the files of the cache directory and the last request to ~/.cfctl/diagnostics/.
+The same bundle is written automatically when cfctl crashes.`,
+	Example: `  # Write a diagnostic bundle for a bug report
+  $ cfctl debug bundle`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := WriteDiagnosticBundle("requested with cfctl debug bundle", nil)
+		if err != nil {
+			return err
+		}
+		pterm.Success.Printf("Diagnostic bundle written to %s\n", path)
+		return nil
+	},
+}
+
+// HandlePanic writes a diagnostic bundle for a recovered panic, points to it in a single line
+// and exits. It is called from the top-level recover of the command.
+func HandlePanic(recovered interface{}) {
+	stack := debug.Stack()
+	path, err := WriteDiagnosticBundle(fmt.Sprintf("panic: %v", recovered), stack)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cfctl crashed: %v\n%s", recovered, stack)
+		os.Exit(2)
+	}
+	fmt.Fprintf(os.Stderr, "cfctl crashed: %v (diagnostics written to %s, please attach it to a bug report)\n", recovered, path)
+	os.Exit(2)
+}
+
+// WriteDiagnosticBundle writes a zip file to ~/.cfctl/diagnostics/ with the environment of the process,
+// the stack trace if given, the sanitized setting file, the state of the cache and the last request.
+// It returns the path of the bundle.
+func WriteDiagnosticBundle(reason string, stack []byte) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %v", err)
+	}
+	dir := filepath.Join(home, ".cfctl", "diagnostics")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create diagnostics directory: %v", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("cfctl-%s.zip", time.Now().Format("20060102-150405")))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create diagnostic bundle: %v", err)
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	entries := map[string]interface{}{
+		"info.json":         diagnosticInfo(reason),
+		"setting.yaml":      sanitizedSettings(),
+		"cache.json":        cacheState(filepath.Join(home, ".cfctl", "cache")),
+		"last_request.json": transport.LastRequest(),
+	}
+	if stack != nil {
+		entries["stack.txt"] = string(stack)
+	}
+
+	for name, content := range entries {
+		writer, err := archive.Create(name)
+		if err != nil {
+			return "", fmt.Errorf("failed to add %s to diagnostic bundle: %v", name, err)
+		}
+
+		var data []byte
+		switch {
+		case strings.HasSuffix(name, ".txt"):
+			data = []byte(content.(string))
+		case strings.HasSuffix(name, ".yaml"):
+			data, err = yaml.Marshal(content)
+		default:
+			data, err = json.MarshalIndent(content, "", "  ")
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to encode %s: %v", name, err)
+		}
+		if _, err := writer.Write(data); err != nil {
+			return "", fmt.Errorf("failed to write %s: %v", name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return "", fmt.Errorf("failed to write diagnostic bundle: %v", err)
+	}
+	return path, nil
+}
+
+// diagnosticInfo describes the process and the command line without secret flag values
+func diagnosticInfo(reason string) map[string]interface{} {
+	info := map[string]interface{}{
+		"reason":  reason,
+		"time":    time.Now().Format(time.RFC3339),
+		"go":      runtime.Version(),
+		"os":      runtime.GOOS,
+		"arch":    runtime.GOARCH,
+		"command": sanitizeArgs(os.Args[1:]),
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info["version"] = build.Main.Version
+	}
+	return info
+}
+
+// sanitizeArgs replaces the values of flags which may carry secrets, e.g. -p password=...
+func sanitizeArgs(args []string) []string {
+	sanitized := make([]string, len(args))
+	redactNext := false
+	for i, arg := range args {
+		sanitized[i] = arg
+		if redactNext {
+			sanitized[i] = transport.RedactedValue
+			redactNext = false
+			continue
+		}
+		for _, flag := range secretArgFlags {
+			if arg == flag {
+				redactNext = true
+			} else if strings.HasPrefix(arg, flag+"=") {
+				sanitized[i] = flag + "=" + transport.RedactedValue
+			}
+		}
+	}
+	return sanitized
+}
+
+// sanitizedSettings returns the setting file with the values of secret keys replaced
+func sanitizedSettings() interface{} {
+	settingPath, err := configs.GetSettingFilePath()
+	if err != nil {
+		return map[string]string{"error": err.Error()}
+	}
+	data, err := os.ReadFile(settingPath)
+	if err != nil {
+		return map[string]string{"error": err.Error()}
+	}
+
+	var settings interface{}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return map[string]string{"error": fmt.Sprintf("failed to parse setting file: %v", err)}
+	}
+	return transport.RedactSecrets(settings)
+}
+
+// cacheFile describes a file of the cache directory without its content
+type cacheFile struct {
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+// cacheState lists the files of the cache directory, which hold tokens and are not copied
+func cacheState(dir string) interface{} {
+	var files []cacheFile
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files = append(files, cacheFile{Path: rel, Size: info.Size(), Modified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return map[string]string{"error": err.Error()}
+	}
+	return files
+}
@@ -0,0 +1,174 @@
+package other
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// CompletionInstallCmd returns the command installing the completion script of the root command
+func CompletionInstallCmd(root *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install the autocompletion script for your shell",
+		Long: `Detect your shell (bash, zsh, fish or powershell), write the completion script
+to the location the shell loads completions from and, where needed, enable it
+in the shell profile. Verbs and resources of services are completed from the
+API descriptors of the current environment.
+
+Open a new shell after installing to use the completion.`,
+		Example: `  # Install the completion for the current shell
+  $ cfctl completion install
+
+  # Install the completion for zsh
+  $ cfctl completion install --shell zsh`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shell, _ := cmd.Flags().GetString("shell")
+			if shell == "" {
+				shell = detectShell()
+				if shell == "" {
+					return fmt.Errorf("unable to detect your shell, please set it with --shell (bash, zsh, fish, powershell)")
+				}
+			}
+
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("unable to find home directory: %v", err)
+			}
+
+			var script bytes.Buffer
+			var path, profile, profileLine string
+
+			switch shell {
+			case "bash":
+				err = root.GenBashCompletionV2(&script, true)
+				path = filepath.Join(dataHome(home), "bash-completion", "completions", root.Name())
+			case "zsh":
+				err = root.GenZshCompletion(&script)
+				dir := filepath.Join(home, ".zsh", "completions")
+				path = filepath.Join(dir, "_"+root.Name())
+				profile = filepath.Join(zshHome(home), ".zshrc")
+				profileLine = fmt.Sprintf("fpath=(%s $fpath)\nautoload -Uz compinit && compinit", dir)
+			case "fish":
+				err = root.GenFishCompletion(&script, true)
+				path = filepath.Join(configHome(home), "fish", "completions", root.Name()+".fish")
+			case "powershell":
+				err = root.GenPowerShellCompletionWithDesc(&script)
+				profileDir := filepath.Join(configHome(home), "powershell")
+				if runtime.GOOS == "windows" {
+					profileDir = filepath.Join(home, "Documents", "PowerShell")
+				}
+				path = filepath.Join(profileDir, root.Name()+"-completion.ps1")
+				profile = filepath.Join(profileDir, "Microsoft.PowerShell_profile.ps1")
+				profileLine = fmt.Sprintf(". '%s'", path)
+			default:
+				return fmt.Errorf("unsupported shell: %s (use bash, zsh, fish or powershell)", shell)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to generate %s completion: %v", shell, err)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return fmt.Errorf("failed to create directory %s: %v", filepath.Dir(path), err)
+			}
+			if err := os.WriteFile(path, script.Bytes(), 0644); err != nil {
+				return fmt.Errorf("failed to write completion script: %v", err)
+			}
+			pterm.Success.Printf("Installed %s completion to %s\n", shell, path)
+
+			if profile != "" {
+				added, err := appendProfileLine(profile, profileLine)
+				if err != nil {
+					return fmt.Errorf("failed to update %s: %v", profile, err)
+				}
+				if added {
+					pterm.Success.Printf("Enabled the completion in %s\n", profile)
+				}
+			}
+
+			if shell == "bash" {
+				pterm.Info.Println("The bash completion requires the bash-completion package (v2) to be installed.")
+			}
+			pterm.Info.Println("Open a new shell to use the completion.")
+			return nil
+		},
+	}
+
+	cmd.Flags().String("shell", "", "Shell to install the completion for (bash, zsh, fish, powershell), detected if not set")
+	cmd.RegisterFlagCompletionFunc("shell", cobra.FixedCompletions([]string{"bash", "zsh", "fish", "powershell"}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+// detectShell returns the name of the login shell of the user
+func detectShell() string {
+	if shell := filepath.Base(os.Getenv("SHELL")); shell != "." && shell != "/" {
+		shell = strings.TrimSuffix(shell, ".exe")
+		switch shell {
+		case "bash", "zsh", "fish":
+			return shell
+		case "pwsh", "powershell":
+			return "powershell"
+		}
+	}
+	if runtime.GOOS == "windows" || os.Getenv("PSModulePath") != "" {
+		return "powershell"
+	}
+	return ""
+}
+
+// appendProfileLine adds the line to a shell profile unless it is already there
+func appendProfileLine(profile, line string) (bool, error) {
+	data, err := os.ReadFile(profile)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	if strings.Contains(string(data), line) {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(profile), 0755); err != nil {
+		return false, err
+	}
+	file, err := os.OpenFile(profile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintf(file, "\n# cfctl completion\n%s\n", line); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// dataHome returns $XDG_DATA_HOME or its default
+func dataHome(home string) string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir
+	}
+	return filepath.Join(home, ".local", "share")
+}
+
+// configHome returns $XDG_CONFIG_HOME or its default
+func configHome(home string) string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	return filepath.Join(home, ".config")
+}
+
+// zshHome returns $ZDOTDIR or the home directory
+func zshHome(home string) string {
+	if dir := os.Getenv("ZDOTDIR"); dir != "" {
+		return dir
+	}
+	return home
+}
@@ -0,0 +1,261 @@
+package other
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/xuri/excelize/v2"
+)
+
+// costDateLayouts are the date formats of each granularity used by cost_analysis
+var costDateLayouts = map[string]string{
+	"DAILY":   "2006-01-02",
+	"MONTHLY": "2006-01",
+	"YEARLY":  "2006",
+}
+
+// CostCmd represents the cost command
+var CostCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "Analyze costs",
+	Long:  `Analyze costs collected by the cost_analysis service.`,
+}
+
+var costReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show a cost report grouped by fields with periods as columns",
+	Example: `  # Monthly cost per project since January 2024
+  $ cfctl cost report --granularity MONTHLY --group-by project_id --start 2024-01
+
+  # Daily cost per provider of the last week as a spreadsheet
+  $ cfctl cost report --granularity DAILY --group-by provider --start -7d -o xlsx --file cost.xlsx`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		granularity, _ := cmd.Flags().GetString("granularity")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		startArg, _ := cmd.Flags().GetString("start")
+		endArg, _ := cmd.Flags().GetString("end")
+		dataSourceID, _ := cmd.Flags().GetString("data-source-id")
+		output, _ := cmd.Flags().GetString("output")
+		file, _ := cmd.Flags().GetString("file")
+
+		granularity = strings.ToUpper(granularity)
+		layout, ok := costDateLayouts[granularity]
+		if !ok {
+			return fmt.Errorf("unsupported granularity '%s' (use DAILY, MONTHLY, YEARLY)", granularity)
+		}
+
+		start, err := parseCostDate(startArg, layout)
+		if err != nil {
+			return err
+		}
+		end, err := parseCostDate(endArg, layout)
+		if err != nil {
+			return err
+		}
+
+		groupFields := strings.Split(groupBy, ",")
+		for i := range groupFields {
+			groupFields[i] = strings.TrimSpace(groupFields[i])
+		}
+
+		params := map[string]interface{}{
+			"query": map[string]interface{}{
+				"granularity": granularity,
+				"start":       start.Format(layout),
+				"end":         end.Format(layout),
+				"group_by":    groupFields,
+				"fields": map[string]interface{}{
+					"cost": map[string]interface{}{"key": "cost", "operator": "sum"},
+				},
+			},
+		}
+		if dataSourceID != "" {
+			params["data_source_id"] = dataSourceID
+		}
+		paramBytes, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal analyze query: %v", err)
+		}
+
+		resp, err := transport.FetchService("cost_analysis", "analyze", "Cost", &transport.FetchOptions{
+			JSONParameter: string(paramBytes),
+		})
+		if err != nil {
+			return err
+		}
+		if resp == nil {
+			return nil
+		}
+
+		results, _ := resp["results"].([]interface{})
+		header, rows := pivotCosts(results, groupFields)
+
+		switch output {
+		case "table":
+			data := [][]string{header}
+			for _, row := range rows {
+				data = append(data, formatCostRow(row, len(groupFields)))
+			}
+			return pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+		case "csv":
+			writer := csv.NewWriter(os.Stdout)
+			if err := writer.Write(header); err != nil {
+				return err
+			}
+			return writer.WriteAll(rows)
+		case "xlsx":
+			if err := writeCostXLSX(file, header, rows, len(groupFields)); err != nil {
+				return err
+			}
+			pterm.Success.Printf("Cost report saved to %s\n", file)
+			return nil
+		default:
+			return fmt.Errorf("unsupported output format '%s' (use table, csv, xlsx)", output)
+		}
+	},
+}
+
+// parseCostDate parses a date in the granularity layout or relative to now
+func parseCostDate(value, layout string) (time.Time, error) {
+	if t, err := time.Parse(layout, value); err == nil {
+		return t, nil
+	}
+	// Accept a month or day for any granularity
+	for _, l := range []string{"2006-01-02", "2006-01"} {
+		if t, err := time.Parse(l, value); err == nil {
+			return t, nil
+		}
+	}
+	return format.ParseTimeArg(value, time.Now())
+}
+
+// pivotCosts turns analyze results into rows of group values with one cost column per period
+func pivotCosts(results []interface{}, groupFields []string) ([]string, [][]string) {
+	costs := make(map[string]map[string]float64)
+	groups := make(map[string][]string)
+	periodSet := make(map[string]bool)
+
+	for _, result := range results {
+		row, ok := result.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		values := make([]string, len(groupFields))
+		for i, field := range groupFields {
+			if val, ok := format.GetValueByPath(row, field); ok && val != nil {
+				values[i] = fmt.Sprintf("%v", val)
+			}
+		}
+		key := strings.Join(values, "\x00")
+		groups[key] = values
+
+		period := fmt.Sprintf("%v", row["date"])
+		periodSet[period] = true
+
+		if costs[key] == nil {
+			costs[key] = make(map[string]float64)
+		}
+		if cost, ok := row["cost"].(float64); ok {
+			costs[key][period] += cost
+		}
+	}
+
+	periods := make([]string, 0, len(periodSet))
+	for period := range periodSet {
+		periods = append(periods, period)
+	}
+	sort.Strings(periods)
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	header := append(append([]string{}, groupFields...), periods...)
+	header = append(header, "total")
+
+	rows := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		row := append([]string{}, groups[key]...)
+		total := 0.0
+		for _, period := range periods {
+			cost := costs[key][period]
+			total += cost
+			row = append(row, fmt.Sprintf("%.2f", cost))
+		}
+		row = append(row, fmt.Sprintf("%.2f", total))
+		rows = append(rows, row)
+	}
+
+	return header, rows
+}
+
+// formatCostRow formats the cost columns of a row with thousands separators
+func formatCostRow(row []string, groupCount int) []string {
+	formatted := make([]string, len(row))
+	for i, cell := range row {
+		formatted[i] = cell
+		if i < groupCount {
+			continue
+		}
+		var v float64
+		if _, err := fmt.Sscanf(cell, "%f", &v); err == nil {
+			formatted[i] = format.FormatNumber(v)
+		}
+	}
+	return formatted
+}
+
+// writeCostXLSX writes the cost report to a spreadsheet with numeric cost cells
+func writeCostXLSX(path string, header []string, rows [][]string, groupCount int) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := f.GetSheetName(0)
+	for col, name := range header {
+		cell, _ := excelize.CoordinatesToCellName(col+1, 1)
+		f.SetCellValue(sheet, cell, name)
+	}
+
+	for r, row := range rows {
+		for col, value := range row {
+			cell, _ := excelize.CoordinatesToCellName(col+1, r+2)
+			if col >= groupCount {
+				var v float64
+				if _, err := fmt.Sscanf(value, "%f", &v); err == nil {
+					f.SetCellValue(sheet, cell, v)
+					continue
+				}
+			}
+			f.SetCellValue(sheet, cell, value)
+		}
+	}
+
+	if err := f.SaveAs(path); err != nil {
+		return fmt.Errorf("failed to save %s: %v", path, err)
+	}
+	return nil
+}
+
+func init() {
+	CostCmd.AddCommand(costReportCmd)
+
+	costReportCmd.Flags().String("granularity", "MONTHLY", "Granularity of periods (DAILY, MONTHLY, YEARLY)")
+	costReportCmd.Flags().String("group-by", "project_id", "Fields to group costs by (--group-by project_id,provider)")
+	costReportCmd.Flags().String("start", "-90d", "Start period (2024-01, 2024-01-02 or a relative duration like -30d)")
+	costReportCmd.Flags().String("end", "now", "End period")
+	costReportCmd.Flags().String("data-source-id", "", "Cost data source id")
+	costReportCmd.Flags().StringP("output", "o", "table", "Output format (table, csv, xlsx)")
+	costReportCmd.Flags().String("file", "cost-report.xlsx", "Output file for xlsx")
+}
@@ -0,0 +1,119 @@
+package other
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// certificateExpiryWarning is how long before expiry a certificate is reported as expiring soon
+const certificateExpiryWarning = 30 * 24 * time.Hour
+
+// DebugCmd represents the debug command
+var DebugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Troubleshoot cfctl and its connections",
+}
+
+var debugConnectionCmd = &cobra.Command{
+	Use:   "connection <service>",
+	Short: "Diagnose the connection to a service",
+	Long: `Resolve the endpoint of a service and report the DNS lookup, TCP connect time,
+TLS handshake details (version, ALPN, certificate chain and expiry) and whether
+server reflection responds.`,
+	Example: `  # Diagnose the connection to the inventory service
+  $ cfctl debug connection inventory
+
+  # Print the diagnostics as JSON, e.g. to attach to a bug report
+  $ cfctl debug connection identity -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+
+		report, err := transport.DiagnoseConnection(args[0])
+		if err != nil {
+			return err
+		}
+
+		switch output {
+		case "json":
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal report: %v", err)
+			}
+			fmt.Println(string(data))
+		case "table":
+			renderConnectionReport(report)
+		default:
+			return fmt.Errorf("unsupported output format: %s (use table or json)", output)
+		}
+
+		if len(report.Errors) > 0 {
+			return fmt.Errorf("connection to %s failed", args[0])
+		}
+		return nil
+	},
+}
+
+// renderConnectionReport prints the diagnostics of a connection
+func renderConnectionReport(report *transport.ConnectionReport) {
+	table := pterm.TableData{
+		{"Check", "Result"},
+		{"Target", report.Target},
+		{"Addresses", strings.Join(report.Addresses, ", ")},
+	}
+	if report.ConnectTime > 0 {
+		table = append(table, []string{"TCP connect", report.ConnectTime.Round(time.Microsecond).String()})
+	}
+	if report.TLS != nil {
+		alpn := report.TLS.ALPN
+		if alpn == "" {
+			alpn = "(none, gRPC requires h2)"
+		}
+		table = append(table,
+			[]string{"TLS version", report.TLS.Version},
+			[]string{"Cipher suite", report.TLS.CipherSuite},
+			[]string{"ALPN", alpn},
+			[]string{"TLS handshake", report.TLS.HandshakeTime.Round(time.Microsecond).String()},
+		)
+	}
+	if report.Reflection {
+		table = append(table,
+			[]string{"Reflection", fmt.Sprintf("ok (%d services)", report.Services)},
+			[]string{"Round trip", report.RoundTrip.Round(time.Microsecond).String()},
+		)
+	}
+	pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+
+	if report.TLS != nil && len(report.TLS.Certificates) > 0 {
+		fmt.Println()
+		certTable := pterm.TableData{{"Subject", "Issuer", "Expires"}}
+		for _, cert := range report.TLS.Certificates {
+			expires := fmt.Sprintf("%s (%s)", cert.NotAfter.Format(time.RFC3339), format.FormatRelativeTime(cert.NotAfter))
+			if time.Until(cert.NotAfter) < certificateExpiryWarning {
+				expires = pterm.FgRed.Sprint(expires)
+			}
+			certTable = append(certTable, []string{cert.Subject, cert.Issuer, expires})
+		}
+		pterm.DefaultTable.WithHasHeader().WithData(certTable).Render()
+	}
+
+	for _, message := range report.Errors {
+		pterm.Error.Println(message)
+	}
+	if len(report.Errors) == 0 {
+		pterm.Success.Printf("Connection to %s is healthy\n", report.Service)
+	}
+}
+
+func init() {
+	DebugCmd.AddCommand(debugConnectionCmd)
+	DebugCmd.AddCommand(debugBundleCmd)
+	debugConnectionCmd.Flags().StringP("output", "o", "table", "Output format (table, json)")
+}
@@ -0,0 +1,139 @@
+package other
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cloudforet-io/cfctl/cmd/common"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// DocsCmd returns the command generating the documentation of the root command
+func DocsCmd(root *cobra.Command) *cobra.Command {
+	docsCmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate the documentation of cfctl",
+	}
+
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate man pages or markdown for every command",
+		Long: `Generate man pages or markdown documentation for every command, including the
+service commands of the current environment. Each verb of a service is documented
+with the resources supporting it, read from the cached API descriptors.`,
+		Example: `  # Generate markdown documentation into ./docs
+  $ cfctl docs generate --format markdown --dir ./docs
+
+  # Generate man pages and view one of them
+  $ cfctl docs generate --format man --dir ./man
+  $ man ./man/cfctl-inventory-list.1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			docFormat, _ := cmd.Flags().GetString("format")
+			dir, _ := cmd.Flags().GetString("dir")
+
+			if docFormat != "man" && docFormat != "markdown" {
+				return fmt.Errorf("unsupported format: %s (use man or markdown)", docFormat)
+			}
+
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create directory %s: %v", dir, err)
+			}
+
+			for _, serviceCmd := range root.Commands() {
+				if serviceCmd.GroupID != "available" {
+					continue
+				}
+				if err := addVerbDocCommands(serviceCmd); err != nil {
+					pterm.Warning.Printf("Skipping the verbs of %s: %v\n", serviceCmd.Name(), err)
+				}
+			}
+
+			root.DisableAutoGenTag = true
+
+			var err error
+			switch docFormat {
+			case "man":
+				err = doc.GenManTree(root, &doc.GenManHeader{Title: strings.ToUpper(root.Name()), Section: "1", Source: root.Name()}, dir)
+			case "markdown":
+				err = doc.GenMarkdownTree(root, dir)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to generate %s documentation: %v", docFormat, err)
+			}
+
+			pterm.Success.Printf("Generated %s documentation in %s\n", docFormat, dir)
+			return nil
+		},
+	}
+
+	generateCmd.Flags().String("format", "markdown", "Documentation format (man, markdown)")
+	generateCmd.Flags().String("dir", "docs", "Directory to write the documentation to")
+
+	docsCmd.AddCommand(generateCmd)
+	return docsCmd
+}
+
+// addVerbDocCommands adds a command for each verb of a service,
+// so that the verbs and their resources appear in the generated documentation.
+// The commands only exist for documentation and are never executed.
+func addVerbDocCommands(serviceCmd *cobra.Command) error {
+	serviceName := serviceCmd.Name()
+	methods, err := common.ServiceMethods(serviceName)
+	if err != nil {
+		return err
+	}
+
+	// Collect the resources and descriptions of each verb
+	verbs := make(map[string][][]string)
+	for resource, resourceMethods := range methods {
+		for _, method := range resourceMethods {
+			description := strings.Join(strings.Fields(method.Description), " ")
+			if method.Deprecated {
+				description = strings.TrimSpace("(deprecated) " + description)
+			}
+			verbs[method.Verb] = append(verbs[method.Verb], []string{resource, description})
+		}
+	}
+
+	existing := make(map[string]bool)
+	for _, child := range serviceCmd.Commands() {
+		existing[child.Name()] = true
+	}
+
+	for verb, resources := range verbs {
+		if existing[verb] {
+			continue
+		}
+
+		sort.Slice(resources, func(i, j int) bool {
+			return resources[i][0] < resources[j][0]
+		})
+
+		var long strings.Builder
+		fmt.Fprintf(&long, "Call the %s method of a resource of the %s service.\n\nResources:\n", verb, serviceName)
+		for _, resource := range resources {
+			if resource[1] != "" {
+				fmt.Fprintf(&long, "  %s - %s\n", resource[0], resource[1])
+			} else {
+				fmt.Fprintf(&long, "  %s\n", resource[0])
+			}
+		}
+
+		verbCmd := &cobra.Command{
+			Use:     verb + " <resource>",
+			Short:   fmt.Sprintf("Call %s on a %s resource", verb, serviceName),
+			Long:    long.String(),
+			Example: fmt.Sprintf("  $ cfctl %s %s %s", serviceName, verb, resources[0][0]),
+			Run:     func(cmd *cobra.Command, args []string) {},
+		}
+		verbCmd.Flags().AddFlagSet(serviceCmd.Flags())
+		serviceCmd.AddCommand(verbCmd)
+	}
+
+	return nil
+}
@@ -0,0 +1,307 @@
+package other
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// domainTokenFiles are the token caches kept per domain
+var domainTokenFiles = []string{"access_token", "refresh_token"}
+
+// DomainCmd represents the domain command
+var DomainCmd = &cobra.Command{
+	Use:   "domain",
+	Short: "Manage the domains logged in to in the current environment",
+	Long: `A user environment can be logged in to several domains of the same SpaceONE
+installation with 'cfctl login --domain <name>'. The tokens of each domain are
+cached separately and 'cfctl domain use' switches between them without logging in again.`,
+}
+
+var domainListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the domains logged in to in the current environment",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		currentEnv, err := currentEnvironment()
+		if err != nil {
+			return err
+		}
+
+		domains, err := loggedInDomains(currentEnv)
+		if err != nil {
+			return err
+		}
+		if len(domains) == 0 {
+			pterm.Info.Println("No domains logged in yet. Run 'cfctl login' first.")
+			return nil
+		}
+
+		active := activeDomain(currentEnv)
+		table := pterm.TableData{{"", "Name", "Domain ID", "Session Expires"}}
+		for _, domain := range domains {
+			marker := ""
+			if domain.name == active {
+				marker = "*"
+			}
+			expires := "-"
+			if !domain.refreshExpires.IsZero() {
+				expires = format.FormatRelativeTime(domain.refreshExpires)
+				if time.Now().After(domain.refreshExpires) {
+					expires = pterm.FgRed.Sprint("expired")
+				}
+			}
+			table = append(table, []string{marker, domain.name, domain.domainID, expires})
+		}
+		pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+		return nil
+	},
+}
+
+var domainUseCmd = &cobra.Command{
+	Use:   "use <domain_id|name>",
+	Short: "Switch to another domain logged in to in the current environment",
+	Example: `  # Log in to a second domain and switch back to the first one
+  $ cfctl login --domain customer-b
+  $ cfctl domain use customer-a`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		currentEnv, err := currentEnvironment()
+		if err != nil {
+			return err
+		}
+		if !strings.HasSuffix(currentEnv, "-user") {
+			return fmt.Errorf("domains can only be switched in user environments")
+		}
+
+		domains, err := loggedInDomains(currentEnv)
+		if err != nil {
+			return err
+		}
+
+		var target *domainSession
+		for i := range domains {
+			if domains[i].name == args[0] || domains[i].domainID == args[0] {
+				target = &domains[i]
+				break
+			}
+		}
+		if target == nil {
+			return fmt.Errorf("not logged in to domain %s, run 'cfctl login --domain %s' first", args[0], args[0])
+		}
+
+		active := activeDomain(currentEnv)
+		if target.name == active {
+			pterm.Info.Printf("Already using domain %s\n", active)
+			return nil
+		}
+
+		if err := switchDomain(currentEnv, active, target.name); err != nil {
+			return err
+		}
+
+		pterm.Success.Printf("Switched to domain %s (%s)\n", target.name, target.domainID)
+		if !target.refreshExpires.IsZero() && time.Now().After(target.refreshExpires) {
+			pterm.Warning.Printf("The session of %s expired, run 'cfctl login' to log in again\n", target.name)
+		}
+		return nil
+	},
+}
+
+// domainSession is a domain with cached tokens
+type domainSession struct {
+	name           string
+	domainID       string
+	refreshExpires time.Time
+}
+
+// currentEnvironment returns the name of the current environment
+func currentEnvironment() (string, error) {
+	settingPath, err := configs.GetSettingFilePath()
+	if err != nil {
+		return "", err
+	}
+
+	v := viper.New()
+	v.SetConfigFile(settingPath)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return "", fmt.Errorf("failed to read setting file: %v", err)
+	}
+
+	currentEnv := v.GetString("environment")
+	if currentEnv == "" {
+		return "", fmt.Errorf("no environment selected")
+	}
+	return currentEnv, nil
+}
+
+// configuredDomain returns the domain set for the environment by 'cfctl login --domain' or 'cfctl domain use'
+func configuredDomain(currentEnv string) string {
+	settingPath, err := configs.GetSettingFilePath()
+	if err != nil {
+		return ""
+	}
+
+	v := viper.New()
+	v.SetConfigFile(settingPath)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return ""
+	}
+	return v.GetString(fmt.Sprintf("environments.%s.domain", currentEnv))
+}
+
+// loginDomainName returns the domain to log in to, the configured domain or the domain derived by the login flow
+func loginDomainName(currentEnv, derived string) string {
+	if domain := configuredDomain(currentEnv); domain != "" {
+		return domain
+	}
+	return derived
+}
+
+// activeDomain returns the domain whose tokens are in the cache of the environment.
+// Environments without a configured domain use the domain of their name.
+func activeDomain(currentEnv string) string {
+	if domain := configuredDomain(currentEnv); domain != "" {
+		return domain
+	}
+	return strings.Split(currentEnv, "-")[0]
+}
+
+// setActiveDomain records the domain of the environment in the setting file
+func setActiveDomain(currentEnv, domain string) error {
+	settingPath, err := configs.GetSettingFilePath()
+	if err != nil {
+		return err
+	}
+
+	v := viper.New()
+	v.SetConfigFile(settingPath)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read setting file: %v", err)
+	}
+
+	v.Set(fmt.Sprintf("environments.%s.domain", currentEnv), domain)
+	if err := v.WriteConfig(); err != nil {
+		return fmt.Errorf("failed to save domain: %v", err)
+	}
+	return nil
+}
+
+// switchDomain stores the tokens of the active domain and restores the tokens of the target domain
+func switchDomain(currentEnv, active, target string) error {
+	envCacheDir, err := envCacheDirectory(currentEnv)
+	if err != nil {
+		return err
+	}
+
+	if err := saveDomainTokens(currentEnv, active); err != nil {
+		return err
+	}
+
+	for _, name := range domainTokenFiles {
+		os.Remove(filepath.Join(envCacheDir, name))
+		data, err := os.ReadFile(filepath.Join(envCacheDir, "domains", target, name))
+		if err != nil {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(envCacheDir, name), data, 0600); err != nil {
+			return fmt.Errorf("failed to restore %s of domain %s: %v", name, target, err)
+		}
+	}
+	// The admin token belongs to the previous domain
+	os.Remove(filepath.Join(envCacheDir, "admin_access_token"))
+
+	return setActiveDomain(currentEnv, target)
+}
+
+// saveDomainTokens copies the cached tokens of the environment to the cache of the domain
+func saveDomainTokens(currentEnv, domain string) error {
+	envCacheDir, err := envCacheDirectory(currentEnv)
+	if err != nil {
+		return err
+	}
+
+	domainDir := filepath.Join(envCacheDir, "domains", domain)
+	for _, name := range domainTokenFiles {
+		data, err := os.ReadFile(filepath.Join(envCacheDir, name))
+		if err != nil {
+			continue
+		}
+		if err := os.MkdirAll(domainDir, 0700); err != nil {
+			return fmt.Errorf("failed to create domain cache directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(domainDir, name), data, 0600); err != nil {
+			return fmt.Errorf("failed to save %s of domain %s: %v", name, domain, err)
+		}
+	}
+	return nil
+}
+
+// loggedInDomains returns the domains with cached tokens in the environment
+func loggedInDomains(currentEnv string) ([]domainSession, error) {
+	envCacheDir, err := envCacheDirectory(currentEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the active domain is listed even before the first switch
+	if err := saveDomainTokens(currentEnv, activeDomain(currentEnv)); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(envCacheDir, "domains"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var domains []domainSession
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		domain := domainSession{name: entry.Name()}
+		if token, err := readTokenFromFile(filepath.Join(envCacheDir, "domains", entry.Name()), "refresh_token"); err == nil {
+			if claims, err := decodeJWT(strings.TrimSpace(token)); err == nil {
+				domain.domainID, _ = claims["did"].(string)
+				if exp, ok := claims["exp"].(float64); ok {
+					domain.refreshExpires = time.Unix(int64(exp), 0)
+				}
+			}
+		}
+		domains = append(domains, domain)
+	}
+
+	sort.Slice(domains, func(i, j int) bool {
+		return domains[i].name < domains[j].name
+	})
+	return domains, nil
+}
+
+// envCacheDirectory returns the cache directory of the environment
+func envCacheDirectory(currentEnv string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find home directory: %v", err)
+	}
+	return filepath.Join(home, ".cfctl", "cache", currentEnv), nil
+}
+
+func init() {
+	DomainCmd.AddCommand(domainListCmd)
+	DomainCmd.AddCommand(domainUseCmd)
+}
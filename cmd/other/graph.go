@@ -0,0 +1,189 @@
+package other
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// graphSkipFields are reference fields left out of the graph since nearly every resource has them
+var graphSkipFields = map[string]bool{"domain_id": true}
+
+// graphNode is a resource of the graph with the resources it refers to
+type graphNode struct {
+	service  string
+	resource string
+	id       string
+	name     string
+	err      error
+	edges    []graphEdge
+}
+
+// graphEdge is a reference field from one resource to another
+type graphEdge struct {
+	field  string
+	target *graphNode
+	seen   bool // The target is already shown elsewhere in the graph
+}
+
+// GraphCmd represents the graph command
+var GraphCmd = &cobra.Command{
+	Use:   "graph <service> <Resource> <id>",
+	Short: "Show the resources a resource refers to",
+	Long: `Follow the reference fields of a resource (project_id, provider, secret_id,
+collector_id and other *_id fields) with get calls and print how it is wired,
+as a tree or as a Graphviz DOT graph.`,
+	Example: `  # Show what a service account is wired to
+  $ cfctl graph identity ServiceAccount sa-1a2b3c4d
+
+  # Render the graph of a collector with Graphviz
+  $ cfctl graph inventory Collector collector-1a2b3c4d -o dot | dot -Tpng > collector.png`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+		depth, _ := cmd.Flags().GetInt("depth")
+		if outputFormat != "tree" && outputFormat != "dot" {
+			return fmt.Errorf("unsupported output format %s, use tree or dot", outputFormat)
+		}
+
+		serviceName, resourceName, id := args[0], args[1], args[2]
+		idField := transport.ResourceIDField(resourceName)
+
+		visited := make(map[string]*graphNode)
+		root := buildGraph(serviceName, resourceName, idField, id, depth, visited)
+		if root.err != nil {
+			return root.err
+		}
+
+		if outputFormat == "dot" {
+			fmt.Print(graphDOT(root))
+			return nil
+		}
+		return pterm.DefaultTree.WithRoot(graphTree(root)).Render()
+	},
+}
+
+// buildGraph gets a resource and follows its reference fields up to the depth
+func buildGraph(serviceName, resourceName, idField, id string, depth int, visited map[string]*graphNode) *graphNode {
+	node := &graphNode{service: serviceName, resource: resourceName, id: id}
+	visited[graphKey(serviceName, resourceName, id)] = node
+
+	resp, err := transport.FetchService(serviceName, "get", resourceName, &transport.FetchOptions{
+		Parameters: []string{fmt.Sprintf("%s=%s", idField, id)},
+	})
+	if err == nil && resp == nil {
+		err = fmt.Errorf("no response")
+	}
+	if err != nil {
+		node.err = fmt.Errorf("failed to get %s.%s %s: %v", serviceName, resourceName, id, err)
+		return node
+	}
+	node.name, _ = resp["name"].(string)
+
+	if depth <= 0 {
+		return node
+	}
+
+	fields := make([]string, 0, len(resp))
+	for field := range resp {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		refID, ok := resp[field].(string)
+		if !ok || refID == "" || field == idField || graphSkipFields[field] || !transport.IsReferenceField(field) {
+			continue
+		}
+
+		refService, refResource := transport.ReferencedResource(serviceName, field)
+		if target, ok := visited[graphKey(refService, refResource, refID)]; ok {
+			node.edges = append(node.edges, graphEdge{field: field, target: target, seen: true})
+			continue
+		}
+		target := buildGraph(refService, refResource, field, refID, depth-1, visited)
+		node.edges = append(node.edges, graphEdge{field: field, target: target})
+	}
+	return node
+}
+
+// graphTree converts the graph to a pterm tree, resources shown before are not expanded again
+func graphTree(node *graphNode) pterm.TreeNode {
+	tree := pterm.TreeNode{Text: graphLabel(node)}
+	for _, edge := range node.edges {
+		child := pterm.TreeNode{Text: fmt.Sprintf("%s: %s", pterm.Gray(edge.field), graphLabel(edge.target))}
+		if edge.seen {
+			child.Text += pterm.Gray(" (see above)")
+		} else {
+			child.Children = graphTree(edge.target).Children
+		}
+		tree.Children = append(tree.Children, child)
+	}
+	return tree
+}
+
+// graphDOT renders the graph in the Graphviz DOT language
+func graphDOT(root *graphNode) string {
+	var sb strings.Builder
+	sb.WriteString("digraph cfctl {\n")
+	sb.WriteString("  node [shape=box];\n")
+
+	written := make(map[*graphNode]bool)
+	var write func(node *graphNode)
+	write = func(node *graphNode) {
+		if written[node] {
+			return
+		}
+		written[node] = true
+
+		label := fmt.Sprintf("%s.%s\\n%s", node.service, node.resource, node.id)
+		if node.name != "" {
+			label += "\\n" + node.name
+		}
+		style := ""
+		if node.err != nil {
+			style = ", style=dashed"
+		}
+		sb.WriteString(fmt.Sprintf("  %s [label=%s%s];\n", dotQuote(node.id), dotQuote(label), style))
+
+		for _, edge := range node.edges {
+			sb.WriteString(fmt.Sprintf("  %s -> %s [label=%s];\n", dotQuote(node.id), dotQuote(edge.target.id), dotQuote(edge.field)))
+			write(edge.target)
+		}
+	}
+	write(root)
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// dotQuote quotes an identifier of the DOT language, keeping \n line breaks of labels
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// graphLabel describes a resource of the graph
+func graphLabel(node *graphNode) string {
+	label := fmt.Sprintf("%s.%s %s", node.service, node.resource, node.id)
+	if node.name != "" {
+		label += fmt.Sprintf(" (%s)", node.name)
+	}
+	if node.err != nil {
+		label += pterm.Red(" unresolved")
+	}
+	return label
+}
+
+// graphKey identifies a resource of the graph
+func graphKey(serviceName, resourceName, id string) string {
+	return fmt.Sprintf("%s.%s/%s", serviceName, resourceName, id)
+}
+
+func init() {
+	GraphCmd.Flags().StringP("output", "o", "tree", "Output format (tree, dot)")
+	GraphCmd.Flags().Int("depth", 3, "How many references deep to follow")
+}
@@ -0,0 +1,39 @@
+package other
+
+import (
+	"time"
+
+	"github.com/cloudforet-io/cfctl/cmd/common"
+	"github.com/spf13/cobra"
+)
+
+// JobsCmd represents the jobs command
+var JobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Follow collection jobs",
+	Long:  `Follow inventory and cost analysis collection jobs.`,
+}
+
+var jobsTailCmd = &cobra.Command{
+	Use:   "tail <job_id>",
+	Short: "Follow the progress of a job until it finishes",
+	Example: `  # Follow an inventory collection job
+  $ cfctl jobs tail job-123456789012
+
+  # Follow a cost analysis job
+  $ cfctl jobs tail job-123456789012 -s cost_analysis`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceName, _ := cmd.Flags().GetString("service")
+		interval, _ := cmd.Flags().GetDuration("interval")
+
+		return common.TailJob(serviceName, args[0], interval)
+	},
+}
+
+func init() {
+	JobsCmd.AddCommand(jobsTailCmd)
+
+	jobsTailCmd.Flags().StringP("service", "s", "inventory", "Service of the job (inventory, cost_analysis)")
+	jobsTailCmd.Flags().Duration("interval", 3*time.Second, "Polling interval")
+}
@@ -0,0 +1,264 @@
+package other
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// maxEventSize is the largest webhook payload accepted
+const maxEventSize = 1 << 20
+
+// ListenCmd represents the listen command
+var ListenCmd = &cobra.Command{
+	Use:   "listen",
+	Short: "Receive SpaceONE webhooks and run a handler per event",
+	Long: `Run an HTTP server accepting the JSON payloads of SpaceONE webhooks and alert
+notifications and run a handler for every event, bridging notifications to local
+automation without writing a service.
+
+With --exec, the command runs through the shell with the payload on stdin and
+CFCTL_EVENT_PATH and CFCTL_EVENT_TYPE set. With --run, a cfctl command is rendered
+from a Go template of the payload and run. Events are acknowledged right away and
+handled in the background.
+
+The receiver listens on 127.0.0.1 by default. Listening on another address requires
+--secret, which must then be sent as the token query parameter or as a bearer token,
+since handlers run with the credentials of cfctl.
+
+The --run template is split into arguments before it is rendered, so that every
+value of the payload ends up in exactly one argument. Quote an argument with
+spaces in the template.`,
+	Example: `  # Pass every event to a script
+  $ cfctl listen --port 8080 --exec ./handler.sh
+
+  # Acknowledge the alert of every event
+  $ cfctl listen --run 'monitoring update Alert -p alert_id={{.alert_id}} -p state=ACKNOWLEDGED'
+
+  # Accept events from other hosts
+  $ cfctl listen --host 0.0.0.0 --exec ./handler.sh --secret s3cr3t`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		host, _ := cmd.Flags().GetString("host")
+		port, _ := cmd.Flags().GetInt("port")
+		path, _ := cmd.Flags().GetString("path")
+		handlerCommand, _ := cmd.Flags().GetString("exec")
+		runTemplate, _ := cmd.Flags().GetString("run")
+		secret, _ := cmd.Flags().GetString("secret")
+
+		if (handlerCommand == "") == (runTemplate == "") {
+			return fmt.Errorf("either --exec or --run is required")
+		}
+
+		if secret == "" && !isLoopbackHost(host) {
+			return fmt.Errorf("--secret is required to listen on %q, since handlers run with your credentials", host)
+		}
+
+		var tmpls []*template.Template
+		if runTemplate != "" {
+			args, err := splitTemplateArgs(runTemplate)
+			if err != nil {
+				return fmt.Errorf("failed to parse --run template: %v", err)
+			}
+			for i, arg := range args {
+				tmpl, err := template.New(fmt.Sprintf("run%d", i)).Option("missingkey=error").Parse(arg)
+				if err != nil {
+					return fmt.Errorf("failed to parse --run template: %v", err)
+				}
+				tmpls = append(tmpls, tmpl)
+			}
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			if secret != "" && !validEventSecret(r, secret) {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxEventSize))
+			if err != nil {
+				http.Error(w, "failed to read payload", http.StatusBadRequest)
+				return
+			}
+			var event map[string]interface{}
+			if err := json.Unmarshal(body, &event); err != nil {
+				http.Error(w, "payload is not a JSON object", http.StatusBadRequest)
+				return
+			}
+
+			w.WriteHeader(http.StatusAccepted)
+			go handleEvent(r.URL.Path, body, event, handlerCommand, tmpls)
+		})
+
+		server := &http.Server{
+			Addr:              fmt.Sprintf("%s:%d", host, port),
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-stop
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server.Shutdown(ctx)
+		}()
+
+		pterm.Info.Printf("Listening for events on http://%s%s\n", server.Addr, path)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("failed to listen: %v", err)
+		}
+		return nil
+	},
+}
+
+// handleEvent runs the handler of an event and logs the outcome
+func handleEvent(path string, body []byte, event map[string]interface{}, handlerCommand string, tmpls []*template.Template) {
+	kind := eventType(event)
+	started := time.Now()
+
+	var cmd *exec.Cmd
+	if tmpls != nil {
+		// Every argument is rendered on its own, so payload values cannot add arguments
+		args := make([]string, len(tmpls))
+		for i, tmpl := range tmpls {
+			var rendered bytes.Buffer
+			if err := tmpl.Execute(&rendered, event); err != nil {
+				pterm.Error.Printf("%s event: failed to render --run template: %v\n", kind, err)
+				return
+			}
+			args[i] = rendered.String()
+		}
+		executable, err := os.Executable()
+		if err != nil {
+			pterm.Error.Printf("%s event: failed to find cfctl executable: %v\n", kind, err)
+			return
+		}
+		cmd = exec.Command(executable, args...)
+	} else {
+		cmd = transport.ShellCommand(handlerCommand)
+		cmd.Stdin = bytes.NewReader(body)
+		cmd.Env = append(os.Environ(),
+			"CFCTL_EVENT_PATH="+path,
+			"CFCTL_EVENT_TYPE="+kind,
+		)
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		pterm.Error.Printf("%s event: handler failed after %s: %v\n", kind, time.Since(started).Round(time.Millisecond), err)
+		return
+	}
+	pterm.Success.Printf("%s event: handled in %s\n", kind, time.Since(started).Round(time.Millisecond))
+}
+
+// eventType returns the type of a webhook event from the common fields of SpaceONE payloads
+func eventType(event map[string]interface{}) string {
+	for _, key := range []string{"event_type", "resource_type", "notification_type"} {
+		if value, ok := event[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return "unknown"
+}
+
+// splitTemplateArgs splits a --run template into arguments at whitespace outside of
+// template actions and quotes. Quotes group an argument and are removed.
+func splitTemplateArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote byte
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case strings.HasPrefix(s[i:], "{{"):
+			end := strings.Index(s[i:], "}}")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed action in %q", s)
+			}
+			current.WriteString(s[i : i+end+2])
+			inArg = true
+			i += end + 1
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				current.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteByte(c)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unclosed quote in %q", s)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return args, nil
+}
+
+// isLoopbackHost reports whether a listen address only accepts connections from this machine
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// validEventSecret checks the secret of a webhook request given as token query parameter or bearer token
+func validEventSecret(r *http.Request, secret string) bool {
+	token := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+}
+
+func init() {
+	ListenCmd.Flags().String("host", "127.0.0.1", "Address to listen on, other than loopback requires --secret")
+	ListenCmd.Flags().Int("port", 8080, "Port to listen on")
+	ListenCmd.Flags().String("path", "/", "URL path accepting events")
+	ListenCmd.Flags().String("exec", "", "Command run through the shell per event with the payload on stdin")
+	ListenCmd.Flags().String("run", "", "cfctl command run per event, rendered as Go template of the payload")
+	ListenCmd.Flags().String("secret", "", "Token required as token query parameter or bearer token")
+}
@@ -0,0 +1,152 @@
+package other
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+)
+
+// lockConfigName is the domain config marking on the server that an environment is being changed
+const lockConfigName = "cfctl:operation-lock"
+
+// operationLock keeps apply and sync runs of two operators or CI jobs from changing an environment at once.
+// It is a lock file in the cache directory of the environment plus a domain config on the server,
+// which is skipped with a warning when the config service is not available.
+type operationLock struct {
+	Operation  string    `json:"operation"`
+	Holder     string    `json:"holder"`
+	Host       string    `json:"host"`
+	PID        int       `json:"pid"`
+	AcquiredAt time.Time `json:"acquired_at"`
+
+	path   string
+	remote bool
+}
+
+// acquireLock takes the lock of the current environment for an operation.
+// With force, a lock left behind by an interrupted run is removed first.
+func acquireLock(operation string, force bool) (*operationLock, error) {
+	path, err := sessionFile("operation.lock")
+	if err != nil {
+		return nil, err
+	}
+
+	host, _ := os.Hostname()
+	holder := "unknown"
+	if current, err := user.Current(); err == nil {
+		holder = current.Username
+	}
+	lock := &operationLock{
+		Operation:  operation,
+		Holder:     fmt.Sprintf("%s@%s", holder, host),
+		Host:       host,
+		PID:        os.Getpid(),
+		AcquiredAt: time.Now().UTC(),
+		path:       path,
+	}
+
+	if force {
+		os.Remove(path)
+		deleteRemoteLock()
+	}
+
+	if err := lock.acquireLocal(); err != nil {
+		return nil, err
+	}
+	if err := lock.acquireRemote(); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return lock, nil
+}
+
+// acquireLocal creates the lock file, replacing a lock of a process of this host which is gone
+func (l *operationLock) acquireLocal() error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lock: %v", err)
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(l.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			defer file.Close()
+			if _, err := file.Write(data); err != nil {
+				return fmt.Errorf("failed to write lock file: %v", err)
+			}
+			return nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("failed to create lock file: %v", err)
+		}
+
+		var held operationLock
+		if content, err := os.ReadFile(l.path); err == nil && json.Unmarshal(content, &held) == nil {
+			if held.Host != l.Host || processRunning(held.PID) {
+				return held.lockedError()
+			}
+		}
+		// The run holding the lock is gone
+		os.Remove(l.path)
+	}
+	return fmt.Errorf("failed to acquire lock file %s", l.path)
+}
+
+// acquireRemote creates the lock marker on the server
+func (l *operationLock) acquireRemote() error {
+	params, err := json.Marshal(map[string]interface{}{"name": lockConfigName, "data": l})
+	if err != nil {
+		return fmt.Errorf("failed to marshal lock: %v", err)
+	}
+
+	_, createErr := transport.FetchService("config", "create", "DomainConfig", &transport.FetchOptions{
+		JSONParameter: string(params),
+	})
+	if createErr == nil {
+		l.remote = true
+		return nil
+	}
+
+	// The marker exists when another run holds the lock
+	existing, err := transport.FetchService("config", "get", "DomainConfig", &transport.FetchOptions{
+		Parameters: []string{"name=" + lockConfigName},
+	})
+	if err != nil || existing == nil {
+		pterm.Warning.Printf("Locking on the server is not available, only this machine is locked: %v\n", createErr)
+		return nil
+	}
+
+	var held operationLock
+	if data, err := json.Marshal(existing["data"]); err == nil {
+		json.Unmarshal(data, &held)
+	}
+	return held.lockedError()
+}
+
+// release removes the lock of the run
+func (l *operationLock) release() {
+	if l.remote {
+		deleteRemoteLock()
+	}
+	os.Remove(l.path)
+}
+
+// lockedError describes the run holding a lock
+func (l *operationLock) lockedError() error {
+	return fmt.Errorf("the environment is locked by %s of %s (pid %d) since %s, "+
+		"retry later or use --force-unlock if that run was interrupted",
+		l.Operation, l.Holder, l.PID, l.AcquiredAt.Local().Format(time.RFC3339))
+}
+
+// deleteRemoteLock removes the lock marker on the server
+func deleteRemoteLock() {
+	transport.FetchService("config", "delete", "DomainConfig", &transport.FetchOptions{
+		Parameters: []string{"name=" + lockConfigName},
+	})
+}
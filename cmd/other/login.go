@@ -21,6 +21,8 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/cloudforet-io/cfctl/pkg/i18n"
 	"github.com/eiannone/keyboard"
 
 	"google.golang.org/grpc/metadata"
@@ -28,7 +30,6 @@ import (
 
 	"github.com/jhump/protoreflect/dynamic"
 
-	"github.com/jhump/protoreflect/grpcreflect"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -36,7 +37,6 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 )
 
 //const encryptionKey = "spaceone-cfctl-encryption-key-32byte"
@@ -46,14 +46,33 @@ const (
 	keyringUser    = "encryption-key"
 )
 
-var providedUrl string
+// Auth types of spaceone.api.identity.v2.Token.issue
+const (
+	authTypeLocal    int32 = 1
+	authTypeExternal int32 = 2
+)
+
+var (
+	providedUrl  string
+	browserLogin bool
+	oidcIssuer   string
+	oidcClientID string
+	idToken      string
+	loginDomain  string
+	workspaceID  string
+	domainScope  bool
+)
 
 // LoginCmd represents the login command
 var LoginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Login to SpaceONE",
 	Long: `A command that allows you to login to SpaceONE.
-It will prompt you for your User ID, Password, and fetch the Domain ID automatically, then fetch the token.`,
+It will prompt you for your User ID, Password, and fetch the Domain ID automatically, then fetch the token.
+With --browser, it signs in through the SSO flow of the console instead, or through the
+device code flow of an external OIDC provider when an issuer is configured.
+With --id-token, a token of a federated identity provider is exchanged for a SpaceONE token,
+use it together with --workspace-id or --domain-scope to sign in without prompts, e.g. in CI.`,
 	Run: executeLogin,
 }
 
@@ -63,9 +82,10 @@ type tokenAuth struct {
 }
 
 func (t *tokenAuth) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
-	return map[string]string{
-		"token": t.token, // Use "token" key instead of "Authorization: Bearer"
-	}, nil
+	// Use the "token" key unless the environment configures another auth header
+	currentEnv, _ := currentEnvironment()
+	key, value := configs.LoadAuthHeader(currentEnv).Metadata(t.token)
+	return map[string]string{key: value}, nil
 }
 
 func (t *tokenAuth) RequireTransportSecurity() bool {
@@ -104,12 +124,28 @@ func executeLogin(cmd *cobra.Command, args []string) {
 
 	// Check if it's an app environment
 	if strings.HasSuffix(currentEnv, "-app") {
-		pterm.DefaultBox.WithTitle("App Environment Detected").
+		pterm.DefaultBox.WithTitle(i18n.T("app_env.title")).
 			WithTitleTopCenter().
 			WithRightPadding(4).
 			WithLeftPadding(4).
 			WithBoxStyle(pterm.NewStyle(pterm.FgYellow)).
-			Println("Login command is not available for app environments.\nPlease use the app token directly in your configuration file.")
+			Println(i18n.T("app_env.body"))
+		return
+	}
+
+	// Keep the tokens of the current domain and continue with the cached tokens of the requested one
+	if loginDomain != "" {
+		if active := activeDomain(currentEnv); loginDomain != active {
+			if err := switchDomain(currentEnv, active, loginDomain); err != nil {
+				pterm.Error.Println(err)
+				return
+			}
+			pterm.Info.Printf("Logging in to domain %s, the session of %s is kept for 'cfctl domain use'\n", loginDomain, active)
+		}
+	}
+
+	if browserLogin || idToken != "" {
+		executeExternalLogin(currentEnv)
 		return
 	}
 
@@ -124,7 +160,7 @@ type TokenInfo struct {
 // promptToken prompts for token input
 func promptToken() (string, error) {
 	prompt := &survey.Password{
-		Message: "Enter your token:",
+		Message: i18n.T("prompt.token"),
 	}
 
 	var token string
@@ -211,7 +247,7 @@ func promptTokenSelection(tokens []TokenInfo) (string, error) {
 
 	selectedIndex := 0
 	for {
-		fmt.Print("\033[H\033[2J") // Clear screen
+		format.ClearScreen()
 
 		pterm.DefaultHeader.WithFullWidth().
 			WithBackgroundStyle(pterm.NewStyle(pterm.BgDarkGray)).
@@ -319,7 +355,7 @@ func executeAppLogin(currentEnv string) error {
 	}
 
 	for {
-		fmt.Print("\033[H\033[2J") // Clear screen
+		format.ClearScreen()
 
 		pterm.DefaultHeader.WithFullWidth().
 			WithBackgroundStyle(pterm.NewStyle(pterm.BgDarkGray)).
@@ -440,7 +476,6 @@ func executeUserLogin(currentEnv string) {
 		exitWithError()
 	}
 
-	var scope string
 	if !hasIdentityService {
 		client := &http.Client{}
 
@@ -449,10 +484,10 @@ func executeUserLogin(currentEnv string) {
 		var tempUserID string
 		if userID == "" {
 			userIDInput := pterm.DefaultInteractiveTextInput
-			tempUserID, _ = userIDInput.Show("Enter your User ID")
+			tempUserID, _ = userIDInput.Show(i18n.T("prompt.user_id"))
 		} else {
 			tempUserID = userID
-			pterm.Info.Printf("Logging in as: %s\n", userID)
+			pterm.Info.Println(i18n.T("login.as", userID))
 		}
 
 		var accessToken, refreshToken string
@@ -462,7 +497,7 @@ func executeUserLogin(currentEnv string) {
 			refreshToken = existingRefreshToken
 		} else {
 			passwordInput := pterm.DefaultInteractiveTextInput.WithMask("*")
-			password, _ := passwordInput.Show("Enter your password")
+			password, _ := passwordInput.Show(i18n.T("prompt.password"))
 
 			endpoint := mainViper.GetString(fmt.Sprintf("environments.%s.endpoint", currentEnv))
 			if endpoint == "" {
@@ -478,7 +513,7 @@ func executeUserLogin(currentEnv string) {
 				pterm.Error.Printf("Invalid endpoint format: %s\n", endpoint)
 				exitWithError()
 			}
-			domainName := parts[0]
+			domainName := loginDomainName(currentEnv, parts[0])
 
 			domainPayload := map[string]string{"name": domainName}
 			jsonPayload, _ := json.Marshal(domainPayload)
@@ -563,63 +598,9 @@ func executeUserLogin(currentEnv string) {
 			exitWithError()
 		}
 
-		// Create cache directory and save tokens
-		envCacheDir := filepath.Join(homeDir, ".cfctl", "cache", currentEnv)
-		if err := os.MkdirAll(envCacheDir, 0700); err != nil {
-			pterm.Error.Printf("Failed to create cache directory: %v\n", err)
-			exitWithError()
-		}
-
 		pterm.Info.Printf("Logged in as %s\n", tempUserID)
 
-		// Use the tokens to fetch workspaces and role
-		workspaces, err := fetchWorkspaces(restIdentityEndpoint, identityEndpoint, hasIdentityService, accessToken)
-		if err != nil {
-			pterm.Error.Println("Failed to fetch workspaces:", err)
-			exitWithError()
-		}
-
-		domainID, roleType, err := fetchDomainIDAndRole(restIdentityEndpoint, identityEndpoint, hasIdentityService, accessToken)
-		if err != nil {
-			pterm.Error.Println("Failed to fetch Domain ID and Role Type:", err)
-			exitWithError()
-		}
-
-		// Determine scope and select workspace
-		scope = determineScope(roleType, len(workspaces))
-		var workspaceID string
-		if roleType == "DOMAIN_ADMIN" {
-			workspaceID = selectScopeOrWorkspace(workspaces, roleType)
-			if workspaceID == "0" {
-				scope = "DOMAIN"
-				workspaceID = ""
-			} else {
-				scope = "WORKSPACE"
-			}
-		} else {
-			workspaceID = selectWorkspaceOnly(workspaces)
-			scope = "WORKSPACE"
-		}
-
-		// Grant new token using the refresh token
-		newAccessToken, err := grantToken(restIdentityEndpoint, identityEndpoint, hasIdentityService, refreshToken, scope, domainID, workspaceID)
-		if err != nil {
-			pterm.Error.Println("Failed to retrieve new access token:", err)
-			exitWithError()
-		}
-
-		// Save all tokens
-		if err := os.WriteFile(filepath.Join(envCacheDir, "refresh_token"), []byte(refreshToken), 0600); err != nil {
-			pterm.Error.Printf("Failed to save refresh token: %v\n", err)
-			exitWithError()
-		}
-
-		if err := os.WriteFile(filepath.Join(envCacheDir, "access_token"), []byte(newAccessToken), 0600); err != nil {
-			pterm.Error.Printf("Failed to save access token: %v\n", err)
-			exitWithError()
-		}
-
-		pterm.Success.Println("Successfully logged in and saved token.")
+		completeUserLogin(currentEnv, restIdentityEndpoint, identityEndpoint, hasIdentityService, accessToken, refreshToken)
 		return
 	} else {
 		// Extract domain name from environment
@@ -628,7 +609,7 @@ func executeUserLogin(currentEnv string) {
 			pterm.Error.Println("Environment name format is invalid.")
 			exitWithError()
 		}
-		name := nameParts[0]
+		name := loginDomainName(currentEnv, nameParts[0])
 
 		// Check for existing user_id in config
 		userID := mainViper.GetString(fmt.Sprintf("environments.%s.user_id", currentEnv))
@@ -636,10 +617,10 @@ func executeUserLogin(currentEnv string) {
 
 		if userID == "" {
 			userIDInput := pterm.DefaultInteractiveTextInput
-			tempUserID, _ = userIDInput.Show("Enter your User ID")
+			tempUserID, _ = userIDInput.Show(i18n.T("prompt.user_id"))
 		} else {
 			tempUserID = userID
-			pterm.Info.Printf("Logging in as: %s\n", userID)
+			pterm.Info.Println(i18n.T("login.as", userID))
 		}
 
 		// Fetch Domain ID
@@ -669,62 +650,83 @@ func executeUserLogin(currentEnv string) {
 			}
 		}
 
-		// Use the tokens to fetch workspaces and role
-		workspaces, err := fetchWorkspaces(restIdentityEndpoint, identityEndpoint, hasIdentityService, accessToken)
-		if err != nil {
-			pterm.Error.Println("Failed to fetch workspaces:", err)
-			exitWithError()
-		}
+		completeUserLogin(currentEnv, restIdentityEndpoint, identityEndpoint, hasIdentityService, accessToken, refreshToken)
+	}
+}
 
-		domainID, roleType, err := fetchDomainIDAndRole(restIdentityEndpoint, identityEndpoint, hasIdentityService, accessToken)
-		if err != nil {
-			pterm.Error.Println("Failed to fetch Domain ID and Role Type:", err)
+// completeUserLogin selects the scope and workspace of the user, grants an access token for them
+// and saves the tokens to the cache of the environment
+func completeUserLogin(currentEnv, restIdentityEndpoint, identityEndpoint string, hasIdentityService bool, accessToken, refreshToken string) {
+	homeDir, _ := os.UserHomeDir()
+
+	// Use the tokens to fetch workspaces and role
+	workspaces, err := fetchWorkspaces(restIdentityEndpoint, identityEndpoint, hasIdentityService, accessToken)
+	if err != nil {
+		pterm.Error.Println("Failed to fetch workspaces:", err)
+		exitWithError()
+	}
+
+	domainID, roleType, err := fetchDomainIDAndRole(restIdentityEndpoint, identityEndpoint, hasIdentityService, accessToken)
+	if err != nil {
+		pterm.Error.Println("Failed to fetch Domain ID and Role Type:", err)
+		exitWithError()
+	}
+
+	// Determine scope and select workspace, without prompting when given by flags
+	scope := determineScope(roleType, len(workspaces))
+	selectedWorkspaceID := workspaceID
+	switch {
+	case domainScope:
+		if roleType != "DOMAIN_ADMIN" {
+			pterm.Error.Println("--domain-scope requires the DOMAIN_ADMIN role")
 			exitWithError()
 		}
-
-		// Determine scope and select workspace
-		scope = determineScope(roleType, len(workspaces))
-		var workspaceID string
-		if roleType == "DOMAIN_ADMIN" {
-			workspaceID = selectScopeOrWorkspace(workspaces, roleType)
-			if workspaceID == "0" {
-				scope = "DOMAIN"
-				workspaceID = ""
-			} else {
-				scope = "WORKSPACE"
-			}
+		scope = "DOMAIN"
+	case selectedWorkspaceID != "":
+		scope = "WORKSPACE"
+	case roleType == "DOMAIN_ADMIN":
+		selectedWorkspaceID = selectScopeOrWorkspace(workspaces, roleType)
+		if selectedWorkspaceID == "0" {
+			scope = "DOMAIN"
+			selectedWorkspaceID = ""
 		} else {
-			workspaceID = selectWorkspaceOnly(workspaces)
 			scope = "WORKSPACE"
 		}
+	default:
+		selectedWorkspaceID = selectWorkspaceOnly(workspaces)
+		scope = "WORKSPACE"
+	}
 
-		// Grant new token using the refresh token
-		newAccessToken, err := grantToken("", identityEndpoint, hasIdentityService, refreshToken, scope, domainID, workspaceID)
-		if err != nil {
-			pterm.Error.Println("Failed to retrieve new access token:", err)
-			exitWithError()
-		}
+	// Grant new token using the refresh token
+	newAccessToken, err := grantToken(restIdentityEndpoint, identityEndpoint, hasIdentityService, refreshToken, scope, domainID, selectedWorkspaceID)
+	if err != nil {
+		pterm.Error.Println("Failed to retrieve new access token:", err)
+		exitWithError()
+	}
 
-		// Create cache directory
-		envCacheDir := filepath.Join(homeDir, ".cfctl", "cache", currentEnv)
-		if err := os.MkdirAll(envCacheDir, 0700); err != nil {
-			pterm.Error.Printf("Failed to create cache directory: %v\n", err)
-			exitWithError()
-		}
+	// Create cache directory
+	envCacheDir := filepath.Join(homeDir, ".cfctl", "cache", currentEnv)
+	if err := os.MkdirAll(envCacheDir, 0700); err != nil {
+		pterm.Error.Printf("Failed to create cache directory: %v\n", err)
+		exitWithError()
+	}
 
-		// Save tokens
-		if err := os.WriteFile(filepath.Join(envCacheDir, "refresh_token"), []byte(refreshToken), 0600); err != nil {
-			pterm.Error.Printf("Failed to save refresh token: %v\n", err)
-			exitWithError()
-		}
+	// Save tokens
+	if err := os.WriteFile(filepath.Join(envCacheDir, "refresh_token"), []byte(refreshToken), 0600); err != nil {
+		pterm.Error.Printf("Failed to save refresh token: %v\n", err)
+		exitWithError()
+	}
 
-		if err := os.WriteFile(filepath.Join(envCacheDir, "access_token"), []byte(newAccessToken), 0600); err != nil {
-			pterm.Error.Printf("Failed to save access token: %v\n", err)
-			exitWithError()
-		}
+	if err := os.WriteFile(filepath.Join(envCacheDir, "access_token"), []byte(newAccessToken), 0600); err != nil {
+		pterm.Error.Printf("Failed to save access token: %v\n", err)
+		exitWithError()
+	}
 
-		pterm.Success.Println("Successfully logged in and saved token.")
+	if err := saveDomainTokens(currentEnv, activeDomain(currentEnv)); err != nil {
+		pterm.Warning.Printf("Failed to save tokens of the domain: %v\n", err)
 	}
+
+	pterm.Success.Println("Successfully logged in and saved token.")
 }
 
 // GetIdentityEndpoint fetches the identity service endpoint from the API endpoint
@@ -793,7 +795,7 @@ func GetIdentityEndpoint(apiEndpoint string) (string, bool, error) {
 // Prompt for password when token is expired
 func promptPassword() string {
 	passwordInput := pterm.DefaultInteractiveTextInput.WithMask("*")
-	password, _ := passwordInput.Show("Enter your password")
+	password, _ := passwordInput.Show(i18n.T("prompt.password"))
 	return password
 }
 
@@ -969,12 +971,12 @@ func verifyAppToken(token string) (map[string]interface{}, bool) {
 	}
 
 	if time.Now().After(time.Unix(int64(exp), 0)) {
-		pterm.DefaultBox.WithTitle("Expired App Token").
+		pterm.DefaultBox.WithTitle(i18n.T("expired_app_token.title")).
 			WithTitleTopCenter().
 			WithRightPadding(4).
 			WithLeftPadding(4).
 			WithBoxStyle(pterm.NewStyle(pterm.FgRed)).
-			Println("Your App token has expired.\nPlease generate a new App and update your config file.")
+			Println(i18n.T("expired_app_token.body"))
 		return nil, false
 	}
 
@@ -985,12 +987,12 @@ func verifyAppToken(token string) (map[string]interface{}, bool) {
 	}
 
 	if role != "DOMAIN_ADMIN" && role != "WORKSPACE_OWNER" {
-		pterm.DefaultBox.WithTitle("Invalid App Token").
+		pterm.DefaultBox.WithTitle(i18n.T("invalid_app_token.title")).
 			WithTitleTopCenter().
 			WithRightPadding(4).
 			WithLeftPadding(4).
 			WithBoxStyle(pterm.NewStyle(pterm.FgRed)).
-			Println("App token must have either DOMAIN_ADMIN or WORKSPACE_OWNER role.\nPlease generate a new App with appropriate permissions and update your config file.")
+			Println(i18n.T("invalid_app_token.body"))
 		return nil, false
 	}
 
@@ -1039,11 +1041,10 @@ func loadEnvironmentConfig() {
 	containsIdentity := strings.Contains(strings.ToLower(providedUrl), "identity")
 
 	if !isProxyEnabled && !containsIdentity {
-		pterm.DefaultBox.WithTitle("Proxy Mode Required").
+		pterm.DefaultBox.WithTitle(i18n.T("proxy_required.title")).
 			WithTitleTopCenter().
 			WithBoxStyle(pterm.NewStyle(pterm.FgYellow)).
-			Println("Current endpoint is not configured for identity service.\n" +
-				"Please enable proxy mode and set identity endpoint first.")
+			Println(i18n.T("proxy_required.body"))
 
 		pterm.DefaultBox.WithBoxStyle(pterm.NewStyle(pterm.FgCyan)).
 			Println("$ cfctl setting endpoint -s identity\n" +
@@ -1099,26 +1100,17 @@ func fetchDomainID(baseUrl string, name string) (string, error) {
 	hostPort := parts[1]
 
 	// Configure gRPC connection
-	var opts []grpc.DialOption
-	if strings.HasPrefix(baseUrl, "grpc+ssl://") {
-		tlsConfig := &tls.Config{
-			InsecureSkipVerify: false,
-		}
-		creds := credentials.NewTLS(tlsConfig)
-		opts = append(opts, grpc.WithTransportCredentials(creds))
-	} else {
-		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	}
+	opts := []grpc.DialOption{configs.Credentials(strings.HasPrefix(baseUrl, "grpc+ssl://"))}
 
 	// Establish connection
-	conn, err := grpc.Dial(hostPort, opts...)
+	conn, err := configs.Dial(hostPort, opts...)
 	if err != nil {
 		return "", fmt.Errorf("failed to connect: %v", err)
 	}
 	defer conn.Close()
 
 	// Create reflection client
-	refClient := grpcreflect.NewClient(context.Background(), grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+	refClient := configs.NewReflectionClient(context.Background(), conn)
 	defer refClient.Reset()
 
 	// Resolve the service
@@ -1157,6 +1149,15 @@ func fetchDomainID(baseUrl string, name string) (string, error) {
 }
 
 func issueToken(baseUrl, userID, password, domainID string) (string, string, error) {
+	localCredentials := map[string]string{
+		"user_id":  userID,
+		"password": password,
+	}
+	return issueTokenWithCredentials(baseUrl, localCredentials, authTypeLocal, domainID)
+}
+
+// issueTokenWithCredentials issues a token with the credentials of an auth type through the identity gRPC service
+func issueTokenWithCredentials(baseUrl string, tokenCredentials map[string]string, authType int32, domainID string) (string, string, error) {
 	// Parse the endpoint
 	parts := strings.Split(baseUrl, "://")
 	if len(parts) != 2 {
@@ -1166,26 +1167,17 @@ func issueToken(baseUrl, userID, password, domainID string) (string, string, err
 	hostPort := parts[1]
 
 	// Configure gRPC connection
-	var opts []grpc.DialOption
-	if strings.HasPrefix(baseUrl, "grpc+ssl://") {
-		tlsConfig := &tls.Config{
-			InsecureSkipVerify: false,
-		}
-		creds := credentials.NewTLS(tlsConfig)
-		opts = append(opts, grpc.WithTransportCredentials(creds))
-	} else {
-		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	}
+	opts := []grpc.DialOption{configs.Credentials(strings.HasPrefix(baseUrl, "grpc+ssl://"))}
 
 	// Establish connection
-	conn, err := grpc.Dial(hostPort, opts...)
+	conn, err := configs.Dial(hostPort, opts...)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to connect: %v", err)
 	}
 	defer conn.Close()
 
 	// Create reflection client
-	refClient := grpcreflect.NewClient(context.Background(), grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+	refClient := configs.NewReflectionClient(context.Background(), conn)
 	defer refClient.Reset()
 
 	// Resolve the service
@@ -1205,24 +1197,14 @@ func issueToken(baseUrl, userID, password, domainID string) (string, string, err
 	reqMsg := dynamic.NewMessage(methodDesc.GetInputType())
 
 	// Create credentials struct using protobuf types
-	structpb := &structpb.Struct{
-		Fields: map[string]*structpb.Value{
-			"user_id": {
-				Kind: &structpb.Value_StringValue{
-					StringValue: userID,
-				},
-			},
-			"password": {
-				Kind: &structpb.Value_StringValue{
-					StringValue: password,
-				},
-			},
-		},
+	credentialsStruct := &structpb.Struct{Fields: map[string]*structpb.Value{}}
+	for key, value := range tokenCredentials {
+		credentialsStruct.Fields[key] = structpb.NewStringValue(value)
 	}
 
 	// Set all fields in the request message
-	reqMsg.SetFieldByName("credentials", structpb)
-	reqMsg.SetFieldByName("auth_type", int32(1)) // LOCAL = 1
+	reqMsg.SetFieldByName("credentials", credentialsStruct)
+	reqMsg.SetFieldByName("auth_type", authType)
 	reqMsg.SetFieldByName("timeout", int32(0))
 	reqMsg.SetFieldByName("verify_code", "")
 	reqMsg.SetFieldByName("domain_id", domainID)
@@ -1316,11 +1298,7 @@ func fetchWorkspaces(baseUrl string, identityEndpoint string, hasIdentityService
 		// Configure gRPC connection
 		var opts []grpc.DialOption
 		if strings.HasPrefix(identityEndpoint, "grpc+ssl://") {
-			tlsConfig := &tls.Config{
-				InsecureSkipVerify: false,
-			}
-			creds := credentials.NewTLS(tlsConfig)
-			opts = append(opts, grpc.WithTransportCredentials(creds))
+			opts = append(opts, configs.Credentials(true))
 		} else if strings.HasPrefix(identityEndpoint, "grpc://") {
 			tlsConfig := &tls.Config{
 				InsecureSkipVerify: true,
@@ -1338,14 +1316,14 @@ func fetchWorkspaces(baseUrl string, identityEndpoint string, hasIdentityService
 		opts = append(opts, grpc.WithPerRPCCredentials(creds))
 
 		// Establish connection
-		conn, err := grpc.Dial(hostPort, opts...)
+		conn, err := configs.Dial(hostPort, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect: %v", err)
 		}
 		defer conn.Close()
 
 		// Create reflection client
-		refClient := grpcreflect.NewClient(context.Background(), grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+		refClient := configs.NewReflectionClient(context.Background(), conn)
 		defer refClient.Reset()
 
 		// Resolve the service
@@ -1365,8 +1343,10 @@ func fetchWorkspaces(baseUrl string, identityEndpoint string, hasIdentityService
 		reqMsg := dynamic.NewMessage(methodDesc.GetInputType())
 
 		// Create metadata with token
+		currentEnv, _ := currentEnvironment()
+		authKey, authValue := configs.LoadAuthHeader(currentEnv).Metadata(accessToken)
 		md := metadata.New(map[string]string{
-			"token": accessToken,
+			authKey: authValue,
 		})
 		ctx := metadata.NewOutgoingContext(context.Background(), md)
 
@@ -1469,29 +1449,20 @@ func fetchDomainIDAndRole(baseUrl string, identityEndpoint string, hasIdentitySe
 		hostPort := parts[1]
 
 		// Configure gRPC connection
-		var opts []grpc.DialOption
-		if strings.HasPrefix(identityEndpoint, "grpc+ssl://") {
-			tlsConfig := &tls.Config{
-				InsecureSkipVerify: false,
-			}
-			creds := credentials.NewTLS(tlsConfig)
-			opts = append(opts, grpc.WithTransportCredentials(creds))
-		} else {
-			opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-		}
+		opts := []grpc.DialOption{configs.Credentials(strings.HasPrefix(identityEndpoint, "grpc+ssl://"))}
 
 		// Add token to metadata
 		opts = append(opts, grpc.WithPerRPCCredentials(&tokenAuth{token: accessToken}))
 
 		// Establish connection
-		conn, err := grpc.Dial(hostPort, opts...)
+		conn, err := configs.Dial(hostPort, opts...)
 		if err != nil {
 			return "", "", fmt.Errorf("failed to connect: %v", err)
 		}
 		defer conn.Close()
 
 		// Create reflection client
-		refClient := grpcreflect.NewClient(context.Background(), grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+		refClient := configs.NewReflectionClient(context.Background(), conn)
 		defer refClient.Reset()
 
 		// Resolve the service
@@ -1607,26 +1578,17 @@ func grantToken(restIdentityEndpoint, identityEndpoint string, hasIdentityServic
 		hostPort := parts[1]
 
 		// Configure gRPC connection
-		var opts []grpc.DialOption
-		if strings.HasPrefix(identityEndpoint, "grpc+ssl://") {
-			tlsConfig := &tls.Config{
-				InsecureSkipVerify: false,
-			}
-			creds := credentials.NewTLS(tlsConfig)
-			opts = append(opts, grpc.WithTransportCredentials(creds))
-		} else {
-			opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-		}
+		opts := []grpc.DialOption{configs.Credentials(strings.HasPrefix(identityEndpoint, "grpc+ssl://"))}
 
 		// Establish connection
-		conn, err := grpc.Dial(hostPort, opts...)
+		conn, err := configs.Dial(hostPort, opts...)
 		if err != nil {
 			return "", fmt.Errorf("failed to connect: %v", err)
 		}
 		defer conn.Close()
 
 		// Create reflection client
-		refClient := grpcreflect.NewClient(context.Background(), grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+		refClient := configs.NewReflectionClient(context.Background(), conn)
 		defer refClient.Reset()
 
 		// Resolve the service
@@ -1740,7 +1702,7 @@ func selectScopeOrWorkspace(workspaces []map[string]interface{}, roleType string
 	selectedIndex := 0
 
 	for {
-		fmt.Print("\033[H\033[2J")
+		format.ClearScreen()
 
 		// Display scope selection
 		pterm.DefaultHeader.WithFullWidth().
@@ -1811,7 +1773,7 @@ func selectWorkspaceOnly(workspaces []map[string]interface{}) string {
 
 	for {
 		// Clear screen
-		fmt.Print("\033[H\033[2J")
+		format.ClearScreen()
 
 		// Apply search filter
 		if searchTerm != "" {
@@ -1960,6 +1922,13 @@ func filterWorkspaces(workspaces []map[string]interface{}, searchTerm string) []
 
 func init() {
 	LoginCmd.Flags().StringVarP(&providedUrl, "url", "u", "", "The URL to use for login (e.g. cfctl login -u https://example.com)")
+	LoginCmd.Flags().BoolVar(&browserLogin, "browser", false, "Sign in through the browser with SSO instead of a password")
+	LoginCmd.Flags().StringVar(&oidcIssuer, "oidc-issuer", "", "Issuer URL of an external OIDC provider for --browser, signs in with the device code flow (or set 'oidc.issuer' in the environment)")
+	LoginCmd.Flags().StringVar(&loginDomain, "domain", "", "Log in to another domain of the environment, keeping the session of the current one (see 'cfctl domain')")
+	LoginCmd.Flags().StringVar(&idToken, "id-token", "", "Exchange an ID token of an external identity provider for a SpaceONE token ('-' reads it from stdin)")
+	LoginCmd.Flags().StringVar(&workspaceID, "workspace-id", "", "Workspace to sign in to without prompting")
+	LoginCmd.Flags().BoolVar(&domainScope, "domain-scope", false, "Sign in with the domain scope without prompting (DOMAIN_ADMIN only)")
+	LoginCmd.Flags().StringVar(&oidcClientID, "oidc-client-id", "", "Client ID registered at the OIDC provider (or set 'oidc.client_id' in the environment)")
 }
 
 // decodeJWT decodes a JWT token and returns the claims
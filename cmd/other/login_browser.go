@@ -0,0 +1,401 @@
+package other
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+// browserLoginTimeout is how long cfctl waits for the browser to complete the login
+const browserLoginTimeout = 5 * time.Minute
+
+// browserLoginPath is the console page starting the SSO flow and redirecting back to cfctl
+const browserLoginPath = "/sign-in"
+
+// browserCallbackPage is shown in the browser after the tokens were received
+const browserCallbackPage = `<html><body><h3>cfctl login complete</h3><p>You can close this window and return to the terminal.</p></body></html>`
+
+// executeExternalLogin signs in with an identity provider instead of a password:
+// with an ID token obtained elsewhere, through the browser with the SSO flow of the console
+// or with the OIDC device code flow of an external identity provider
+func executeExternalLogin(currentEnv string) {
+	loadEnvironmentConfig()
+
+	baseUrl := providedUrl
+	if baseUrl == "" {
+		pterm.Error.Println("No token endpoint specified in the configuration file.")
+		exitWithError()
+	}
+
+	apiEndpoint, err := configs.GetAPIEndpoint(baseUrl)
+	if err != nil {
+		pterm.Error.Printf("Failed to get API endpoint: %v\n", err)
+		exitWithError()
+	}
+	restIdentityEndpoint := apiEndpoint + "/identity"
+
+	identityEndpoint, hasIdentityService, err := configs.GetIdentityEndpoint(apiEndpoint)
+	if err != nil {
+		pterm.Error.Printf("Failed to get identity endpoint: %v\n", err)
+		exitWithError()
+	}
+
+	issuer := oidcIssuer
+	if issuer == "" {
+		issuer = viper.GetString(fmt.Sprintf("environments.%s.oidc.issuer", currentEnv))
+	}
+	clientID := oidcClientID
+	if clientID == "" {
+		clientID = viper.GetString(fmt.Sprintf("environments.%s.oidc.client_id", currentEnv))
+	}
+
+	var accessToken, refreshToken string
+	if idToken != "" || issuer != "" {
+		idpTokens := &oidcTokens{IDToken: idToken}
+		if idToken == "-" {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				pterm.Error.Printf("Failed to read ID token from stdin: %v\n", err)
+				exitWithError()
+			}
+			idpTokens.IDToken = strings.TrimSpace(string(data))
+		}
+
+		if idToken == "" {
+			if clientID == "" {
+				pterm.Error.Println("An OIDC client ID is required with an issuer, set it with --oidc-client-id or 'oidc.client_id' in the environment")
+				exitWithError()
+			}
+
+			idpTokens, err = deviceCodeLogin(issuer, clientID)
+			if err != nil {
+				pterm.Error.Printf("Failed to sign in with %s: %v\n", issuer, err)
+				exitWithError()
+			}
+		}
+
+		nameParts := strings.Split(currentEnv, "-")
+		if len(nameParts) < 2 {
+			pterm.Error.Println("Environment name format is invalid.")
+			exitWithError()
+		}
+
+		accessToken, refreshToken, err = issueExternalToken(restIdentityEndpoint, identityEndpoint, hasIdentityService, loginDomainName(currentEnv, nameParts[0]), idpTokens)
+		if err != nil {
+			pterm.Error.Printf("Failed to issue token: %v\n", err)
+			exitWithError()
+		}
+	} else {
+		accessToken, refreshToken, err = consoleSSOLogin(baseUrl)
+		if err != nil {
+			pterm.Error.Printf("Failed to sign in through the browser: %v\n", err)
+			exitWithError()
+		}
+	}
+
+	completeUserLogin(currentEnv, restIdentityEndpoint, identityEndpoint, hasIdentityService, accessToken, refreshToken)
+}
+
+// consoleSSOLogin opens the sign in page of the console, which redirects back to a localhost callback
+// with the tokens of the user after the SSO flow completed
+func consoleSSOLogin(consoleUrl string) (string, string, error) {
+	state, err := randomState()
+	if err != nil {
+		return "", "", err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to start callback listener: %v", err)
+	}
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr().String())
+
+	type callbackResult struct {
+		accessToken  string
+		refreshToken string
+		err          error
+	}
+	results := make(chan callbackResult, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("state") != state {
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		}
+
+		result := callbackResult{
+			accessToken:  query.Get("access_token"),
+			refreshToken: query.Get("refresh_token"),
+		}
+		if message := query.Get("error"); message != "" {
+			result.err = fmt.Errorf("login failed: %s", message)
+		} else if result.accessToken == "" || result.refreshToken == "" {
+			result.err = fmt.Errorf("tokens not found in callback")
+		}
+
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, browserCallbackPage)
+		}
+
+		select {
+		case results <- result:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Shutdown(context.Background())
+
+	loginUrl := fmt.Sprintf("%s%s?%s", strings.TrimSuffix(consoleUrl, "/"), browserLoginPath, url.Values{
+		"redirect_uri": {redirectURI},
+		"state":        {state},
+		"client":       {"cfctl"},
+	}.Encode())
+
+	pterm.Info.Println("Opening the browser to sign in. If it does not open, visit:")
+	fmt.Println(loginUrl)
+	openBrowser(loginUrl)
+
+	spinner, _ := pterm.DefaultSpinner.Start("Waiting for the login to complete in the browser...")
+	select {
+	case result := <-results:
+		if result.err != nil {
+			spinner.Fail(result.err.Error())
+			return "", "", result.err
+		}
+		spinner.Success("Received token from the browser")
+		return result.accessToken, result.refreshToken, nil
+	case <-time.After(browserLoginTimeout):
+		spinner.Fail("Timed out waiting for the browser")
+		return "", "", fmt.Errorf("login was not completed within %s", browserLoginTimeout)
+	}
+}
+
+// oidcTokens holds the tokens issued by an external identity provider
+type oidcTokens struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+}
+
+// deviceCodeLogin signs in with the OAuth 2.0 device authorization grant (RFC 8628) of an OIDC provider
+func deviceCodeLogin(issuer, clientID string) (*oidcTokens, error) {
+	discoveryUrl := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	resp, err := http.Get(discoveryUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC configuration: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var discovery struct {
+		DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+		TokenEndpoint               string `json:"token_endpoint"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+		return nil, fmt.Errorf("failed to decode OIDC configuration: %v", err)
+	}
+	if discovery.DeviceAuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("the identity provider does not support the device code flow")
+	}
+
+	resp, err = http.PostForm(discovery.DeviceAuthorizationEndpoint, url.Values{
+		"client_id": {clientID},
+		"scope":     {"openid profile email"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to request device code: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var device struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int    `json:"expires_in"`
+		Interval                int    `json:"interval"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&device); err != nil {
+		return nil, fmt.Errorf("failed to decode device code response: %v", err)
+	}
+	if device.DeviceCode == "" {
+		return nil, fmt.Errorf("device code not found in response (status %d)", resp.StatusCode)
+	}
+
+	verificationUrl := device.VerificationURIComplete
+	if verificationUrl == "" {
+		verificationUrl = device.VerificationURI
+	}
+	pterm.Info.Printf("Visit %s and enter the code: %s\n", device.VerificationURI, pterm.FgLightYellow.Sprint(device.UserCode))
+	openBrowser(verificationUrl)
+
+	interval := time.Duration(device.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	expiresIn := time.Duration(device.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = browserLoginTimeout
+	}
+	deadline := time.Now().Add(expiresIn)
+
+	spinner, _ := pterm.DefaultSpinner.Start("Waiting for the login to complete in the browser...")
+	for time.Now().Before(deadline) {
+		time.Sleep(interval)
+
+		resp, err := http.PostForm(discovery.TokenEndpoint, url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code": {device.DeviceCode},
+			"client_id":   {clientID},
+		})
+		if err != nil {
+			spinner.Fail("Failed to poll the token endpoint")
+			return nil, fmt.Errorf("failed to poll token endpoint: %v", err)
+		}
+
+		var result struct {
+			oidcTokens
+			Error string `json:"error"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			spinner.Fail("Failed to decode the token response")
+			return nil, fmt.Errorf("failed to decode token response: %v", err)
+		}
+
+		switch result.Error {
+		case "":
+			spinner.Success("Signed in with the identity provider")
+			return &result.oidcTokens, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			spinner.Fail(result.Error)
+			return nil, fmt.Errorf("login failed: %s", result.Error)
+		}
+	}
+
+	spinner.Fail("The device code expired")
+	return nil, fmt.Errorf("login was not completed before the device code expired")
+}
+
+// issueExternalToken exchanges the tokens of an external identity provider for SpaceONE tokens
+func issueExternalToken(restIdentityEndpoint, identityEndpoint string, hasIdentityService bool, domainName string, idpTokens *oidcTokens) (string, string, error) {
+	tokenCredentials := map[string]string{}
+	if idpTokens.AccessToken != "" {
+		tokenCredentials["access_token"] = idpTokens.AccessToken
+	}
+	if idpTokens.IDToken != "" {
+		tokenCredentials["id_token"] = idpTokens.IDToken
+	}
+
+	if hasIdentityService {
+		domainID, err := fetchDomainID(identityEndpoint, domainName)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to fetch Domain ID: %v", err)
+		}
+		return issueTokenWithCredentials(identityEndpoint, tokenCredentials, authTypeExternal, domainID)
+	}
+
+	var domainResult map[string]interface{}
+	if err := postIdentity(restIdentityEndpoint+"/domain/get-auth-info", map[string]interface{}{"name": domainName}, &domainResult); err != nil {
+		return "", "", fmt.Errorf("failed to fetch domain info: %v", err)
+	}
+	domainID, ok := domainResult["domain_id"].(string)
+	if !ok {
+		return "", "", fmt.Errorf("domain ID not found in response")
+	}
+
+	var tokenResult map[string]interface{}
+	payload := map[string]interface{}{
+		"credentials": tokenCredentials,
+		"auth_type":   "EXTERNAL",
+		"domain_id":   domainID,
+	}
+	if err := postIdentity(restIdentityEndpoint+"/token/issue", payload, &tokenResult); err != nil {
+		return "", "", fmt.Errorf("failed to issue token: %v", err)
+	}
+
+	accessToken, ok := tokenResult["access_token"].(string)
+	if !ok {
+		return "", "", fmt.Errorf("access token not found in response")
+	}
+	refreshToken, ok := tokenResult["refresh_token"].(string)
+	if !ok {
+		return "", "", fmt.Errorf("refresh token not found in response")
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// postIdentity posts a JSON payload to the REST identity API and decodes the response
+func postIdentity(endpoint string, payload interface{}, result interface{}) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// randomState returns an unguessable value binding the callback to this login
+func randomState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate state: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// openBrowser opens the URL in the default browser, failures are ignored since the URL is also printed
+func openBrowser(target string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	_ = cmd.Start()
+}
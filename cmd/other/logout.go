@@ -0,0 +1,123 @@
+package other
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+)
+
+// logoutFiles are the files of a login session in the cache directory of an environment
+var logoutFiles = []string{"access_token", "refresh_token", "grant_token", "admin_access_token", "session.pid"}
+
+// LogoutCmd represents the logout command
+var LogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Log out of the current environment",
+	Long: `Revoke the tokens of the current environment on the server when the identity
+service supports it, stop the session refresh daemon and remove the cached tokens,
+including the tokens of every domain logged in to with 'cfctl login --domain'.
+
+With --keyring, the key encrypting saved passwords is removed from the system
+keyring as well, so that saved passwords can no longer be decrypted.`,
+	Example: `  # Log out of the current environment
+  $ cfctl logout
+
+  # Log out of every environment and forget the keyring entry
+  $ cfctl logout --all-environments --keyring`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		allEnvironments, _ := cmd.Flags().GetBool("all-environments")
+		clearKeyring, _ := cmd.Flags().GetBool("keyring")
+
+		var environments []string
+		if allEnvironments {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("unable to find home directory: %v", err)
+			}
+			entries, err := os.ReadDir(filepath.Join(home, ".cfctl", "cache"))
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to read cache directory: %v", err)
+			}
+			for _, entry := range entries {
+				if entry.IsDir() {
+					environments = append(environments, entry.Name())
+				}
+			}
+		} else {
+			currentEnv, err := currentEnvironment()
+			if err != nil {
+				return err
+			}
+			environments = append(environments, currentEnv)
+		}
+
+		for _, env := range environments {
+			if err := logout(env); err != nil {
+				return err
+			}
+		}
+
+		if clearKeyring {
+			if err := keyring.Delete(keyringService, keyringUser); err != nil && err != keyring.ErrNotFound {
+				return fmt.Errorf("failed to remove keyring entry: %v", err)
+			}
+			pterm.Success.Println("Removed the encryption key from the keyring.")
+		}
+		return nil
+	},
+}
+
+// logout ends the login session of an environment
+func logout(env string) error {
+	envCacheDir, err := envCacheDirectory(env)
+	if err != nil {
+		return err
+	}
+
+	if process, err := sessionDaemon(filepath.Join(envCacheDir, "session.pid")); err == nil {
+		if err := process.Kill(); err != nil {
+			pterm.Warning.Printf("Failed to stop session refresh daemon of %s: %v\n", env, err)
+		}
+	}
+
+	revoked, err := transport.RevokeSession(env)
+	if err != nil {
+		pterm.Warning.Printf("Failed to revoke tokens of %s, removing them locally only: %v\n", env, err)
+	}
+
+	removed := false
+	for _, name := range logoutFiles {
+		if err := os.Remove(filepath.Join(envCacheDir, name)); err == nil {
+			removed = true
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s of %s: %v", name, env, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(envCacheDir, "domains")); err == nil {
+		if err := os.RemoveAll(filepath.Join(envCacheDir, "domains")); err != nil {
+			return fmt.Errorf("failed to remove domain tokens of %s: %v", env, err)
+		}
+		removed = true
+	}
+
+	switch {
+	case revoked:
+		pterm.Success.Printf("Logged out of %s, the tokens were revoked.\n", env)
+	case removed:
+		pterm.Success.Printf("Logged out of %s.\n", env)
+	default:
+		pterm.Info.Printf("Not logged in to %s.\n", env)
+	}
+	return nil
+}
+
+func init() {
+	LogoutCmd.Flags().Bool("all-environments", false, "Log out of every environment")
+	LogoutCmd.Flags().Bool("keyring", false, "Also remove the encryption key of saved passwords from the system keyring")
+}
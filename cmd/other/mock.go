@@ -0,0 +1,67 @@
+package other
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// MockCmd represents the mock command
+var MockCmd = &cobra.Command{
+	Use:   "mock",
+	Short: "Run a local mock of the SpaceONE APIs",
+}
+
+var mockServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve canned responses for the APIs of a protoset",
+	Long: `Start a local gRPC server that advertises the services of a protoset through
+server reflection and answers every call with a fixture file, so that scripts
+and CI can exercise cfctl workflows without a SpaceONE cluster.
+
+Fixtures are read on every call from <fixtures>/<package.Service>/<Method>.json
+in the protobuf JSON format, e.g. fixtures/spaceone.api.identity.v2.Workspace/list.json.
+A JSON array holds the messages of a server streaming method.
+Methods without a fixture fail with UNIMPLEMENTED.
+
+Point an environment at the server with 'endpoint: grpc://localhost:50051'.`,
+	Example: `  # Serve the identity and inventory APIs from local fixtures
+  $ cfctl mock serve --protoset spaceone.pb --fixtures ./fixtures
+
+  # Listen on another address
+  $ cfctl mock serve --protoset spaceone.pb --fixtures ./fixtures --address 127.0.0.1:6000`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		protoset, _ := cmd.Flags().GetString("protoset")
+		fixtures, _ := cmd.Flags().GetString("fixtures")
+		address, _ := cmd.Flags().GetString("address")
+
+		server, err := transport.NewMockServer(protoset, fixtures)
+		if err != nil {
+			return err
+		}
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+			server.Stop()
+		}()
+
+		pterm.Info.Printf("Serving %d services on %s\n", len(server.GetServiceInfo()), address)
+		return server.Serve(address)
+	},
+}
+
+func init() {
+	MockCmd.AddCommand(mockServeCmd)
+	mockServeCmd.Flags().String("protoset", "", "Descriptor set of the APIs to serve (protoc --descriptor_set_out --include_imports)")
+	mockServeCmd.Flags().String("fixtures", "", "Directory of the response fixtures")
+	mockServeCmd.Flags().String("address", "localhost:50051", "Address to listen on")
+	mockServeCmd.MarkFlagRequired("protoset")
+	mockServeCmd.MarkFlagRequired("fixtures")
+}
@@ -0,0 +1,167 @@
+package other
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/pterm/pterm"
+)
+
+// Actions of a plan, other verbs of apply are planned as calls with the verb as action
+const (
+	planCreate = "create"
+	planUpdate = "update"
+	planDelete = "delete"
+)
+
+// planUnknown stands for values which depend on the responses of earlier calls
+const planUnknown = "(known after apply)"
+
+// planAction is a change of a resource planned by apply or sync
+type planAction struct {
+	Action   string            `json:"action"`
+	Service  string            `json:"service"`
+	Resource string            `json:"resource"`
+	ID       string            `json:"id,omitempty"`
+	Name     string            `json:"name,omitempty"`
+	Changes  []planFieldChange `json:"changes,omitempty"`
+	Source   string            `json:"source,omitempty"` // Manifest file of the resource
+	spec     map[string]interface{}
+}
+
+// planFieldChange is a field whose value is set, changed or removed by an action
+type planFieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// planSummary counts the actions of a plan
+type planSummary struct {
+	Create int `json:"create"`
+	Update int `json:"update"`
+	Delete int `json:"delete"`
+	Other  int `json:"other"`
+}
+
+// planFile is the machine-readable plan written with --plan-out
+type planFile struct {
+	Summary planSummary  `json:"summary"`
+	Actions []planAction `json:"actions"`
+}
+
+// summarizePlan counts the actions of a plan by kind
+func summarizePlan(plan []planAction) planSummary {
+	var summary planSummary
+	for _, action := range plan {
+		switch action.Action {
+		case planCreate:
+			summary.Create++
+		case planUpdate:
+			summary.Update++
+		case planDelete:
+			summary.Delete++
+		default:
+			summary.Other++
+		}
+	}
+	return summary
+}
+
+// planChanges returns the fields of the desired state whose current values differ
+func planChanges(current, desired map[string]interface{}) []planFieldChange {
+	fields := make([]string, 0, len(desired))
+	for field := range desired {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var changes []planFieldChange
+	for _, field := range fields {
+		if !reflect.DeepEqual(current[field], desired[field]) {
+			changes = append(changes, planFieldChange{Field: field, Before: current[field], After: desired[field]})
+		}
+	}
+	return changes
+}
+
+// printPlan prints the actions of a plan in the style of terraform plan, marking creates with +,
+// updates with ~ and deletes with -, followed by the changed fields and a summary line
+func printPlan(plan []planAction) {
+	if len(plan) == 0 {
+		pterm.Success.Println("No changes, the environment matches the configuration.")
+		return
+	}
+
+	for _, action := range plan {
+		target := action.Service + "." + action.Resource
+		label := action.ID
+		if label == "" {
+			label = action.Name
+		} else if action.Name != "" {
+			label += fmt.Sprintf(" (%s)", action.Name)
+		}
+
+		switch action.Action {
+		case planCreate:
+			fmt.Printf("  %s %s %s\n", pterm.Green("+"), target, label)
+		case planUpdate:
+			fmt.Printf("  %s %s %s\n", pterm.Yellow("~"), target, label)
+		case planDelete:
+			fmt.Printf("  %s %s %s\n", pterm.Red("-"), target, label)
+		default:
+			fmt.Printf("  %s %s %s %s\n", pterm.Cyan("*"), target, action.Action, label)
+		}
+
+		for _, change := range action.Changes {
+			switch {
+			case action.Action == planUpdate && change.Before != nil && change.After != nil:
+				fmt.Printf("      %s %s = %s -> %s\n", pterm.Yellow("~"), change.Field,
+					pterm.Red(planValue(change.Before)), pterm.Green(planValue(change.After)))
+			case change.After == nil:
+				fmt.Printf("      %s %s = %s\n", pterm.Red("-"), change.Field, planValue(change.Before))
+			default:
+				fmt.Printf("      %s %s = %s\n", pterm.Green("+"), change.Field, planValue(change.After))
+			}
+		}
+	}
+
+	summary := summarizePlan(plan)
+	line := fmt.Sprintf("Plan: %s to create, %s to update, %s to delete",
+		pterm.Green(summary.Create), pterm.Yellow(summary.Update), pterm.Red(summary.Delete))
+	if summary.Other > 0 {
+		line += fmt.Sprintf(", %s other calls", pterm.Cyan(summary.Other))
+	}
+	fmt.Printf("\n%s.\n\n", line)
+}
+
+// planValue formats a field value of a plan as compact JSON
+func planValue(value interface{}) string {
+	if value == planUnknown {
+		return planUnknown
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// writePlanFile writes a plan as JSON so that CI can archive it and check its summary
+func writePlanFile(path string, plan []planAction) error {
+	if plan == nil {
+		plan = []planAction{}
+	}
+	data, err := json.MarshalIndent(planFile{Summary: summarizePlan(plan), Actions: plan}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal plan: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write plan: %v", err)
+	}
+	pterm.Info.Printf("Plan written to %s\n", path)
+	return nil
+}
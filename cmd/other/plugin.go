@@ -0,0 +1,191 @@
+package other
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// pluginPrefix is the executable name prefix of cfctl plugins
+const pluginPrefix = "cfctl-"
+
+// outputPluginPrefix is the executable name prefix of output format plugins (-o plugin:<name>)
+const outputPluginPrefix = pluginPrefix + "format-"
+
+// PluginCmd represents the plugin command
+var PluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage cfctl plugins",
+	Long: `Plugins are executables named cfctl-<name> found in PATH.
+Running 'cfctl <name>' for an unknown command executes the plugin with the remaining arguments.
+The current environment is passed to the plugin through the CFCTL_ENVIRONMENT,
+CFCTL_ENDPOINT, CFCTL_TOKEN and CFCTL_SETTING environment variables.`,
+}
+
+var listPluginCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all plugins found in PATH",
+	Example: `  # List installed plugins
+  $ cfctl plugin list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		plugins := findPlugins()
+		if len(plugins) == 0 {
+			pterm.Info.Println("No plugins found in PATH")
+			return
+		}
+
+		table := pterm.TableData{
+			{"Name", "Type", "Path"},
+		}
+
+		for _, plugin := range plugins {
+			name := pluginName(plugin)
+			pluginType := "command"
+			if strings.HasPrefix(filepath.Base(plugin), outputPluginPrefix) {
+				name = "plugin:" + strings.TrimPrefix(name, "format-")
+				pluginType = "output format"
+			}
+			table = append(table, []string{name, pluginType, plugin})
+		}
+
+		pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+	},
+}
+
+// LookupPlugin returns the path of the cfctl-<name> executable in PATH
+func LookupPlugin(name string) (string, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, "-") {
+		return "", false
+	}
+
+	path, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return "", false
+	}
+
+	return path, true
+}
+
+// RunPlugin executes the plugin with the given arguments and the current environment
+// and returns the exit code of the plugin.
+func RunPlugin(path string, args []string) int {
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), pluginEnv()...)
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return exitErr.ExitCode()
+		}
+		pterm.Error.Printf("Failed to run plugin %s: %v\n", filepath.Base(path), err)
+		return 1
+	}
+
+	return 0
+}
+
+// pluginEnv returns the environment variables describing the current environment.
+// Plugins still run without them when no environment is configured.
+func pluginEnv() []string {
+	var env []string
+
+	if settingPath, err := configs.GetSettingFilePath(); err == nil {
+		env = append(env, "CFCTL_SETTING="+settingPath)
+	}
+
+	config, err := configs.SetSettingFile()
+	if err != nil {
+		return env
+	}
+
+	envConfig := config.Environments[config.Environment]
+	env = append(env,
+		"CFCTL_ENVIRONMENT="+config.Environment,
+		"CFCTL_ENDPOINT="+envConfig.Endpoint,
+		"CFCTL_TOKEN="+envConfig.Token,
+	)
+
+	return env
+}
+
+// findPlugins returns the paths of all cfctl-* executables in PATH.
+// Plugins shadowed by an earlier directory in PATH are skipped.
+func findPlugins() []string {
+	seen := make(map[string]bool)
+	var plugins []string
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasPrefix(entry.Name(), pluginPrefix) {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+
+			name := pluginName(path)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, path)
+		}
+	}
+
+	sort.Slice(plugins, func(i, j int) bool {
+		return pluginName(plugins[i]) < pluginName(plugins[j])
+	})
+
+	return plugins
+}
+
+// pluginName returns the name of a plugin executable without the cfctl- prefix
+func pluginName(path string) string {
+	name := strings.TrimPrefix(filepath.Base(path), pluginPrefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+// isExecutable reports whether the file at path can be executed
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".exe", ".bat", ".cmd", ".com":
+			return true
+		}
+		return false
+	}
+
+	return info.Mode()&0111 != 0
+}
+
+func init() {
+	PluginCmd.AddCommand(listPluginCmd)
+}
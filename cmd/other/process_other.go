@@ -0,0 +1,35 @@
+//go:build !windows
+
+package other
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// processRunning reports whether a process of this host is running
+func processRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// Signal 0 checks that the process exists on Unix
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// processExecutable returns the path of the executable of a running process
+func processExecutable(pid int) (string, error) {
+	// Linux links the executable of each process in /proc, other systems report it with ps
+	if path, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid)); err == nil {
+		return path, nil
+	}
+	output, err := exec.Command("ps", "-o", "comm=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the executable of process %d: %v", pid, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
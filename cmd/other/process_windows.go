@@ -0,0 +1,45 @@
+//go:build windows
+
+package other
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is the exit code of a process which has not exited
+const stillActive = 259
+
+// processRunning reports whether a process of this host is running.
+// Signals cannot probe processes on Windows, so the exit code of the process is queried.
+func processRunning(pid int) bool {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// A process of another user exists but cannot be opened
+		return err == windows.ERROR_ACCESS_DENIED
+	}
+	defer windows.CloseHandle(handle)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(handle, &code); err != nil {
+		return true
+	}
+	return code == stillActive
+}
+
+// processExecutable returns the path of the executable of a running process
+func processExecutable(pid int) (string, error) {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return "", fmt.Errorf("failed to open process %d: %v", pid, err)
+	}
+	defer windows.CloseHandle(handle)
+
+	buf := make([]uint16, windows.MAX_LONG_PATH)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(handle, 0, &buf[0], &size); err != nil {
+		return "", fmt.Errorf("failed to find the executable of process %d: %v", pid, err)
+	}
+	return windows.UTF16ToString(buf[:size]), nil
+}
@@ -0,0 +1,112 @@
+package other
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// manifestFuncs are the functions available in manifest templates besides the builtin ones
+var manifestFuncs = template.FuncMap{
+	"default": func(fallback, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+	"required": func(message string, value interface{}) (interface{}, error) {
+		if value == nil || value == "" {
+			return nil, fmt.Errorf("%s", message)
+		}
+		return value, nil
+	},
+	"env":   os.Getenv,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"quote": func(value interface{}) string { return fmt.Sprintf("%q", fmt.Sprint(value)) },
+	"toJson": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+// addManifestValueFlags adds the flags setting the values of manifest templates to a command
+func addManifestValueFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("set", []string{}, "Set a value of the manifest templates (--set name=payments --set db.size=large)")
+	cmd.Flags().StringArray("values", []string{}, "YAML file of values of the manifest templates, later files override earlier ones")
+}
+
+// manifestValues collects the values of manifest templates from the --values files and the
+// --set pairs of a command. --set overrides the files and dotted keys set nested values.
+func manifestValues(cmd *cobra.Command) (map[string]interface{}, error) {
+	files, _ := cmd.Flags().GetStringArray("values")
+	pairs, _ := cmd.Flags().GetStringArray("set")
+
+	values := make(map[string]interface{})
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read values file: %v", err)
+		}
+		var fileValues map[string]interface{}
+		if err := yaml.Unmarshal(data, &fileValues); err != nil {
+			return nil, fmt.Errorf("failed to parse values file %s: %v", file, err)
+		}
+		mergeValues(values, fileValues)
+	}
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set %s, use key=value", pair)
+		}
+		parts := strings.Split(key, ".")
+		current := values
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := current[part].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				current[part] = next
+			}
+			current = next
+		}
+		current[parts[len(parts)-1]] = value
+	}
+	return values, nil
+}
+
+// mergeValues merges values into the target, merging nested maps
+func mergeValues(target, values map[string]interface{}) {
+	for key, value := range values {
+		if nested, ok := value.(map[string]interface{}); ok {
+			if existing, ok := target[key].(map[string]interface{}); ok {
+				mergeValues(existing, nested)
+				continue
+			}
+		}
+		target[key] = value
+	}
+}
+
+// renderManifest renders a manifest file as Go template with the values available as .Values
+// Example:
+//
+//	name: {{ .Values.env }}-payments
+func renderManifest(name string, data []byte, values map[string]interface{}) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(manifestFuncs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %v", name, err)
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, map[string]interface{}{"Values": values}); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %v", name, err)
+	}
+	return rendered.Bytes(), nil
+}
@@ -0,0 +1,297 @@
+package other
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// ReportSpec describes the calls of a report and how they are rendered
+type ReportSpec struct {
+	Title    string        `yaml:"title"`
+	Queries  []ReportQuery `yaml:"queries"`
+	Template string        `yaml:"template"`
+}
+
+// ReportQuery is a single call whose results become a section of the report
+type ReportQuery struct {
+	Name       string                 `yaml:"name"`
+	Service    string                 `yaml:"service"`
+	Verb       string                 `yaml:"verb"`
+	Resource   string                 `yaml:"resource"`
+	Parameters map[string]interface{} `yaml:"parameters"`
+	Columns    []string               `yaml:"columns"`
+	GroupBy    string                 `yaml:"group_by"`
+	Agg        string                 `yaml:"agg"`
+}
+
+// ReportSection is the rendered data of a query passed to the template
+type ReportSection struct {
+	Name    string
+	Columns []string
+	Rows    [][]string
+	Total   int
+	Results []interface{}
+}
+
+// builtinReports are report specs available by name
+var builtinReports = map[string]string{
+	"inventory-summary": `title: Inventory Summary
+queries:
+  - name: Servers by provider
+    service: inventory
+    verb: list
+    resource: Server
+    group_by: provider
+  - name: Cloud services by provider
+    service: inventory
+    verb: list
+    resource: CloudService
+    group_by: provider,cloud_service_group
+  - name: Projects
+    service: identity
+    verb: list
+    resource: Project
+    columns: [project_id, name, project_type, created_at]
+`,
+}
+
+const defaultReportTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1 { margin-bottom: 0; }
+.generated { color: #888; margin-bottom: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ddd; padding: 4px 10px; text-align: left; }
+th { background: #f4f4f4; }
+tr:nth-child(even) td { background: #fafafa; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="generated">Generated at {{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</div>
+{{range .Sections}}
+<h2>{{.Name}} <small>({{.Total}})</small></h2>
+<table>
+<tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`
+
+// ReportCmd represents the report command
+var ReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate reports from API results",
+}
+
+var reportGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a standalone HTML report from a report spec",
+	Example: `  # Generate the built-in inventory summary
+  $ cfctl report generate --template inventory-summary --output report.html
+
+  # Generate a report from your own spec
+  $ cfctl report generate --template my-report.yaml --output report.html
+
+  # my-report.yaml
+  title: Weekly Snapshot
+  queries:
+    - name: Servers
+      service: inventory
+      verb: list
+      resource: Server
+      columns: [name, provider, region_code, state]
+  template: |            # Optional Go html/template
+    <h1>{{.Title}}</h1> ...`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templateName, _ := cmd.Flags().GetString("template")
+		output, _ := cmd.Flags().GetString("output")
+
+		spec, err := loadReportSpec(templateName)
+		if err != nil {
+			return err
+		}
+
+		tmplText := spec.Template
+		if tmplText == "" {
+			tmplText = defaultReportTemplate
+		}
+		tmpl, err := template.New("report").Parse(tmplText)
+		if err != nil {
+			return fmt.Errorf("failed to parse report template: %v", err)
+		}
+
+		sections := make([]ReportSection, 0, len(spec.Queries))
+		for i, query := range spec.Queries {
+			pterm.Info.Printf("Running query %d/%d: %s\n", i+1, len(spec.Queries), query.Name)
+			section, err := runReportQuery(query)
+			if err != nil {
+				return fmt.Errorf("query '%s' failed: %v", query.Name, err)
+			}
+			sections = append(sections, *section)
+		}
+
+		file, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %v", output, err)
+		}
+		defer file.Close()
+
+		err = tmpl.Execute(file, map[string]interface{}{
+			"Title":       spec.Title,
+			"GeneratedAt": time.Now(),
+			"Sections":    sections,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to render report: %v", err)
+		}
+
+		pterm.Success.Printf("Report saved to %s\n", output)
+		return nil
+	},
+}
+
+// loadReportSpec loads a built-in report spec by name or a spec file
+func loadReportSpec(name string) (*ReportSpec, error) {
+	data := []byte(builtinReports[name])
+	if len(data) == 0 {
+		var err error
+		data, err = os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("unknown report template '%s': %v", name, err)
+		}
+	}
+
+	var spec ReportSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse report spec: %v", err)
+	}
+	if spec.Title == "" {
+		spec.Title = "cfctl report"
+	}
+	return &spec, nil
+}
+
+// runReportQuery calls the API of a query and converts the results to table rows
+func runReportQuery(query ReportQuery) (*ReportSection, error) {
+	verb := query.Verb
+	if verb == "" {
+		verb = "list"
+	}
+
+	jsonParameter := ""
+	if len(query.Parameters) > 0 {
+		jsonBytes, err := json.Marshal(query.Parameters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal parameters: %v", err)
+		}
+		jsonParameter = string(jsonBytes)
+	}
+
+	resp, err := transport.FetchService(query.Service, verb, query.Resource, &transport.FetchOptions{
+		JSONParameter: jsonParameter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("no response")
+	}
+
+	results, ok := resp["results"].([]interface{})
+	if !ok {
+		results = []interface{}{resp}
+	}
+
+	if query.GroupBy != "" {
+		results, err = format.AggregateResults(results, query.GroupBy, query.Agg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	columns := query.Columns
+	if len(columns) == 0 {
+		columns = reportColumns(results)
+	}
+
+	rows := make([][]string, 0, len(results))
+	for _, result := range results {
+		row := make([]string, len(columns))
+		if resultMap, ok := result.(map[string]interface{}); ok {
+			for i, col := range columns {
+				if val, ok := format.GetValueByPath(resultMap, col); ok {
+					row[i] = reportValue(val)
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return &ReportSection{
+		Name:    query.Name,
+		Columns: columns,
+		Rows:    rows,
+		Total:   len(results),
+		Results: results,
+	}, nil
+}
+
+// reportValue renders a value as plain text without terminal colors
+func reportValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		jsonBytes, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(jsonBytes)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// reportColumns collects the top-level fields of the results
+func reportColumns(results []interface{}) []string {
+	seen := make(map[string]bool)
+	var columns []string
+	for _, result := range results {
+		if resultMap, ok := result.(map[string]interface{}); ok {
+			for key := range resultMap {
+				if !seen[key] {
+					seen[key] = true
+					columns = append(columns, key)
+				}
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func init() {
+	ReportCmd.AddCommand(reportGenerateCmd)
+
+	reportGenerateCmd.Flags().StringP("template", "t", "inventory-summary", "Built-in report name or path to a report spec YAML")
+	reportGenerateCmd.Flags().StringP("output", "o", "report.html", "Output HTML file")
+}
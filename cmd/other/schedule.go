@@ -0,0 +1,383 @@
+package other
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// ScheduledCommand is a cfctl command run by the schedule runner on a cron expression
+type ScheduledCommand struct {
+	ID   string   `yaml:"id"`
+	Cron string   `yaml:"cron"`
+	Args []string `yaml:"args"`
+}
+
+// ScheduleCmd represents the schedule command
+var ScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Run cfctl commands on a schedule",
+	Long: `Store cfctl commands with cron expressions and run them with 'cfctl schedule run',
+which uses the login and environment of cfctl, where setting up system cron with
+the right authentication is hard. Schedules are kept in ~/.cfctl/schedules.yaml and
+the results are logged to ~/.cfctl/schedule.log.`,
+}
+
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add <cron> -- <command>...",
+	Short: "Schedule a cfctl command",
+	Example: `  # Export cloud services every morning at 7
+  $ cfctl schedule add "0 7 * * *" -- export inventory CloudService --dir ~/backups
+
+  # Renew the session every hour
+  $ cfctl schedule add @hourly -- session refresh`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.ArgsLenAtDash() != 1 {
+			return fmt.Errorf("separate the cron expression and the command with --")
+		}
+
+		spec := args[0]
+		if _, err := cron.ParseStandard(spec); err != nil {
+			return fmt.Errorf("invalid cron expression '%s': %v", spec, err)
+		}
+
+		schedules, err := loadSchedules()
+		if err != nil {
+			return err
+		}
+
+		id, err := scheduleID()
+		if err != nil {
+			return err
+		}
+		schedules = append(schedules, ScheduledCommand{ID: id, Cron: spec, Args: args[1:]})
+		if err := saveSchedules(schedules); err != nil {
+			return err
+		}
+
+		pterm.Success.Printf("Scheduled 'cfctl %s' at '%s' as %s\n", strings.Join(args[1:], " "), spec, id)
+		if _, err := scheduleDaemon(); err != nil {
+			pterm.Info.Println("Start the runner with: cfctl schedule run --daemon")
+		}
+		return nil
+	},
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the scheduled commands",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schedules, err := loadSchedules()
+		if err != nil {
+			return err
+		}
+		if len(schedules) == 0 {
+			pterm.Info.Println("No commands scheduled.")
+			return nil
+		}
+
+		now := time.Now()
+		table := pterm.TableData{{"ID", "Cron", "Command", "Next Run"}}
+		for _, schedule := range schedules {
+			next := "-"
+			if parsed, err := cron.ParseStandard(schedule.Cron); err == nil {
+				next = parsed.Next(now).Format("2006-01-02 15:04")
+			}
+			table = append(table, []string{schedule.ID, schedule.Cron, "cfctl " + strings.Join(schedule.Args, " "), next})
+		}
+		pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+
+		if process, err := scheduleDaemon(); err == nil {
+			pterm.Info.Printf("The runner is running (pid %d)\n", process.Pid)
+		} else {
+			pterm.Warning.Println("The runner is not running, start it with: cfctl schedule run --daemon")
+		}
+		return nil
+	},
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove a scheduled command",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schedules, err := loadSchedules()
+		if err != nil {
+			return err
+		}
+
+		kept := schedules[:0]
+		for _, schedule := range schedules {
+			if schedule.ID != args[0] {
+				kept = append(kept, schedule)
+			}
+		}
+		if len(kept) == len(schedules) {
+			return fmt.Errorf("no scheduled command with id %s", args[0])
+		}
+
+		if err := saveSchedules(kept); err != nil {
+			return err
+		}
+		pterm.Success.Printf("Removed scheduled command %s\n", args[0])
+		return nil
+	},
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run the scheduled commands when they are due",
+	Long: `Run the scheduled commands when their cron expressions are due until stopped.
+Changes to the schedules are picked up without restarting the runner.
+With --daemon, the runner is started in the background.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		daemon, _ := cmd.Flags().GetBool("daemon")
+		if daemon {
+			return startScheduleDaemon()
+		}
+		return runSchedules()
+	},
+}
+
+var scheduleStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the background schedule runner",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		process, err := scheduleDaemon()
+		if err != nil {
+			pterm.Info.Println("The schedule runner is not running.")
+			return nil
+		}
+		if err := process.Kill(); err != nil {
+			return fmt.Errorf("failed to stop the schedule runner: %v", err)
+		}
+
+		if pidPath, err := schedulePath("schedule.pid"); err == nil {
+			os.Remove(pidPath)
+		}
+		pterm.Success.Printf("Stopped the schedule runner (pid %d)\n", process.Pid)
+		return nil
+	},
+}
+
+// startScheduleDaemon starts a detached cfctl process running the scheduled commands
+func startScheduleDaemon() error {
+	if process, err := scheduleDaemon(); err == nil {
+		pterm.Info.Printf("The schedule runner is already running (pid %d)\n", process.Pid)
+		return nil
+	}
+
+	pidPath, err := schedulePath("schedule.pid")
+	if err != nil {
+		return err
+	}
+	logPath, err := schedulePath("schedule.log")
+	if err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open schedule log: %v", err)
+	}
+	defer logFile.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find cfctl executable: %v", err)
+	}
+
+	runner := exec.Command(executable, "schedule", "run")
+	runner.Stdout = logFile
+	runner.Stderr = logFile
+	if err := runner.Start(); err != nil {
+		return fmt.Errorf("failed to start the schedule runner: %v", err)
+	}
+
+	if err := os.WriteFile(pidPath, []byte(strconv.Itoa(runner.Process.Pid)), 0600); err != nil {
+		return fmt.Errorf("failed to save runner pid: %v", err)
+	}
+	_ = runner.Process.Release()
+
+	pterm.Success.Printf("Started the schedule runner (pid %d)\n", runner.Process.Pid)
+	pterm.Info.Printf("Log: %s\n", logPath)
+	return nil
+}
+
+// runSchedules runs the scheduled commands which are due every minute.
+// The schedules are read again every minute so that added and removed commands take effect.
+func runSchedules() error {
+	signal.Ignore(syscall.SIGHUP)
+	entry := func(format string, args ...interface{}) string {
+		return fmt.Sprintf("%s %s\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, args...))
+	}
+	log := func(format string, args ...interface{}) {
+		fmt.Print(entry(format, args...))
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find cfctl executable: %v", err)
+	}
+
+	// Schedules whose previous run is still active, which are skipped instead of stacking up
+	var runningMu sync.Mutex
+	running := make(map[string]bool)
+
+	log("schedule runner started")
+	for {
+		tick := time.Now().Truncate(time.Minute).Add(time.Minute)
+		time.Sleep(time.Until(tick))
+
+		schedules, err := loadSchedules()
+		if err != nil {
+			log("failed to load schedules: %v", err)
+			continue
+		}
+
+		for _, schedule := range schedules {
+			parsed, err := cron.ParseStandard(schedule.Cron)
+			if err != nil {
+				log("skipping %s with invalid cron expression '%s': %v", schedule.ID, schedule.Cron, err)
+				continue
+			}
+			if !parsed.Next(tick.Add(-time.Second)).Equal(tick) {
+				continue
+			}
+
+			runningMu.Lock()
+			if running[schedule.ID] {
+				runningMu.Unlock()
+				log("skipping %s, its previous run is still active", schedule.ID)
+				continue
+			}
+			running[schedule.ID] = true
+			runningMu.Unlock()
+
+			go func(schedule ScheduledCommand) {
+				defer func() {
+					runningMu.Lock()
+					delete(running, schedule.ID)
+					runningMu.Unlock()
+				}()
+
+				started := time.Now()
+				output, err := exec.Command(executable, schedule.Args...).CombinedOutput()
+				status := "succeeded"
+				if err != nil {
+					status = fmt.Sprintf("failed (%v)", err)
+				}
+
+				// Write the entry with its output at once, so that entries of concurrent runs do not interleave
+				var sb strings.Builder
+				sb.WriteString(entry("%s 'cfctl %s' %s in %s", schedule.ID, strings.Join(schedule.Args, " "), status, time.Since(started).Round(time.Millisecond)))
+				if len(output) > 0 {
+					sb.Write(output)
+					if !strings.HasSuffix(string(output), "\n") {
+						sb.WriteByte('\n')
+					}
+				}
+				fmt.Print(sb.String())
+			}(schedule)
+		}
+	}
+}
+
+// loadSchedules reads the scheduled commands
+func loadSchedules() ([]ScheduledCommand, error) {
+	path, err := schedulePath("schedules.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read schedules: %v", err)
+	}
+
+	var file struct {
+		Schedules []ScheduledCommand `yaml:"schedules"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse schedules: %v", err)
+	}
+	return file.Schedules, nil
+}
+
+// saveSchedules writes the scheduled commands
+func saveSchedules(schedules []ScheduledCommand) error {
+	path, err := schedulePath("schedules.yaml")
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(map[string]interface{}{"schedules": schedules})
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedules: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save schedules: %v", err)
+	}
+	return nil
+}
+
+// scheduleDaemon returns the running schedule runner
+func scheduleDaemon() (*os.Process, error) {
+	pidPath, err := schedulePath("schedule.pid")
+	if err != nil {
+		return nil, err
+	}
+	return sessionDaemon(pidPath)
+}
+
+// schedulePath returns the path of a file of the schedule runner in the cfctl directory
+func schedulePath(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find home directory: %v", err)
+	}
+
+	dir := filepath.Join(home, ".cfctl")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create cfctl directory: %v", err)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// scheduleID returns a short random id for a scheduled command
+func scheduleID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate schedule id: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func init() {
+	ScheduleCmd.AddCommand(scheduleAddCmd)
+	ScheduleCmd.AddCommand(scheduleListCmd)
+	ScheduleCmd.AddCommand(scheduleRemoveCmd)
+	ScheduleCmd.AddCommand(scheduleRunCmd)
+	ScheduleCmd.AddCommand(scheduleStopCmd)
+	scheduleRunCmd.Flags().Bool("daemon", false, "Run the scheduled commands in a background process")
+}
@@ -0,0 +1,143 @@
+package other
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// searchResult is the outcome of the keyword query of a resource type
+type searchResult struct {
+	target  configs.SearchTarget
+	results []interface{}
+	err     error
+}
+
+// SearchCmd represents the search command
+var SearchCmd = &cobra.Command{
+	Use:   "search <keyword>",
+	Short: "Search resources of several services by keyword",
+	Long: `Query the resource types of the search section of the setting file by keyword
+at the same time and print the matches in one table, tagged with their type.
+Servers, projects, users and service accounts are searched by default.`,
+	Example: `  # Find everything related to payments
+  $ cfctl search payments
+
+  # Search only servers and projects
+  $ cfctl search payments --type Server --type Project
+
+  # Configure the searched resource types in ~/.cfctl/setting.yaml
+  search:
+    targets:
+      - service: inventory
+        resource: CloudService
+      - service: identity
+        resource: Project`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keyword := args[0]
+		types, _ := cmd.Flags().GetStringSlice("type")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		var targets []configs.SearchTarget
+		for _, target := range configs.LoadSearchTargets() {
+			if len(types) == 0 || containsFold(types, target.Resource) {
+				if target.IDField == "" {
+					target.IDField = transport.ResourceIDField(target.Resource)
+				}
+				targets = append(targets, target)
+			}
+		}
+		if len(targets) == 0 {
+			return fmt.Errorf("no resource types to search, check --type and the search section of the setting file")
+		}
+
+		outcomes := make([]searchResult, len(targets))
+		var wg sync.WaitGroup
+		for i, target := range targets {
+			wg.Add(1)
+			go func(i int, target configs.SearchTarget) {
+				defer wg.Done()
+				results, err := searchResources(target, keyword, limit)
+				outcomes[i] = searchResult{target: target, results: results, err: err}
+			}(i, target)
+		}
+		wg.Wait()
+
+		table := pterm.TableData{{"Type", "ID", "Name", "Get"}}
+		for _, outcome := range outcomes {
+			if outcome.err != nil {
+				pterm.Warning.Printf("Failed to search %s.%s: %v\n", outcome.target.Service, outcome.target.Resource, outcome.err)
+				continue
+			}
+			for _, result := range outcome.results {
+				row, ok := result.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				id, _ := row[outcome.target.IDField].(string)
+				name, _ := row["name"].(string)
+				table = append(table, []string{
+					fmt.Sprintf("%s.%s", outcome.target.Service, outcome.target.Resource),
+					id,
+					name,
+					fmt.Sprintf("cfctl %s get %s -p %s=%s", outcome.target.Service, outcome.target.Resource, outcome.target.IDField, id),
+				})
+			}
+		}
+
+		if len(table) == 1 {
+			pterm.Info.Printf("No resources found for '%s'\n", keyword)
+			return nil
+		}
+		pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+		return nil
+	},
+}
+
+// searchResources lists the resources of a type matching the keyword
+func searchResources(target configs.SearchTarget, keyword string, limit int) ([]interface{}, error) {
+	query, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"keyword": keyword,
+			"page":    map[string]interface{}{"limit": limit},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal search query: %v", err)
+	}
+
+	resp, err := transport.FetchService(target.Service, "list", target.Resource, &transport.FetchOptions{
+		JSONParameter: string(query),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("no response")
+	}
+
+	results, _ := resp["results"].([]interface{})
+	return results, nil
+}
+
+// containsFold reports whether the values contain the value ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	SearchCmd.Flags().StringSlice("type", []string{}, "Search only these resource types (--type Server --type Project)")
+	SearchCmd.Flags().Int("limit", 20, "Maximum number of matches per resource type")
+}
@@ -0,0 +1,60 @@
+package other
+
+import (
+	"sort"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// serviceNamesCmd manages the endpoint names of services
+var serviceNamesCmd = &cobra.Command{
+	Use:   "service-names",
+	Short: "Manage the endpoint names of services",
+	Long: `Services are reached at endpoints named after them, with underscores replaced by
+hyphens unless a name is configured (e.g. cost_analysis -> cost-analysis). Configure
+the names of services released after this version of cfctl in the service_names
+section of the setting file.`,
+}
+
+var serviceNamesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the configured endpoint names of services",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names := configs.LoadServiceNames()
+		services := make([]string, 0, len(names))
+		for service := range names {
+			services = append(services, service)
+		}
+		sort.Strings(services)
+
+		table := pterm.TableData{{"Service", "Endpoint Name"}}
+		for _, service := range services {
+			table = append(table, []string{service, names[service]})
+		}
+		pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+		return nil
+	},
+}
+
+var serviceNamesSetCmd = &cobra.Command{
+	Use:   "set <service> <endpoint_name>",
+	Short: "Set the endpoint name of a service",
+	Example: `  # Reach the new_service service at new-svc.<domain>
+  $ cfctl setting service-names set new_service new-svc`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := configs.SetServiceName(args[0], args[1]); err != nil {
+			return err
+		}
+		pterm.Success.Printf("Service %s is reached at %s\n", args[0], args[1])
+		return nil
+	},
+}
+
+func init() {
+	serviceNamesCmd.AddCommand(serviceNamesListCmd)
+	serviceNamesCmd.AddCommand(serviceNamesSetCmd)
+}
@@ -0,0 +1,321 @@
+package other
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// sessionRetryInterval is how long the daemon waits before retrying a failed refresh
+const sessionRetryInterval = time.Minute
+
+// SessionCmd represents the session command
+var SessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Manage the login session of user environments",
+}
+
+var sessionRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Renew the access token of the current user environment",
+	Long: `Grant a new access token with the cached refresh token, keeping the scope and
+workspace of the current token. The token file is replaced atomically, so running
+commands are not affected.
+
+With --daemon, a background process renews the token shortly before it expires
+until the refresh token expires, so that long running watch and export commands
+do not fail with authentication errors. Tokens renewed by another cfctl, e.g. a
+login in another terminal, are picked up. Its log is written to session.log in
+the cache directory of the environment.`,
+	Example: `  # Renew the access token once
+  $ cfctl session refresh
+
+  # Keep the session alive in the background
+  $ cfctl session refresh --daemon
+
+  # Stop the background process
+  $ cfctl session stop`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		daemon, _ := cmd.Flags().GetBool("daemon")
+		loop, _ := cmd.Flags().GetBool("loop")
+		before, _ := cmd.Flags().GetDuration("before")
+
+		if loop {
+			return runSessionLoop(before)
+		}
+		if daemon {
+			return startSessionDaemon(before)
+		}
+
+		status, err := transport.RefreshSession()
+		if err != nil {
+			return err
+		}
+		pterm.Success.Printf("Renewed the %s scope token of %s, it expires %s\n",
+			status.Scope, status.Environment, format.FormatRelativeTime(status.ExpiresAt))
+		return nil
+	},
+}
+
+var sessionStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the session refresh daemon of the current environment",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pidPath, err := sessionFile("session.pid")
+		if err != nil {
+			return err
+		}
+
+		process, err := sessionDaemon(pidPath)
+		if err != nil {
+			pterm.Info.Println("No session refresh daemon is running.")
+			return nil
+		}
+
+		if err := process.Kill(); err != nil {
+			return fmt.Errorf("failed to stop session refresh daemon: %v", err)
+		}
+		os.Remove(pidPath)
+		pterm.Success.Printf("Stopped session refresh daemon (pid %d)\n", process.Pid)
+		return nil
+	},
+}
+
+// startSessionDaemon starts a detached cfctl process running the refresh loop
+func startSessionDaemon(before time.Duration) error {
+	// Refresh once in the foreground so that errors are reported right away
+	status, err := transport.RefreshSession()
+	if err != nil {
+		return err
+	}
+
+	pidPath, err := sessionFile("session.pid")
+	if err != nil {
+		return err
+	}
+	if process, err := sessionDaemon(pidPath); err == nil {
+		pterm.Info.Printf("Session refresh daemon is already running (pid %d)\n", process.Pid)
+		return nil
+	}
+
+	logPath, err := sessionFile("session.log")
+	if err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open session log: %v", err)
+	}
+	defer logFile.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find cfctl executable: %v", err)
+	}
+
+	daemon := exec.Command(executable, "session", "refresh", "--loop", "--before", before.String())
+	daemon.Stdout = logFile
+	daemon.Stderr = logFile
+	if err := daemon.Start(); err != nil {
+		return fmt.Errorf("failed to start session refresh daemon: %v", err)
+	}
+
+	if err := os.WriteFile(pidPath, []byte(strconv.Itoa(daemon.Process.Pid)), 0600); err != nil {
+		return fmt.Errorf("failed to save daemon pid: %v", err)
+	}
+	_ = daemon.Process.Release()
+
+	pterm.Success.Printf("Started session refresh daemon for %s (pid %d), the session lasts until %s\n",
+		status.Environment, daemon.Process.Pid, status.RefreshExpires.Format(time.RFC3339))
+	pterm.Info.Printf("Log: %s\n", logPath)
+	return nil
+}
+
+// runSessionLoop renews the access token before it expires until the refresh token expires
+// or the current environment changes
+func runSessionLoop(before time.Duration) error {
+	signal.Ignore(syscall.SIGHUP)
+	log := func(format string, args ...interface{}) {
+		fmt.Printf("%s %s\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, args...))
+	}
+
+	environment := ""
+	var watcher *configs.SettingWatcher
+	for {
+		if setting, err := configs.SetSettingFile(); err == nil {
+			if environment == "" {
+				environment = setting.Environment
+			} else if setting.Environment != environment {
+				log("environment changed from %s to %s, stopping", environment, setting.Environment)
+				return nil
+			}
+		}
+		if watcher == nil && environment != "" {
+			if w, err := configs.WatchSettings(environment); err == nil {
+				watcher = w
+				defer watcher.Close()
+			}
+		}
+
+		status, err := transport.RefreshSession()
+		if err != nil {
+			if errors.Is(err, transport.ErrLoginRequired) {
+				log("%v, stopping", err)
+				return err
+			}
+			log("refresh failed, retrying in %s: %v", sessionRetryInterval, err)
+			time.Sleep(sessionRetryInterval)
+			continue
+		}
+
+		next := nextSessionRefresh(status.ExpiresAt, status.RefreshExpires, before)
+		log("renewed %s scope token of %s, expires at %s, next refresh in %s",
+			status.Scope, status.Environment, status.ExpiresAt.Format(time.RFC3339), next.Round(time.Second))
+		if watcher == nil {
+			time.Sleep(next)
+			continue
+		}
+		if !waitSessionRefresh(watcher, status, before, next, log) {
+			return nil
+		}
+	}
+}
+
+// waitSessionRefresh waits until the next refresh while following the setting file and the token cache.
+// A token renewed by another cfctl, e.g. a login in another terminal, moves the refresh, and a change
+// of the current environment ends the loop, which is reported by returning false.
+func waitSessionRefresh(watcher *configs.SettingWatcher, status *transport.SessionStatus, before, next time.Duration, log func(string, ...interface{})) bool {
+	timer := time.NewTimer(next)
+	defer timer.Stop()
+
+	expiresAt := status.ExpiresAt
+	for {
+		select {
+		case <-timer.C:
+			return true
+		case path, ok := <-watcher.Changes:
+			if !ok {
+				<-timer.C
+				return true
+			}
+
+			if filepath.Base(path) == "setting.yaml" {
+				if setting, err := configs.SetSettingFile(); err == nil && setting.Environment != status.Environment {
+					log("environment changed from %s to %s, stopping", status.Environment, setting.Environment)
+					return false
+				}
+				continue
+			}
+
+			// Our own refresh is reported as well, but leaves the expiry unchanged
+			renewed, err := transport.AccessTokenExpiry()
+			if err != nil || renewed.Equal(expiresAt) {
+				continue
+			}
+			expiresAt = renewed
+			next := nextSessionRefresh(expiresAt, status.RefreshExpires, before)
+			timer.Reset(next)
+			log("access token of %s was renewed elsewhere, expires at %s, next refresh in %s",
+				status.Environment, expiresAt.Format(time.RFC3339), next.Round(time.Second))
+		}
+	}
+}
+
+// nextSessionRefresh returns how long to wait before renewing a token, at most until the session ends
+// and at least the retry interval
+func nextSessionRefresh(expiresAt, refreshExpires time.Time, before time.Duration) time.Duration {
+	next := time.Until(expiresAt) - before
+	if untilEnd := time.Until(refreshExpires); untilEnd < next {
+		next = untilEnd
+	}
+	if next < sessionRetryInterval {
+		next = sessionRetryInterval
+	}
+	return next
+}
+
+// sessionDaemon returns the running daemon recorded in the pid file.
+// The pid file is removed when the process is gone or the pid was reused by another program,
+// so that a stale pid file never makes cfctl stop an unrelated process.
+func sessionDaemon(pidPath string) (*os.Process, error) {
+	data, err := os.ReadFile(pidPath)
+	if err != nil {
+		return nil, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, err
+	}
+	if !processRunning(pid) {
+		os.Remove(pidPath)
+		return nil, fmt.Errorf("daemon %d is not running", pid)
+	}
+	if !cfctlProcess(pid) {
+		os.Remove(pidPath)
+		return nil, fmt.Errorf("process %d is not a cfctl daemon", pid)
+	}
+	return os.FindProcess(pid)
+}
+
+// cfctlProcess reports whether a process runs the executable of cfctl
+func cfctlProcess(pid int) bool {
+	path, err := processExecutable(pid)
+	if err != nil {
+		return false
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return false
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		if selfInfo, err := os.Stat(self); err == nil && os.SameFile(info, selfInfo) {
+			return true
+		}
+	}
+	// The executable may have been replaced since the daemon started, e.g. by an upgrade
+	return strings.EqualFold(filepath.Base(strings.TrimSuffix(path, " (deleted)")), filepath.Base(self))
+}
+
+// sessionFile returns the path of a file in the cache directory of the current environment
+func sessionFile(name string) (string, error) {
+	setting, err := configs.SetSettingFile()
+	if err != nil {
+		return "", fmt.Errorf("failed to load setting: %v", err)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %v", err)
+	}
+
+	dir := filepath.Join(home, ".cfctl", "cache", setting.Environment)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+func init() {
+	SessionCmd.AddCommand(sessionRefreshCmd)
+	SessionCmd.AddCommand(sessionStopCmd)
+	sessionRefreshCmd.Flags().Bool("daemon", false, "Keep renewing the token in a background process")
+	sessionRefreshCmd.Flags().Duration("before", 10*time.Minute, "How long before expiry the token is renewed")
+	sessionRefreshCmd.Flags().Bool("loop", false, "Run the refresh loop in the foreground")
+	sessionRefreshCmd.Flags().MarkHidden("loop")
+}
@@ -3,7 +3,6 @@ package other
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -20,12 +19,8 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 
 	"github.com/jhump/protoreflect/dynamic"
-	"github.com/jhump/protoreflect/grpcreflect"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -39,8 +34,9 @@ type ServiceEndpoint struct {
 
 // SettingCmd represents the setting command
 var SettingCmd = &cobra.Command{
-	Use:   "setting",
-	Short: "Manage cfctl setting file",
+	Use:     "setting",
+	Aliases: []string{"config"},
+	Short:   "Manage cfctl setting file",
 	Long: `Manage setting file for cfctl. 
 You can initialize, switch environments, and display the current configuration.`,
 }
@@ -662,33 +658,26 @@ You can either specify a new endpoint URL directly or use the service-based endp
 				endpointName = strings.Join(parts[:len(parts)-1], "/")
 				parts = strings.Split(endpointName, "://")
 				if len(parts) != 2 {
-					fmt.Errorf("invalid endpoint format: %s", endpointName)
+					pterm.Error.Printf("invalid endpoint format: %s\n", endpointName)
+					return
 				}
 
 				scheme := parts[0]
 				hostPort := parts[1]
 
 				// Configure gRPC connection based on scheme
-				var opts []grpc.DialOption
-				if scheme == "grpc+ssl" {
-					tlsConfig := &tls.Config{
-						InsecureSkipVerify: false, // Enable server certificate verification
-					}
-					creds := credentials.NewTLS(tlsConfig)
-					opts = append(opts, grpc.WithTransportCredentials(creds))
-				} else {
-					opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-				}
+				opts := []grpc.DialOption{configs.Credentials(scheme == "grpc+ssl")}
 
 				// Establish the connection
-				conn, err := grpc.Dial(hostPort, opts...)
+				conn, err := configs.Dial(hostPort, opts...)
 				if err != nil {
-					fmt.Errorf("connection failed: unable to connect to %s: %v", endpointName, err)
+					pterm.Error.Printf("connection failed: unable to connect to %s: %v\n", endpointName, err)
+					return
 				}
 				defer conn.Close()
 
 				// Use Reflection to discover services
-				refClient := grpcreflect.NewClient(context.Background(), grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+				refClient := configs.NewReflectionClient(context.Background(), conn)
 				defer refClient.Reset()
 
 				// Resolve the service and method
@@ -697,12 +686,14 @@ You can either specify a new endpoint URL directly or use the service-based endp
 
 				serviceDesc, err := refClient.ResolveService(serviceName)
 				if err != nil {
-					fmt.Errorf("failed to resolve service %s: %v", serviceName, err)
+					pterm.Error.Printf("failed to resolve service %s: %v\n", serviceName, err)
+					return
 				}
 
 				methodDesc := serviceDesc.FindMethodByName(methodName)
 				if methodDesc == nil {
-					fmt.Errorf("method not found: %s", methodName)
+					pterm.Error.Printf("method not found: %s\n", methodName)
+					return
 				}
 
 				// Dynamically create the request message
@@ -725,14 +716,16 @@ You can either specify a new endpoint URL directly or use the service-based endp
 				// Invoke the gRPC method
 				err = conn.Invoke(context.Background(), fullMethod, reqMsg, respMsg)
 				if err != nil {
-					fmt.Errorf("failed to invoke method %s: %v", fullMethod, err)
+					pterm.Error.Printf("failed to invoke method %s: %v\n", fullMethod, err)
+					return
 				}
 
 				// Process the response to extract `service` and `endpoint`
 				endpoints = make(map[string]string)
 				resultsField := respMsg.FindFieldDescriptorByName("results")
 				if resultsField == nil {
-					fmt.Errorf("'results' field not found in response")
+					pterm.Error.Printf("'results' field not found in response\n")
+					return
 				}
 
 				results := respMsg.GetField(resultsField).([]interface{})
@@ -894,14 +887,14 @@ func invokeGRPCEndpointList(hostPort string, opts []grpc.DialOption) (map[string
 	}()
 
 	// Establish the connection
-	conn, err := grpc.Dial(hostPort, opts...)
+	conn, err := configs.Dial(hostPort, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("connection failed: unable to connect to %s: %v", hostPort, err)
 	}
 	defer conn.Close()
 
 	// Use Reflection to discover services
-	refClient := grpcreflect.NewClient(context.Background(), grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+	refClient := configs.NewReflectionClient(context.Background(), conn)
 	defer refClient.Reset()
 
 	serviceName := "spaceone.api.identity.v2.Endpoint"
@@ -1046,11 +1039,7 @@ func fetchAvailableServices(identityEndpoint, restIdentityEndpoint string, hasId
 
 		// Set up TLS credentials if the scheme is grpc+ssl://
 		if strings.HasPrefix(identityEndpoint, "grpc+ssl://") {
-			tlsSetting := &tls.Config{
-				InsecureSkipVerify: false, // Set to true only if you want to skip TLS verification (not recommended)
-			}
-			creds := credentials.NewTLS(tlsSetting)
-			opts = append(opts, grpc.WithTransportCredentials(creds))
+			opts = append(opts, configs.Credentials(true))
 		} else {
 			return nil, fmt.Errorf("unsupported scheme in endpoint: %s", identityEndpoint)
 		}
@@ -1061,7 +1050,7 @@ func fetchAvailableServices(identityEndpoint, restIdentityEndpoint string, hasId
 		}
 
 		// Establish a connection to the gRPC server
-		conn, err := grpc.Dial(fmt.Sprintf("%s:%s", host, port), opts...)
+		conn, err := configs.Dial(fmt.Sprintf("%s:%s", host, port), opts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to dial gRPC endpoint: %w", err)
 		}
@@ -1070,7 +1059,7 @@ func fetchAvailableServices(identityEndpoint, restIdentityEndpoint string, hasId
 		ctx := context.Background()
 
 		// Create a reflection client to discover services and methods
-		refClient := grpcreflect.NewClient(ctx, grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+		refClient := configs.NewReflectionClient(ctx, conn)
 		defer refClient.Reset()
 
 		// Resolve the service descriptor for "spaceone.api.identity.v2.Endpoint"
@@ -1432,8 +1421,7 @@ func getInternalEndpoint(endpoint string) (string, error) {
 		}
 		defer conn.Close()
 
-		client := grpc_reflection_v1alpha.NewServerReflectionClient(conn)
-		refClient := grpcreflect.NewClient(context.Background(), client)
+		refClient := configs.NewReflectionClient(context.Background(), conn)
 		defer refClient.Reset()
 
 		serviceName := "spaceone.api.identity.v2.Endpoint"
@@ -1642,6 +1630,7 @@ func init() {
 	SettingCmd.AddCommand(settingTokenCmd)
 	SettingCmd.AddCommand(envCmd)
 	SettingCmd.AddCommand(showCmd)
+	SettingCmd.AddCommand(serviceNamesCmd)
 	settingInitCmd.AddCommand(settingInitProxyCmd)
 	settingInitCmd.AddCommand(settingInitStaticCmd)
 
@@ -0,0 +1,389 @@
+package other
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// The tag marking resources created or updated by sync, which --prune deletes when their manifest is removed
+const (
+	syncManagedKey   = "managed_by"
+	syncManagedValue = "cfctl"
+)
+
+// syncManifest is a resource of a manifest file with the file it was read from
+type syncManifest struct {
+	ResourceSpec
+	source string
+}
+
+// SyncCmd represents the sync command
+var SyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Reconcile a directory of resource manifests with the environment",
+	Long: `Read the resource manifests of a directory and make the environment match them:
+missing resources are created and drifted ones updated. Resources are matched by
+their id field when the manifest has one, otherwise by name.
+
+Created and updated resources are tagged managed_by=cfctl. With --prune, tagged
+resources of the synced types which no manifest describes anymore are deleted.
+The plan is printed before anything is changed and can be written as JSON with
+--plan-out for CI to archive and check.`,
+	Example: `  # A manifest in ./spaceone-config/projects.yaml
+  service: identity
+  resource: Project
+  spec:
+    name: payments
+    tags:
+      team: billing
+
+  # Show what would change in production, manifests are Go templates of the values
+  $ cfctl sync --dir ./spaceone-config --values prd.yaml --set env=prd --dry-run
+
+  # Reconcile and delete the managed resources removed from the directory
+  $ cfctl sync --dir ./spaceone-config --prune --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := cmd.Flags().GetString("dir")
+		prune, _ := cmd.Flags().GetBool("prune")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		planOut, _ := cmd.Flags().GetString("plan-out")
+		useLock, _ := cmd.Flags().GetBool("lock")
+		forceUnlock, _ := cmd.Flags().GetBool("force-unlock")
+
+		values, err := manifestValues(cmd)
+		if err != nil {
+			return err
+		}
+		manifests, err := loadSyncManifests(dir, values)
+		if err != nil {
+			return err
+		}
+		if len(manifests) == 0 {
+			return fmt.Errorf("no resource manifests found in %s", dir)
+		}
+
+		// Keep other runs from changing the environment until this one is done
+		if useLock && !dryRun {
+			lock, err := acquireLock("sync", forceUnlock)
+			if err != nil {
+				return err
+			}
+			defer lock.release()
+		}
+
+		plan, err := syncPlan(manifests, prune)
+		if err != nil {
+			return err
+		}
+
+		printPlan(plan)
+		if planOut != "" {
+			if err := writePlanFile(planOut, plan); err != nil {
+				return err
+			}
+		}
+		if len(plan) == 0 || dryRun {
+			return nil
+		}
+
+		if !yes {
+			confirmed := false
+			prompt := &survey.Confirm{Message: fmt.Sprintf("Apply %d changes?", len(plan))}
+			if err := survey.AskOne(prompt, &confirmed); err != nil {
+				return err
+			}
+			if !confirmed {
+				pterm.Info.Println("Sync cancelled.")
+				return nil
+			}
+		}
+
+		return applySyncPlan(plan)
+	},
+}
+
+// loadSyncManifests renders and reads the resource manifests of the YAML files in a directory and its subdirectories
+func loadSyncManifests(dir string, values map[string]interface{}) ([]syncManifest, error) {
+	var manifests []syncManifest
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", path, err)
+		}
+		data, err = renderManifest(path, data, values)
+		if err != nil {
+			return err
+		}
+		specs, err := parseResourceSpecs(data)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		for i, spec := range specs {
+			if spec.Service == "" || spec.Resource == "" {
+				return fmt.Errorf("%s: manifest %d needs service and resource", path, i+1)
+			}
+			manifests = append(manifests, syncManifest{ResourceSpec: spec, source: path})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manifests: %v", err)
+	}
+	return manifests, nil
+}
+
+// syncPlan compares the manifests with the live resources and returns the changes to make
+func syncPlan(manifests []syncManifest, prune bool) ([]planAction, error) {
+	var plan []planAction
+	managed := make(map[string]map[string]bool) // Ids of the resources described by manifests per type
+
+	for _, manifest := range manifests {
+		typeKey := manifest.Service + "." + manifest.Resource
+		if managed[typeKey] == nil {
+			managed[typeKey] = make(map[string]bool)
+		}
+
+		idField := transport.ResourceIDField(manifest.Resource)
+		live, err := findSyncResource(manifest.ResourceSpec, idField)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", manifest.source, err)
+		}
+
+		spec, err := normalizeSpec(manifest.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", manifest.source, err)
+		}
+		name, _ := spec["name"].(string)
+
+		if live == nil {
+			desired := withManagedTag(spec, nil)
+			delete(desired, idField)
+			plan = append(plan, planAction{
+				Action: planCreate, Service: manifest.Service, Resource: manifest.Resource,
+				Name: name, Changes: planChanges(nil, desired), Source: manifest.source, spec: desired,
+			})
+			continue
+		}
+
+		id, _ := live[idField].(string)
+		managed[typeKey][id] = true
+
+		desired := withManagedTag(spec, live)
+		if changes := planChanges(live, desired); len(changes) > 0 {
+			desired[idField] = id
+			plan = append(plan, planAction{
+				Action: planUpdate, Service: manifest.Service, Resource: manifest.Resource,
+				ID: id, Name: name, Changes: changes, Source: manifest.source, spec: desired,
+			})
+		}
+	}
+
+	if prune {
+		typeKeys := make([]string, 0, len(managed))
+		for typeKey := range managed {
+			typeKeys = append(typeKeys, typeKey)
+		}
+		sort.Strings(typeKeys)
+
+		for _, typeKey := range typeKeys {
+			serviceName, resourceName, _ := strings.Cut(typeKey, ".")
+			idField := transport.ResourceIDField(resourceName)
+			results, err := listSyncResources(serviceName, resourceName, map[string]interface{}{
+				"k": "tags." + syncManagedKey, "v": syncManagedValue, "o": "eq",
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list managed %s: %v", typeKey, err)
+			}
+			for _, result := range results {
+				id, _ := result[idField].(string)
+				if id == "" || managed[typeKey][id] {
+					continue
+				}
+				name, _ := result["name"].(string)
+				plan = append(plan, planAction{
+					Action: planDelete, Service: serviceName, Resource: resourceName, ID: id, Name: name,
+				})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// findSyncResource returns the live resource of a manifest, matched by its id field or name
+func findSyncResource(spec ResourceSpec, idField string) (map[string]interface{}, error) {
+	key := idField
+	value, ok := spec.Spec[idField].(string)
+	if !ok || value == "" {
+		key = "name"
+		value, ok = spec.Spec["name"].(string)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%s.%s needs %s or name to be matched", spec.Service, spec.Resource, idField)
+		}
+	}
+
+	results, err := listSyncResources(spec.Service, spec.Resource, map[string]interface{}{"k": key, "v": value, "o": "eq"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s %s: %v", spec.Resource, value, err)
+	}
+
+	switch len(results) {
+	case 0:
+		return nil, nil
+	case 1:
+		return results[0], nil
+	default:
+		return nil, fmt.Errorf("%s %s '%s' is ambiguous, %d resources match", spec.Resource, key, value, len(results))
+	}
+}
+
+// listSyncResources lists the resources of a type matching a filter
+func listSyncResources(serviceName, resourceName string, filter map[string]interface{}) ([]map[string]interface{}, error) {
+	query, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{"filter": []interface{}{filter}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %v", err)
+	}
+
+	resp, err := transport.FetchService(serviceName, "list", resourceName, &transport.FetchOptions{
+		JSONParameter: string(query),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// No response without an error means that no call was made, e.g. without a token
+	if resp == nil {
+		return nil, fmt.Errorf("not authenticated for this environment")
+	}
+
+	var results []map[string]interface{}
+	rows, _ := resp["results"].([]interface{})
+	for _, row := range rows {
+		if result, ok := row.(map[string]interface{}); ok {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// normalizeSpec converts the values of a manifest to the JSON types of responses so that they can be compared
+func normalizeSpec(spec map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert spec: %v", err)
+	}
+	normalized := make(map[string]interface{})
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, fmt.Errorf("failed to convert spec: %v", err)
+	}
+	return normalized, nil
+}
+
+// withManagedTag returns the spec with the managed tag added to its tags, or to the live tags
+// when the manifest leaves the tags out
+func withManagedTag(spec, live map[string]interface{}) map[string]interface{} {
+	desired := make(map[string]interface{}, len(spec)+1)
+	for key, value := range spec {
+		desired[key] = value
+	}
+
+	tags := make(map[string]interface{})
+	source, ok := spec["tags"].(map[string]interface{})
+	if !ok && live != nil {
+		source, _ = live["tags"].(map[string]interface{})
+	}
+	for key, value := range source {
+		tags[key] = value
+	}
+	tags[syncManagedKey] = syncManagedValue
+	desired["tags"] = tags
+	return desired
+}
+
+// applySyncPlan makes the changes of a sync plan and reports the failures
+func applySyncPlan(plan []planAction) error {
+	progress := format.NewProgress("Syncing resources", len(plan))
+	for _, action := range plan {
+		item := fmt.Sprintf("%s %s.%s %s", action.Action, action.Service, action.Resource, firstNonEmpty(action.ID, action.Name))
+
+		var options *transport.FetchOptions
+		switch action.Action {
+		case planDelete:
+			options = &transport.FetchOptions{
+				Parameters: []string{fmt.Sprintf("%s=%s", transport.ResourceIDField(action.Resource), action.ID)},
+			}
+		default:
+			spec, err := json.Marshal(action.spec)
+			if err != nil {
+				progress.Fail(item, err)
+				continue
+			}
+			// Manifests may contain fields which can be set on create only
+			options = &transport.FetchOptions{
+				JSONParameter:     string(spec),
+				DropUnknownFields: action.Action == planUpdate,
+			}
+		}
+
+		resp, err := transport.FetchService(action.Service, action.Action, action.Resource, options)
+		if err == nil && resp == nil {
+			err = fmt.Errorf("not authenticated for this environment")
+		}
+		if err != nil {
+			progress.Fail(item, err)
+			continue
+		}
+		progress.Succeed()
+	}
+
+	summary := progress.Stop()
+	if summary.Failed > 0 {
+		summary.PrintPartialFailure()
+		return fmt.Errorf("%d of %d changes failed", summary.Failed, summary.Total)
+	}
+	pterm.Success.Printf("%d changes applied\n", summary.Succeeded)
+	return nil
+}
+
+// firstNonEmpty returns the first value which is not empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func init() {
+	SyncCmd.Flags().String("dir", "", "Directory of resource manifests")
+	SyncCmd.Flags().Bool("prune", false, "Delete managed resources of the synced types which have no manifest")
+	SyncCmd.Flags().Bool("dry-run", false, "Print the plan without changing anything")
+	SyncCmd.Flags().BoolP("yes", "y", false, "Apply the plan without confirmation")
+	SyncCmd.Flags().String("plan-out", "", "Write the plan as JSON to this file")
+	SyncCmd.Flags().Bool("lock", false, "Lock the environment so that other apply and sync runs are refused until this one is done")
+	SyncCmd.Flags().Bool("force-unlock", false, "Remove the lock left behind by an interrupted run before locking")
+	addManifestValueFlags(SyncCmd)
+	SyncCmd.MarkFlagRequired("dir")
+}
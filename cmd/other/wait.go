@@ -0,0 +1,105 @@
+package other
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/cloudforet-io/cfctl/pkg/transport"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	waitInitialInterval = 2 * time.Second
+	waitMaxInterval     = 30 * time.Second
+)
+
+// WaitCmd represents the wait command
+var WaitCmd = &cobra.Command{
+	Use:   "wait <service> <Resource> <id>",
+	Short: "Wait for a resource to reach a state",
+	Long:  `Poll a resource with get until a field condition is met or the timeout expires.`,
+	Example: `  # Wait until a collection job is finished
+  $ cfctl wait inventory Job job-123456789012 --for status=SUCCESS --timeout 10m
+
+  # Accept one of several values
+  $ cfctl wait inventory Job job-123456789012 --for "status=SUCCESS|FAILURE|CANCELED"`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		serviceName, resourceName, id := args[0], args[1], args[2]
+
+		condition, _ := cmd.Flags().GetString("for")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		idField, _ := cmd.Flags().GetString("id-field")
+		parameters, _ := cmd.Flags().GetStringArray("parameter")
+
+		field, expected, err := parseWaitCondition(condition)
+		if err != nil {
+			return err
+		}
+
+		if idField == "" {
+			idField = transport.ResourceIDField(resourceName)
+		}
+		parameters = append(parameters, fmt.Sprintf("%s=%s", idField, id))
+
+		deadline := time.Now().Add(timeout)
+		interval := waitInitialInterval
+
+		for {
+			resp, err := transport.FetchService(serviceName, "get", resourceName, &transport.FetchOptions{
+				Parameters: parameters,
+			})
+			if err != nil {
+				return err
+			}
+			if resp == nil {
+				return fmt.Errorf("failed to get %s %s", resourceName, id)
+			}
+
+			current := ""
+			if val, ok := format.GetValueByPath(resp, field); ok {
+				current = fmt.Sprintf("%v", val)
+			}
+
+			for _, value := range expected {
+				if current == value {
+					pterm.Success.Printf("%s %s condition met: %s=%s\n", resourceName, id, field, current)
+					return nil
+				}
+			}
+
+			if time.Now().Add(interval).After(deadline) {
+				return fmt.Errorf("timed out after %s waiting for %s=%s (current: %s)", timeout, field, strings.Join(expected, "|"), current)
+			}
+
+			pterm.Info.Printf("%s=%s, retrying in %s\n", field, current, interval)
+			time.Sleep(interval)
+
+			interval *= 2
+			if interval > waitMaxInterval {
+				interval = waitMaxInterval
+			}
+		}
+	},
+}
+
+// parseWaitCondition parses a condition in the form field=value[|value...]
+func parseWaitCondition(condition string) (string, []string, error) {
+	parts := strings.SplitN(condition, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", nil, fmt.Errorf("invalid condition '%s'. Use --for <field>=<value>", condition)
+	}
+
+	return parts[0], strings.Split(parts[1], "|"), nil
+}
+
+func init() {
+	WaitCmd.Flags().String("for", "", "Condition to wait for (--for state=ACTIVE)")
+	WaitCmd.Flags().Duration("timeout", 10*time.Minute, "Maximum time to wait")
+	WaitCmd.Flags().String("id-field", "", "Id parameter name (default: <resource>_id)")
+	WaitCmd.Flags().StringArrayP("parameter", "p", []string{}, "Additional get parameter (-p <key>=<value> -p ...)")
+	WaitCmd.MarkFlagRequired("for")
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -11,10 +12,11 @@ import (
 
 	"github.com/cloudforet-io/cfctl/cmd/common"
 	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/cloudforet-io/cfctl/pkg/i18n"
 	"github.com/cloudforet-io/cfctl/pkg/transport"
-	"github.com/jhump/protoreflect/grpcreflect"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 	"gopkg.in/yaml.v3"
 
 	"github.com/spf13/viper"
@@ -32,6 +34,7 @@ type Config struct {
 	Environment string
 	Endpoint    string
 	Token       string
+	Endpoints   map[string]string
 }
 
 // rootCmd represents the base command when called without any subcommands
@@ -46,11 +49,23 @@ var rootCmd = &cobra.Command{
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+			format.SetQuiet()
+		}
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	// Write a diagnostic bundle instead of a bare stack trace when cfctl crashes
+	defer func() {
+		if r := recover(); r != nil {
+			other.HandlePanic(r)
+		}
+	}()
+
 	if len(os.Args) == 2 {
 		alias := os.Args[1]
 		if cmd := getAliasCommand(alias); cmd != "" {
@@ -58,7 +73,18 @@ func Execute() {
 		}
 	}
 
-	if err := rootCmd.Execute(); err != nil {
+	// Dispatch unknown commands to cfctl-<name> plugins in PATH
+	if len(os.Args) > 1 {
+		if _, _, err := rootCmd.Find(os.Args[1:]); err != nil {
+			if path, ok := other.LookupPlugin(os.Args[1]); ok {
+				os.Exit(other.RunPlugin(path, os.Args[2:]))
+			}
+		}
+	}
+
+	err := rootCmd.Execute()
+	configs.FlushMetrics()
+	if err != nil {
 		os.Exit(1)
 	}
 }
@@ -83,10 +109,12 @@ func init() {
 	}
 	rootCmd.AddGroup(AvailableCommands)
 
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Suppress info and success messages, only print the output, warnings and errors")
+
 	done := make(chan bool)
 	go func() {
-		if endpoints, err := loadCachedEndpoints(); err == nil {
-			cachedEndpointsMap = endpoints
+		if endpointsMap, err := loadCachedEndpoints(); err == nil {
+			cachedEndpointsMap = endpointsMap
 		}
 		done <- true
 	}()
@@ -100,13 +128,16 @@ func init() {
 		}
 	}
 
+	// Enable ANSI colors and escape codes on Windows consoles
+	format.InitTerminal()
+
 	if len(os.Args) > 1 && (os.Args[1] == "__complete" || os.Args[1] == "completion") {
 		pterm.DisableColor()
 	}
 
 	// Determine if the current command is 'setting environment -l'
 	skipDynamicCommands := false
-	if len(os.Args) >= 2 && os.Args[1] == "setting" {
+	if len(os.Args) >= 2 && (os.Args[1] == "setting" || os.Args[1] == "config") {
 		// Skip dynamic commands for all setting related operations
 		skipDynamicCommands = true
 	}
@@ -126,8 +157,31 @@ func init() {
 	rootCmd.AddCommand(other.ApiResourcesCmd)
 	rootCmd.AddCommand(other.SettingCmd)
 	rootCmd.AddCommand(other.LoginCmd)
+	rootCmd.AddCommand(other.LogoutCmd)
 	rootCmd.AddCommand(other.AliasCmd)
 	rootCmd.AddCommand(other.ApplyCmd)
+	rootCmd.AddCommand(other.WaitCmd)
+	rootCmd.AddCommand(other.JobsCmd)
+	rootCmd.AddCommand(other.CostCmd)
+	rootCmd.AddCommand(other.ReportCmd)
+	rootCmd.AddCommand(other.PluginCmd)
+	rootCmd.AddCommand(other.BenchmarkCmd)
+	rootCmd.AddCommand(other.DebugCmd)
+	rootCmd.AddCommand(other.MockCmd)
+	rootCmd.AddCommand(other.DocsCmd(rootCmd))
+	rootCmd.AddCommand(other.SessionCmd)
+	rootCmd.AddCommand(other.DomainCmd)
+	rootCmd.AddCommand(other.SearchCmd)
+	rootCmd.AddCommand(other.GraphCmd)
+	rootCmd.AddCommand(other.ScheduleCmd)
+	rootCmd.AddCommand(other.ListenCmd)
+	rootCmd.AddCommand(other.SyncCmd)
+
+	// Add the install subcommand to the default completion command
+	rootCmd.InitDefaultCompletionCmd()
+	if completionCmd, _, err := rootCmd.Find([]string{"completion"}); err == nil {
+		completionCmd.AddCommand(other.CompletionInstallCmd(rootCmd))
+	}
 
 	// Set default group for commands without a group
 	for _, cmd := range rootCmd.Commands() {
@@ -198,37 +252,27 @@ func showInitializationGuide() {
 			}
 
 			pterm.DefaultBox.
-				WithTitle("Token Not Found").
+				WithTitle(i18n.T("token_not_found.title")).
 				WithTitleTopCenter().
 				WithBoxStyle(pterm.NewStyle(pterm.FgWhite)).
 				WithRightPadding(1).
 				WithLeftPadding(1).
 				WithTopPadding(0).
 				WithBottomPadding(0).
-				Println("Please follow the instructions below to obtain an App Token.")
-
-			boxContent := fmt.Sprintf(`Please follow these steps to obtain an App Token:
-
-1. Visit %s
-2. Go to Admin page or Workspace page
-3. Navigate to the App page
-4. Click [Create] button
-5. Copy the generated App Token
-6. Update your settings:
-     Path: %s
-     Environment: %s
-     Field: "token"`,
+				Println(i18n.T("token_not_found.body"))
+
+			boxContent := i18n.T("setup_instructions.body",
 				pterm.FgLightCyan.Sprint(url),
 				pterm.FgLightYellow.Sprint(settingFile),
 				pterm.FgLightGreen.Sprint(currentEnv))
 
 			pterm.DefaultBox.
-				WithTitle("Setup Instructions").
+				WithTitle(i18n.T("setup_instructions.title")).
 				WithTitleTopCenter().
 				WithBoxStyle(pterm.NewStyle(pterm.FgLightBlue)).
 				Println(boxContent)
 
-			pterm.Info.Println("After updating the token, please try your command again.")
+			pterm.Info.Println(i18n.T("token_not_found.retry"))
 		}
 	} else if strings.HasSuffix(currentEnv, "-user") {
 		// Get endpoint from environment config
@@ -245,8 +289,8 @@ func showInitializationGuide() {
 			return
 		}
 
-		pterm.Warning.Printf("Authentication required.\n")
-		pterm.Info.Println("To see Available Commands, please authenticate first:")
+		pterm.Warning.Println(i18n.T("auth.required"))
+		pterm.Info.Println(i18n.T("auth.login_first"))
 		pterm.Info.Println("$ cfctl login")
 	}
 }
@@ -265,14 +309,19 @@ func addDynamicServiceCommands() error {
 	if strings.Contains(config.Endpoint, ".svc.cluster.local") {
 		apiEndpoint = endpointName
 	} else if strings.HasPrefix(config.Endpoint, "grpc://") {
-		endpoint := strings.TrimPrefix(config.Endpoint, "grpc://")
+		target, err := endpoints.ParseTarget(config.Endpoint)
+		if err != nil {
+			return err
+		}
 
-		conn, err := grpc.Dial(endpoint, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(time.Second))
+		dialCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		conn, err := configs.DialContext(dialCtx, target.HostPort, target.Credentials())
+		cancel()
 		if err != nil {
-			pterm.DefaultBox.WithTitle("Local gRPC Server Not Found").
+			pterm.DefaultBox.WithTitle(i18n.T("local_grpc.title")).
 				WithTitleTopCenter().
 				WithBoxStyle(pterm.NewStyle(pterm.FgYellow)).
-				Printfln("Current environment: %s\nUnable to connect to local gRPC server.\nPlease make sure your gRPC server is running on %s", config.Environment, config.Endpoint)
+				Println(i18n.T("local_grpc.body", config.Environment, config.Endpoint))
 			return nil
 		}
 		defer func(conn *grpc.ClientConn) {
@@ -283,7 +332,7 @@ func addDynamicServiceCommands() error {
 		}(conn)
 
 		ctx := context.Background()
-		refClient := grpcreflect.NewClientV1Alpha(ctx, grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+		refClient := configs.NewReflectionClient(ctx, conn)
 		defer refClient.Reset()
 
 		services, err := refClient.ListServices()
@@ -348,16 +397,7 @@ func addDynamicServiceCommands() error {
 
 	// Try to use cached endpoints first
 	if cachedEndpointsMap != nil {
-		currentService := ""
-		if strings.HasPrefix(endpointName, "grpc+ssl://") {
-			parts := strings.Split(endpointName, "://")
-			if len(parts) == 2 {
-				hostParts := strings.Split(parts[1], ".")
-				if len(hostParts) > 0 {
-					currentService = hostParts[0]
-				}
-			}
-		}
+		currentService := endpoints.ServiceOf(endpointName)
 
 		if currentService != "identity" && currentService != "" {
 			if cmd := createServiceCommand(currentService); cmd != nil {
@@ -368,7 +408,7 @@ func addDynamicServiceCommands() error {
 		}
 
 		// If identity service or no specific service, add all available commands
-		for serviceName := range cachedEndpointsMap {
+		for serviceName := range withEndpointOverrides(cachedEndpointsMap, config.Endpoints) {
 			cmd := createServiceCommand(serviceName)
 			cmd.GroupID = "available"
 			rootCmd.AddCommand(cmd)
@@ -398,16 +438,7 @@ func addDynamicServiceCommands() error {
 
 	progressbar.UpdateTitle("Registering available service commands")
 	// Add commands based on the current service
-	currentService := ""
-	if strings.HasPrefix(endpointName, "grpc+ssl://") {
-		parts := strings.Split(endpointName, "://")
-		if len(parts) == 2 {
-			hostParts := strings.Split(parts[1], ".")
-			if len(hostParts) > 0 {
-				currentService = hostParts[0]
-			}
-		}
-	}
+	currentService := endpoints.ServiceOf(endpointName)
 
 	if currentService != "identity" && currentService != "" {
 		if cmd := createServiceCommand(currentService); cmd != nil {
@@ -415,7 +446,7 @@ func addDynamicServiceCommands() error {
 			rootCmd.AddCommand(cmd)
 		}
 	} else {
-		for serviceName := range endpointsMap {
+		for serviceName := range withEndpointOverrides(endpointsMap, config.Endpoints) {
 			cmd := createServiceCommand(serviceName)
 			cmd.GroupID = "available"
 			rootCmd.AddCommand(cmd)
@@ -431,6 +462,31 @@ func addDynamicServiceCommands() error {
 	return nil
 }
 
+// splitResources returns the resources of comma separated or repeated arguments
+func splitResources(args []string) []string {
+	var resources []string
+	for _, arg := range args {
+		for _, resource := range strings.Split(arg, ",") {
+			if resource = strings.TrimSpace(resource); resource != "" {
+				resources = append(resources, resource)
+			}
+		}
+	}
+	return resources
+}
+
+// withEndpointOverrides adds the services with an explicit endpoint to the discovered endpoints
+func withEndpointOverrides(endpointsMap, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(endpointsMap)+len(overrides))
+	for service, endpoint := range endpointsMap {
+		merged[service] = endpoint
+	}
+	for service, endpoint := range overrides {
+		merged[service] = endpoint
+	}
+	return merged
+}
+
 func loadCachedEndpoints() (map[string]string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -455,30 +511,10 @@ func loadCachedEndpoints() (map[string]string, error) {
 		return nil, fmt.Errorf("no environment set")
 	}
 
-	cacheFile := filepath.Join(home, ".cfctl", "cache", settings.Environment, "endpoints.yaml")
-	data, err := os.ReadFile(cacheFile)
-	if err != nil {
-		return nil, err
-	}
-
-	cacheInfo, err := os.Stat(cacheFile)
-	if err != nil {
-		return nil, err
-	}
-
-	if time.Since(cacheInfo.ModTime()) > 24*time.Hour {
-		return nil, fmt.Errorf("cache expired")
-	}
-
-	var endpoints map[string]string
-	if err := yaml.Unmarshal(data, &endpoints); err != nil {
-		return nil, err
-	}
-
-	return endpoints, nil
+	return endpoints.LoadCache(settings.Environment)
 }
 
-func saveEndpointsCache(endpoints map[string]string) error {
+func saveEndpointsCache(endpointsMap map[string]string) error {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return err
@@ -497,18 +533,7 @@ func saveEndpointsCache(endpoints map[string]string) error {
 		return fmt.Errorf("no environment set")
 	}
 
-	// Create environment-specific cache directory
-	envCacheDir := filepath.Join(home, ".cfctl", "cache", currentEnv)
-	if err := os.MkdirAll(envCacheDir, 0755); err != nil {
-		return err
-	}
-
-	data, err := yaml.Marshal(endpoints)
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(filepath.Join(envCacheDir, "endpoints.yaml"), data, 0644)
+	return endpoints.SaveCache(currentEnv, endpointsMap)
 }
 
 // loadConfig loads configuration from both main and cache setting files
@@ -547,6 +572,7 @@ func loadConfig() (*Config, error) {
 	config := &Config{
 		Environment: currentEnv,
 		Endpoint:    endpointName,
+		Endpoints:   envConfig.GetStringMapString("endpoints"),
 	}
 
 	if strings.HasSuffix(currentEnv, "-app") {
@@ -556,12 +582,39 @@ func loadConfig() (*Config, error) {
 	return config, nil
 }
 
+// applyFlagDefaults sets the flags which were not given on the command line
+// to the values of the defaults section of the setting file
+func applyFlagDefaults(cmd *cobra.Command, verb string) {
+	for name, value := range configs.LoadDefaults(verb) {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			pterm.Warning.Printf("Ignoring unknown flag '%s' in the defaults of the setting file\n", name)
+			continue
+		}
+		if flag.Changed {
+			continue
+		}
+
+		values, ok := value.([]interface{})
+		if !ok {
+			values = []interface{}{value}
+		}
+		for _, v := range values {
+			if err := cmd.Flags().Set(name, fmt.Sprint(v)); err != nil {
+				pterm.Warning.Printf("Ignoring invalid default of flag '%s': %v\n", name, err)
+				break
+			}
+		}
+	}
+}
+
 func createServiceCommand(serviceName string) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     serviceName + " [verb] [resource]",
-		Short:   fmt.Sprintf("Interact with the %s service", serviceName),
-		Long:    fmt.Sprintf("Use this command to interact with the %s service.", serviceName),
-		GroupID: "available",
+		Use:               serviceName + " [verb] [resource]",
+		Short:             fmt.Sprintf("Interact with the %s service", serviceName),
+		Long:              fmt.Sprintf("Use this command to interact with the %s service.", serviceName),
+		GroupID:           "available",
+		ValidArgsFunction: common.CompleteServiceArgs(serviceName),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 {
 				pterm.Info.Println("To see available API resources, run:")
@@ -579,22 +632,63 @@ func createServiceCommand(serviceName string) *cobra.Command {
 			if len(args) > 1 {
 				resource = args[1]
 			}
+			verb = common.ResolveVerb(serviceName, verb, resource)
 
 			if verb == "api_resources" {
-				return common.ListAPIResources(serviceName)
+				return common.ListAPIResources(serviceName, common.APIResourceOptions{})
 			}
 
+			applyFlagDefaults(cmd, verb)
+
 			parameters, _ := cmd.Flags().GetStringArray("parameter")
 			jsonParameter, _ := cmd.Flags().GetString("json-parameter")
 			fileParameter, _ := cmd.Flags().GetString("file-parameter")
 			outputFormat, _ := cmd.Flags().GetString("output")
+			noHeaders, _ := cmd.Flags().GetBool("no-headers")
+			delimiter, _ := cmd.Flags().GetString("delimiter")
+			sortedKeys, _ := cmd.Flags().GetBool("sorted-keys")
+			showStats, _ := cmd.Flags().GetBool("show-stats")
 			copyToClipboard, _ := cmd.Flags().GetBool("copy")
+			pasteFromClipboard, _ := cmd.Flags().GetBool("paste")
+			copyField, _ := cmd.Flags().GetString("copy-field")
+			jq, _ := cmd.Flags().GetString("jq")
+			apiVersion, _ := cmd.Flags().GetString("api-version")
+			failIfEmpty, _ := cmd.Flags().GetBool("fail-if-empty")
+			strict, _ := cmd.Flags().GetBool("strict")
+			fromGet, _ := cmd.Flags().GetString("from-get")
+			patchFile, _ := cmd.Flags().GetString("patch-file")
+			patchArrays, _ := cmd.Flags().GetString("patch-arrays")
 
 			sortBy := ""
 			columns := ""
 			rows := 0
 			pageSize := 100
 			noPaging := false
+			groupBy := ""
+			aggregation := ""
+			var enrich []string
+			humanizeTime, _ := cmd.Flags().GetBool("humanize-time")
+			rawValues, _ := cmd.Flags().GetBool("raw-values")
+			summary, _ := cmd.Flags().GetBool("summary")
+			checkPermission, _ := cmd.Flags().GetBool("check-permission")
+			admin, _ := cmd.Flags().GetBool("admin")
+			headers, _ := cmd.Flags().GetStringArray("header")
+			maxRecvSize, _ := cmd.Flags().GetString("max-recv-size")
+			maxSendSize, _ := cmd.Flags().GetString("max-send-size")
+			compress, _ := cmd.Flags().GetString("compress")
+			protoset, _ := cmd.Flags().GetString("protoset")
+			notifyCommand, _ := cmd.Flags().GetString("notify-command")
+			notifyDesktop, _ := cmd.Flags().GetBool("notify-desktop")
+			resume, _ := cmd.Flags().GetBool("resume")
+			allPages, _ := cmd.Flags().GetBool("all-pages")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			since, _ := cmd.Flags().GetString("since")
+			until, _ := cmd.Flags().GetString("until")
+			timeField, _ := cmd.Flags().GetString("time-field")
+			record, _ := cmd.Flags().GetString("record")
+			replay, _ := cmd.Flags().GetString("replay")
+			asUser, _ := cmd.Flags().GetString("as-user")
+			asWorkspace, _ := cmd.Flags().GetString("as-workspace")
 
 			if verb == "list" {
 				sortBy, _ = cmd.Flags().GetString("sort")
@@ -602,36 +696,110 @@ func createServiceCommand(serviceName string) *cobra.Command {
 				rows, _ = cmd.Flags().GetInt("rows")
 				pageSize, _ = cmd.Flags().GetInt("rows-per-page")
 				noPaging, _ = cmd.Flags().GetBool("no-paging")
+				groupBy, _ = cmd.Flags().GetString("group-by")
+				aggregation, _ = cmd.Flags().GetString("agg")
+				enrich, _ = cmd.Flags().GetStringArray("enrich")
 			}
 
 			options := &transport.FetchOptions{
 				Parameters:           parameters,
 				JSONParameter:        jsonParameter,
 				FileParameter:        fileParameter,
+				APIVersion:           apiVersion,
 				OutputFormat:         outputFormat,
 				OutputFormatExplicit: cmd.Flags().Changed("output"),
+				NoHeaders:            noHeaders,
+				Delimiter:            delimiter,
+				SortedKeys:           sortedKeys,
+				ShowStats:            showStats,
 				CopyToClipboard:      copyToClipboard,
+				PasteFromClipboard:   pasteFromClipboard,
+				CopyField:            copyField,
+				JQ:                   jq,
+				FailIfEmpty:          failIfEmpty,
+				AllowUnknownParams:   !strict,
+				FromGet:              fromGet,
+				PatchFile:            patchFile,
+				PatchArrays:          patchArrays,
 				SortBy:               sortBy,
 				MinimalColumns:       verb == "list" && cmd.Flag("minimal") != nil && cmd.Flag("minimal").Changed,
 				Columns:              columns,
 				Rows:                 rows,
 				PageSize:             pageSize,
 				NoPaging:             noPaging,
+				HumanizeTime:         humanizeTime,
+				RawValues:            rawValues,
+				Summary:              summary,
+				GroupBy:              groupBy,
+				Aggregation:          aggregation,
+				Enrich:               enrich,
+				CheckPermission:      checkPermission,
+				Admin:                admin,
+				Headers:              headers,
+				MaxRecvSize:          maxRecvSize,
+				MaxSendSize:          maxSendSize,
+				Compress:             compress,
+				Protoset:             protoset,
+				NotifyCommand:        notifyCommand,
+				NotifyDesktop:        notifyDesktop,
+				Resume:               resume,
+				AllPages:             allPages,
+				Concurrency:          concurrency,
+				Since:                since,
+				Until:                until,
+				TimeField:            timeField,
+				Record:               record,
+				Replay:               replay,
+				AsUser:               asUser,
+				AsWorkspace:          asWorkspace,
+				Requested:            true,
 			}
 
 			if verb == "list" && !cmd.Flags().Changed("output") {
 				options.OutputFormat = "table"
 			}
 
+			// Apply the column preset of the resource unless columns were chosen explicitly
+			if verb == "list" && (options.OutputFormat == "table" || options.OutputFormat == "csv" || options.OutputFormat == "tsv") &&
+				!cmd.Flags().Changed("columns") && !options.MinimalColumns {
+				if preset := configs.LoadColumnPreset(serviceName, resource); preset != "" {
+					options.Columns = preset
+				}
+			}
+
+			edit, _ := cmd.Flags().GetBool("edit")
+			if edit {
+				if verb != "get" {
+					return fmt.Errorf("--edit can only be used with the get verb")
+				}
+				if err := transport.EditResource(serviceName, resource, options); err != nil {
+					pterm.Error.Println(err.Error())
+					configs.FlushMetrics()
+					os.Exit(1)
+				}
+				return nil
+			}
+
 			watch, _ := cmd.Flags().GetBool("watch")
-			if watch && verb == "list" {
-				return transport.WatchResource(serviceName, verb, resource, options)
+			if verb == "watch" || (watch && verb == "list") {
+				resources := splitResources(args[1:])
+				if len(resources) == 0 {
+					return fmt.Errorf("resource is required to watch (e.g. cfctl %s watch <Resource>[,<Resource>...])", serviceName)
+				}
+				return transport.WatchResources(serviceName, "list", resources, options)
 			}
 
 			_, err := transport.FetchService(serviceName, verb, resource, options)
+			if errors.Is(err, transport.ErrEmptyResults) {
+				// The notice was already printed with the empty output
+				configs.FlushMetrics()
+				os.Exit(1)
+			}
 			if err != nil {
+				// The library returns every failure, the command decides the exit status
 				pterm.Error.Println(err.Error())
-				return nil
+				configs.FlushMetrics()
+				os.Exit(1)
 			}
 			return nil
 		},
@@ -640,21 +808,78 @@ func createServiceCommand(serviceName string) *cobra.Command {
 	// Add api_resources subcommand
 	cmd.AddCommand(common.FetchApiResourcesCmd(serviceName))
 
+	// Add service specific helper commands
+	if serviceName == "monitoring" {
+		cmd.AddCommand(common.MonitoringLogsCmd())
+		cmd.AddCommand(common.MonitoringMetricCmd())
+		cmd.AddCommand(common.MonitoringAlertCmd())
+	}
+	if serviceName == "identity" {
+		cmd.AddCommand(common.IdentityServiceAccountCmd())
+		cmd.AddCommand(common.IdentityWhoamiCmd())
+	}
+	if serviceName == "inventory" {
+		cmd.AddCommand(common.InventoryCollectCmd())
+		cmd.AddCommand(common.InventorySummaryCmd())
+	}
+	if serviceName == "notification" {
+		cmd.AddCommand(common.NotificationSendTestCmd())
+	}
+
 	// Add list-specific flags
-	cmd.Flags().BoolP("watch", "w", false, "Watch for changes")
+	cmd.Flags().BoolP("watch", "w", false, "Watch for changes, several resources can be watched at once (list CloudService,Job -w)")
+	cmd.Flags().String("notify-command", "", "Shell command run for each new item in watch mode, with the item JSON on stdin")
+	cmd.Flags().Bool("notify-desktop", false, "Show a desktop notification for new items in watch mode")
+	cmd.Flags().Bool("resume", false, "Resume watching from the state of the last run instead of announcing existing items")
 	cmd.Flags().StringP("sort", "s", "", "Sort by field (e.g. 'name', 'created_at')")
 	cmd.Flags().BoolP("minimal", "m", false, "Show minimal columns")
-	cmd.Flags().StringP("columns", "c", "", "Specific columns (-c id,name)")
+	cmd.Flags().StringP("columns", "c", "", "Specific columns, nested paths allowed (-c id,name,data.os.os_distro)")
 	cmd.Flags().IntP("rows", "r", 0, "Number of rows")
 	cmd.Flags().IntP("rows-per-page", "n", 15, "Number of rows per page")
 	cmd.Flags().BoolP("no-paging", "", false, "Disable pagination and show all results")
+	cmd.Flags().Bool("all-pages", false, "Fetch every page of list results, csv, tsv, ndjson and name output is written page by page")
+	cmd.Flags().Int("concurrency", 4, "Number of pages fetched at once with --all-pages")
+	cmd.Flags().String("since", "", "List items created since a duration or date (--since 24h, --since 2024-06-01)")
+	cmd.Flags().String("until", "", "List items created before a duration or date (--until 1h, --until 2024-07-01)")
+	cmd.Flags().String("time-field", "created_at", "Field filtered by --since and --until")
+	cmd.Flags().Bool("humanize-time", true, "Show timestamps as relative time (e.g. 3h ago) in table output")
+	cmd.Flags().Bool("raw-values", false, "Show raw values without number, size and time formatting in table output")
+	cmd.Flags().String("group-by", "", "Group list results by fields (--group-by provider,region_code)")
+	cmd.Flags().String("agg", "count", "Aggregation for --group-by (count, sum:<field>)")
+	cmd.Flags().StringArray("enrich", []string{}, "Add referenced names to list results (--enrich project_id=identity.Project.name)")
+	cmd.Flags().String("patch-file", "", "Update the resource by applying a JSON merge patch (YAML or JSON) to its current state")
+	cmd.Flags().String("patch-arrays", "replace", "How --patch-file changes arrays (replace, append, merge by key, name or id)")
+	cmd.Flags().String("from-get", "", "Update the resource with this id from its current state with the given values over it")
+	cmd.Flags().Bool("strict", true, "Fail on parameters which are not fields of the request (--strict=false skips them)")
+	cmd.Flags().Bool("fail-if-empty", false, "Exit with an error when a list has no results")
+	cmd.Flags().Bool("summary", false, "Print a JSON summary of list results (shown, total, status counts) instead of the results")
 
 	// Add existing flags
-	cmd.Flags().StringArrayP("parameter", "p", []string{}, "Input Parameter (-p <key>=<value> -p ...)")
+	cmd.Flags().StringArrayP("parameter", "p", []string{}, "Input Parameter (-p <key>=<value> -p ...), ids can be given by name (-p project_id=name:<name>) and secrets by reference (-p password=vault:<path>#<field>, env:<NAME>, file:<path>)")
 	cmd.Flags().StringP("json-parameter", "j", "", "JSON type parameter")
 	cmd.Flags().StringP("file-parameter", "f", "", "YAML file parameter")
-	cmd.Flags().StringP("output", "o", "yaml", "Output format (yaml, json, table, csv)")
+	cmd.Flags().StringP("output", "o", "yaml", "Output format (yaml, json, table, csv, tsv, ndjson, name, plugin:<name>)")
+	cmd.Flags().Bool("no-headers", false, "Leave out the header row of table, csv and tsv output")
+	cmd.Flags().String("delimiter", "", "Field delimiter of csv output (e.g. ';', or '\\t' for tabs)")
+	cmd.Flags().Bool("sorted-keys", false, "Order the keys of yaml and json output by the proto field order for stable diffs between runs")
 	cmd.Flags().BoolP("copy", "y", false, "Copy the output to the clipboard")
+	cmd.Flags().String("copy-field", "", "Copy a single field of the response to the clipboard (--copy-field results[0].server_id)")
+	cmd.Flags().String("jq", "", "Transform the response with a jq expression before formatting (--jq '.results[] | {name, state}')")
+	cmd.Flags().Bool("paste", false, "Read the JSON or YAML request body from the clipboard")
+	cmd.Flags().StringArrayP("header", "H", []string{}, "Extra gRPC metadata header (-H key=value -H ...)")
+	cmd.Flags().Bool("show-stats", false, "Print the duration, payload size, server and number of items of each call to stderr")
+	cmd.Flags().String("max-recv-size", "", "Maximum response message size (e.g. 64MiB, default 10MiB)")
+	cmd.Flags().String("max-send-size", "", "Maximum request message size (e.g. 64MiB, default 10MiB)")
+	cmd.Flags().String("compress", "", "Compress messages (gzip, none)")
+	cmd.Flags().String("api-version", "", "Call the service of this API version only (e.g. v1, v2), failing if the server does not expose it")
+	cmd.Flags().String("protoset", "", "Discover methods from a compiled descriptor set file instead of server reflection (or set 'protoset' in the environment)")
+	cmd.Flags().String("record", "", "Save the request and response of the call to a directory")
+	cmd.Flags().String("replay", "", "Print the recorded response from a directory instead of calling the service")
+	cmd.Flags().String("as-user", "", "Check the call against the role of another user (domain admins only). SpaceONE cannot issue a token for another user, so the call is not made")
+	cmd.Flags().String("as-workspace", "", "Call with your admin role in another workspace, or check --as-user there (domain admins only)")
+	cmd.Flags().Bool("admin", false, "Call the API in admin mode with a domain scope token (or set 'mode: admin' in the environment)")
+	cmd.Flags().Bool("check-permission", false, "Check the permission of your role before calling the API")
+	cmd.Flags().Bool("edit", false, "Edit the resource in $EDITOR and submit the changes with update (get only)")
 
 	return cmd
 }
@@ -0,0 +1,57 @@
+package configs
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// defaultAuthHeader is the metadata key of the token expected by SpaceONE
+const defaultAuthHeader = "token"
+
+// AuthHeader describes how the token of an environment is sent in the metadata of calls
+type AuthHeader struct {
+	Name   string // Metadata key, token when empty
+	Scheme string // Prefix of the token value (e.g. Bearer), none when empty
+}
+
+// LoadAuthHeader reads the auth header of an environment from the setting file.
+// Deployments behind gateways expecting the token as bearer token configure it as follows.
+// Example:
+//
+//	environments:
+//	  prod-user:
+//	    auth_header: authorization
+//	    auth_scheme: Bearer
+func LoadAuthHeader(env string) AuthHeader {
+	settingPath, err := GetSettingFilePath()
+	if err != nil {
+		return AuthHeader{}
+	}
+	v, err := setViperWithSetting(settingPath)
+	if err != nil {
+		return AuthHeader{}
+	}
+
+	return authHeader(v, env)
+}
+
+// authHeader reads the auth header of an environment from a loaded setting file
+func authHeader(v *viper.Viper, env string) AuthHeader {
+	return AuthHeader{
+		Name:   v.GetString(fmt.Sprintf("environments.%s.auth_header", env)),
+		Scheme: v.GetString(fmt.Sprintf("environments.%s.auth_scheme", env)),
+	}
+}
+
+// Metadata returns the metadata key and value carrying the token
+func (h AuthHeader) Metadata(token string) (string, string) {
+	name := h.Name
+	if name == "" {
+		name = defaultAuthHeader
+	}
+	if h.Scheme != "" {
+		return name, fmt.Sprintf("%s %s", h.Scheme, token)
+	}
+	return name, token
+}
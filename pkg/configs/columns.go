@@ -0,0 +1,23 @@
+package configs
+
+import "fmt"
+
+// LoadColumnPreset reads the preferred columns of a resource from the columns section of the setting file.
+// An empty string is returned when no preset is configured.
+// Example:
+//
+//	columns:
+//	  inventory:
+//	    CloudService: name,provider,region_code,state
+func LoadColumnPreset(serviceName, resourceName string) string {
+	settingPath, err := GetSettingFilePath()
+	if err != nil {
+		return ""
+	}
+	v, err := setViperWithSetting(settingPath)
+	if err != nil {
+		return ""
+	}
+
+	return v.GetString(fmt.Sprintf("columns.%s.%s", serviceName, resourceName))
+}
@@ -0,0 +1,55 @@
+package configs
+
+import "strings"
+
+// flagAliases maps the keys of the defaults section to flag names where they differ
+var flagAliases = map[string]string{
+	"page_size": "rows-per-page",
+}
+
+// LoadDefaults reads the default flag values of a verb from the defaults section of the setting file.
+// Values directly under defaults apply to every verb, values under defaults.<verb> override them.
+// Keys are returned as flag names.
+// Example:
+//
+//	defaults:
+//	  minimal: true
+//	  list:
+//	    output: table
+//	    page_size: 50
+func LoadDefaults(verb string) map[string]interface{} {
+	defaults := make(map[string]interface{})
+
+	settingPath, err := GetSettingFilePath()
+	if err != nil {
+		return defaults
+	}
+	v, err := setViperWithSetting(settingPath)
+	if err != nil {
+		return defaults
+	}
+
+	section := v.GetStringMap("defaults")
+	for key, value := range section {
+		if _, isVerb := value.(map[string]interface{}); isVerb {
+			continue
+		}
+		defaults[flagName(key)] = value
+	}
+
+	if verbSection, ok := section[strings.ToLower(verb)].(map[string]interface{}); ok {
+		for key, value := range verbSection {
+			defaults[flagName(key)] = value
+		}
+	}
+
+	return defaults
+}
+
+// flagName converts a key of the defaults section (e.g. page_size) to its flag name
+func flagName(key string) string {
+	if name, ok := flagAliases[key]; ok {
+		return name
+	}
+	return strings.ReplaceAll(key, "_", "-")
+}
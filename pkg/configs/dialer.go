@@ -0,0 +1,158 @@
+package configs
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// defaultDialTimeout bounds connection attempts so that unreachable endpoints fail instead of hanging
+const defaultDialTimeout = 10 * time.Second
+
+// TransportSettings holds the gRPC transport tuning of an environment
+type TransportSettings struct {
+	DialTimeout      time.Duration // Maximum time to wait for a ready connection, 0 connects lazily on the first call
+	KeepaliveTime    time.Duration // Interval of keepalive pings, 0 disables keepalive
+	KeepaliveTimeout time.Duration // Time to wait for a keepalive ack
+	WaitForReady     bool          // Queue calls until the connection is ready instead of failing fast
+	RateLimit        float64       // Maximum calls per second, 0 disables rate limiting
+	RateBurst        int           // Calls allowed at once before the rate limit applies
+}
+
+var (
+	transportSettings     TransportSettings
+	transportSettingsOnce sync.Once
+	transportSettingsMu   sync.Mutex // Guards the reload of the settings
+)
+
+// LoadTransportSettings reads the transport settings of the current environment
+// Example:
+//
+//	environments:
+//	  prod-user:
+//	    dial_timeout: 5s
+//	    keepalive_time: 5m
+//	    keepalive_timeout: 20s
+//	    wait_for_ready: true
+//	    rate_limit: 10
+//	    rate_burst: 20
+func LoadTransportSettings() TransportSettings {
+	transportSettingsMu.Lock()
+	defer transportSettingsMu.Unlock()
+
+	transportSettingsOnce.Do(func() {
+		transportSettings = TransportSettings{DialTimeout: defaultDialTimeout}
+
+		settingPath, err := GetSettingFilePath()
+		if err != nil {
+			return
+		}
+		v, err := setViperWithSetting(settingPath)
+		if err != nil {
+			return
+		}
+
+		env := v.GetString("environment")
+		key := func(name string) string {
+			return fmt.Sprintf("environments.%s.%s", env, name)
+		}
+
+		if v.IsSet(key("dial_timeout")) {
+			transportSettings.DialTimeout = v.GetDuration(key("dial_timeout"))
+		}
+		transportSettings.KeepaliveTime = v.GetDuration(key("keepalive_time"))
+		transportSettings.KeepaliveTimeout = v.GetDuration(key("keepalive_timeout"))
+		transportSettings.WaitForReady = v.GetBool(key("wait_for_ready"))
+		transportSettings.RateLimit = v.GetFloat64(key("rate_limit"))
+		transportSettings.RateBurst = v.GetInt(key("rate_burst"))
+	})
+
+	return transportSettings
+}
+
+// ReloadTransportSettings makes the next connection read the transport settings again,
+// e.g. after the setting file changed in a long running command
+func ReloadTransportSettings() {
+	transportSettingsMu.Lock()
+	defer transportSettingsMu.Unlock()
+
+	transportSettingsOnce = sync.Once{}
+}
+
+// Credentials returns the transport credentials dial option of an endpoint,
+// TLS with verification for grpc+ssl:// and plaintext for grpc://
+func Credentials(secure bool) grpc.DialOption {
+	if !secure {
+		return grpc.WithTransportCredentials(insecure.NewCredentials())
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		InsecureSkipVerify: false,
+	}))
+}
+
+// Dial creates a client connection using the transport settings of the current environment
+// and waits up to the dial timeout of the environment until it is ready.
+// All gRPC connections of cfctl are created through this function or DialContext.
+func Dial(target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	settings := LoadTransportSettings()
+	if settings.DialTimeout <= 0 {
+		return newClient(target, settings, opts...)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), settings.DialTimeout)
+	defer cancel()
+
+	conn, err := DialContext(ctx, target, opts...)
+	if err == context.DeadlineExceeded {
+		return nil, fmt.Errorf("timed out after %s connecting to %s", settings.DialTimeout, target)
+	}
+	return conn, err
+}
+
+// DialContext creates a client connection using the transport settings of the current environment
+// and waits until it is ready or the context is done
+func DialContext(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	conn, err := newClient(target, LoadTransportSettings(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return conn, nil
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			conn.Close()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// newClient creates a client connection with the interceptors and options of the transport settings
+func newClient(target string, settings TransportSettings, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	dialOpts := append([]grpc.DialOption{}, opts...)
+	if settings.KeepaliveTime > 0 {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                settings.KeepaliveTime,
+			Timeout:             settings.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+	if settings.WaitForReady {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.WaitForReady(true)))
+	}
+	dialOpts = append(dialOpts, rateLimitOptions(settings)...)
+	dialOpts = append(dialOpts, metricsOptions()...)
+
+	return grpc.NewClient(target, dialOpts...)
+}
@@ -0,0 +1,130 @@
+package configs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeSetting points the home directory to a temporary directory with the given setting file
+func writeSetting(t *testing.T, content string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	dir := filepath.Join(home, ".cfctl")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if content != "" {
+		if err := os.WriteFile(filepath.Join(dir, "setting.yaml"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ReloadTransportSettings()
+	t.Cleanup(ReloadTransportSettings)
+}
+
+func TestLoadTransportSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting string
+		want    TransportSettings
+	}{
+		{
+			name: "missing setting file",
+			want: TransportSettings{DialTimeout: defaultDialTimeout},
+		},
+		{
+			name:    "malformed setting file",
+			setting: "environment: [dev",
+			want:    TransportSettings{DialTimeout: defaultDialTimeout},
+		},
+		{
+			name: "environment settings",
+			setting: `environment: dev-user
+environments:
+  dev-user:
+    dial_timeout: 5s
+    keepalive_time: 5m
+    keepalive_timeout: 20s
+    wait_for_ready: true
+    rate_limit: 10
+    rate_burst: 20
+`,
+			want: TransportSettings{
+				DialTimeout:      5 * time.Second,
+				KeepaliveTime:    5 * time.Minute,
+				KeepaliveTimeout: 20 * time.Second,
+				WaitForReady:     true,
+				RateLimit:        10,
+				RateBurst:        20,
+			},
+		},
+		{
+			name: "disabled dial timeout",
+			setting: `environment: dev-user
+environments:
+  dev-user:
+    dial_timeout: 0s
+`,
+			want: TransportSettings{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeSetting(t, tt.setting)
+			if got := LoadTransportSettings(); got != tt.want {
+				t.Errorf("LoadTransportSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialReturnsErrorOnTimeout(t *testing.T) {
+	writeSetting(t, `environment: dev-user
+environments:
+  dev-user:
+    dial_timeout: 200ms
+`)
+
+	conn, err := Dial("127.0.0.1:1", Credentials(false))
+	if err == nil {
+		conn.Close()
+		t.Fatal("Dial() to a closed port succeeded, want an error")
+	}
+	if !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Dial() error = %v, want a timeout", err)
+	}
+}
+
+func TestDialContextReturnsContextError(t *testing.T) {
+	writeSetting(t, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	conn, err := DialContext(ctx, "127.0.0.1:1", Credentials(false))
+	if err == nil {
+		conn.Close()
+		t.Fatal("DialContext() to a closed port succeeded, want an error")
+	}
+	if err != context.DeadlineExceeded {
+		t.Errorf("DialContext() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestGetServiceEndpointReturnsError(t *testing.T) {
+	config := &Environments{
+		Environment:  "dev-user",
+		Environments: map[string]Environment{"dev-user": {}},
+	}
+	if _, err := GetServiceEndpoint(config, "identity"); err == nil {
+		t.Error("GetServiceEndpoint() without endpoint succeeded, want an error")
+	}
+}
@@ -3,20 +3,13 @@ package configs
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/jhump/protoreflect/dynamic"
-	"github.com/jhump/protoreflect/grpcreflect"
-	"github.com/pterm/pterm"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 )
 
 // GetAPIEndpoint fetches the actual API endpoint from the config endpoint
@@ -132,6 +125,11 @@ func GetServiceEndpoint(config *Environments, serviceName string) (string, error
 		return "", fmt.Errorf("endpoint not found in environment config")
 	}
 
+	// Explicit service endpoints take precedence over discovery
+	if endpoint, ok := envConfig.Endpoints[serviceName]; ok && endpoint != "" {
+		return endpoint, nil
+	}
+
 	if strings.HasPrefix(envConfig.Endpoint, "grpc://") {
 		// Allow both localhost and cluster-internal addresses
 		if strings.Contains(envConfig.Endpoint, "localhost") || strings.Contains(envConfig.Endpoint, ".svc.cluster.local") {
@@ -142,8 +140,7 @@ func GetServiceEndpoint(config *Environments, serviceName string) (string, error
 	// Get console API endpoint
 	apiEndpoint, err := GetAPIEndpoint(envConfig.Endpoint)
 	if err != nil {
-		pterm.Error.Printf("Failed to get API endpoint: %v\n", err)
-		os.Exit(1)
+		return "", fmt.Errorf("failed to get API endpoint: %v", err)
 	}
 
 	// Get identity endpoint
@@ -181,8 +178,7 @@ func FetchEndpointsMap(endpoint string) (map[string]string, error) {
 	listEndpointsUrl := endpoint + "/identity/endpoint/list"
 
 	if err != nil {
-		pterm.Error.Printf("Failed to get identity endpoint: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("failed to get identity endpoint: %v", err)
 	}
 
 	if !hasIdentityService {
@@ -206,12 +202,7 @@ func FetchEndpointsMap(endpoint string) (map[string]string, error) {
 			baseDomain := strings.Join(hostParts[1:], ".")
 
 			// Configure TLS
-			tlsConfig := &tls.Config{
-				InsecureSkipVerify: false,
-			}
-			opts := []grpc.DialOption{
-				grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
-			}
+			opts := []grpc.DialOption{Credentials(true)}
 
 			//If current service is not identity, modify hostPort to use identity service
 			if svc != "identity" {
@@ -278,26 +269,17 @@ func FetchEndpointsMap(endpoint string) (map[string]string, error) {
 		hostPort := parts[1]
 
 		// Configure gRPC connection based on scheme
-		var opts []grpc.DialOption
-		if scheme == "grpc+ssl" {
-			tlsConfig := &tls.Config{
-				InsecureSkipVerify: false, // Enable server certificate verification
-			}
-			creds := credentials.NewTLS(tlsConfig)
-			opts = append(opts, grpc.WithTransportCredentials(creds))
-		} else {
-			opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-		}
+		opts := []grpc.DialOption{Credentials(scheme == "grpc+ssl")}
 
 		// Establish the connection
-		conn, err := grpc.Dial(hostPort, opts...)
+		conn, err := Dial(hostPort, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("connection failed: unable to connect to %s: %v", identityEndpoint, err)
 		}
 		defer conn.Close()
 
 		// Use Reflection to discover services
-		refClient := grpcreflect.NewClient(context.Background(), grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+		refClient := NewReflectionClient(context.Background(), conn)
 		defer refClient.Reset()
 
 		// Resolve the service and method
@@ -375,14 +357,14 @@ func invokeGRPCEndpointList(hostPort string, opts []grpc.DialOption) (map[string
 	}()
 
 	// Establish the connection
-	conn, err := grpc.Dial(hostPort, opts...)
+	conn, err := Dial(hostPort, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("connection failed: unable to connect to %s: %v", hostPort, err)
 	}
 	defer conn.Close()
 
 	// Use Reflection to discover services
-	refClient := grpcreflect.NewClient(context.Background(), grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+	refClient := NewReflectionClient(context.Background(), conn)
 	defer refClient.Reset()
 
 	serviceName := "spaceone.api.identity.v2.Endpoint"
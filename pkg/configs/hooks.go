@@ -0,0 +1,29 @@
+package configs
+
+// Hooks holds the user scripts run around the service calls of cfctl
+type Hooks struct {
+	PreRequest  string // Command receiving the request JSON, its output replaces the request
+	PostRequest string // Command receiving the response JSON
+}
+
+// LoadHooks reads the hooks of the setting file
+// Example:
+//
+//	hooks:
+//	  pre_request: ~/.cfctl/hooks/default-tags.sh
+//	  post_request: ~/.cfctl/hooks/push-metrics.sh
+func LoadHooks() Hooks {
+	settingPath, err := GetSettingFilePath()
+	if err != nil {
+		return Hooks{}
+	}
+	v, err := setViperWithSetting(settingPath)
+	if err != nil {
+		return Hooks{}
+	}
+
+	return Hooks{
+		PreRequest:  v.GetString("hooks.pre_request"),
+		PostRequest: v.GetString("hooks.post_request"),
+	}
+}
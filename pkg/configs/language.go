@@ -0,0 +1,17 @@
+package configs
+
+// LoadLanguage reads the language of messages from the setting file, or an empty string when it is not set
+// Example:
+//
+//	language: ko
+func LoadLanguage() string {
+	settingPath, err := GetSettingFilePath()
+	if err != nil {
+		return ""
+	}
+	v, err := setViperWithSetting(settingPath)
+	if err != nil {
+		return ""
+	}
+	return v.GetString("language")
+}
@@ -0,0 +1,311 @@
+package configs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pterm/pterm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MetricsSettings configures the metrics of the calls of cfctl, so that automation running
+// cfctl can be monitored centrally. Metrics are only emitted when an address is set.
+type MetricsSettings struct {
+	StatsdAddr     string `mapstructure:"statsd_addr"`     // host:port of a statsd server, sent over UDP
+	PushgatewayURL string `mapstructure:"pushgateway_url"` // URL of a Prometheus pushgateway
+	Prefix         string `mapstructure:"prefix"`          // Prefix of the metric names
+	Job            string `mapstructure:"job"`             // Job of the pushed metrics
+	Instance       string `mapstructure:"instance"`        // Instance label of the pushed metrics, the host name by default
+}
+
+// metricsPushInterval is how often the aggregated metrics are pushed to the pushgateway in the background
+const metricsPushInterval = 15 * time.Second
+
+// callMetric aggregates the calls of a method with the same code for the pushgateway
+type callMetric struct {
+	count    int
+	duration time.Duration
+}
+
+// callMetrics emits the count, duration and code of calls
+type callMetrics struct {
+	settings MetricsSettings
+	statsd   net.Conn
+
+	mu       sync.Mutex
+	calls    map[[2]string]*callMetric // By method and code
+	dirty    bool                      // Calls were recorded since the last push
+	pushMu   sync.Mutex                // Keeps pushes in order
+	stop     chan struct{}             // Stops the push loop
+	warnOnce sync.Once
+}
+
+var (
+	metrics       *callMetrics
+	metricsLoaded bool
+	metricsMu     sync.Mutex
+)
+
+// LoadMetricsSettings reads the metrics section of the setting file.
+// The pushgateway keeps only the last push of each job and instance, so the counters pushed
+// by a run replace the ones of the previous run with the same job on the same host.
+// Use statsd to aggregate the calls of many runs.
+// Example:
+//
+//	metrics:
+//	  statsd_addr: localhost:8125
+//	  pushgateway_url: http://pushgateway:9091
+//	  prefix: cfctl
+//	  job: nightly-export
+//	  instance: batch-01
+func LoadMetricsSettings() MetricsSettings {
+	var settings MetricsSettings
+	if settingPath, err := GetSettingFilePath(); err == nil {
+		if v, err := setViperWithSetting(settingPath); err == nil && v.IsSet("metrics") {
+			_ = v.UnmarshalKey("metrics", &settings)
+		}
+	}
+	if settings.Prefix == "" {
+		settings.Prefix = "cfctl"
+	}
+	if settings.Job == "" {
+		settings.Job = "cfctl"
+	}
+	if settings.Instance == "" {
+		settings.Instance, _ = os.Hostname()
+	}
+	return settings
+}
+
+// loadCallMetrics returns the metrics of the process, or nil when no metrics are configured
+func loadCallMetrics() *callMetrics {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	if metricsLoaded {
+		return metrics
+	}
+	metricsLoaded = true
+
+	settings := LoadMetricsSettings()
+	if settings.StatsdAddr == "" && settings.PushgatewayURL == "" {
+		metrics = nil
+		return nil
+	}
+
+	metrics = &callMetrics{settings: settings, calls: make(map[[2]string]*callMetric), stop: make(chan struct{})}
+	if settings.StatsdAddr != "" {
+		conn, err := net.Dial("udp", settings.StatsdAddr)
+		if err != nil {
+			pterm.Warning.WithWriter(os.Stderr).Printf("Failed to connect to statsd at %s: %v\n", settings.StatsdAddr, err)
+		} else {
+			metrics.statsd = conn
+		}
+	}
+	if settings.PushgatewayURL != "" {
+		go metrics.pushLoop()
+	}
+	return metrics
+}
+
+// ReloadMetricsSettings pushes the pending metrics and makes the next connection read the
+// metrics settings again, e.g. after the setting file changed in a long running command
+func ReloadMetricsSettings() {
+	metricsMu.Lock()
+	previous := metrics
+	metrics, metricsLoaded = nil, false
+	metricsMu.Unlock()
+
+	if previous != nil {
+		previous.close()
+	}
+}
+
+// FlushMetrics pushes the metrics which have not been pushed yet.
+// It is called before the process exits, since the pushgateway is only updated in the background.
+func FlushMetrics() {
+	metricsMu.Lock()
+	m := metrics
+	metricsMu.Unlock()
+
+	if m != nil && m.settings.PushgatewayURL != "" {
+		m.flush()
+	}
+}
+
+// close stops the push loop after a last push and closes the statsd connection
+func (m *callMetrics) close() {
+	if m.settings.PushgatewayURL != "" {
+		close(m.stop)
+		m.flush()
+	}
+	if m.statsd != nil {
+		m.statsd.Close()
+	}
+}
+
+// record emits the metrics of a finished call. Calls are sent to statsd right away and
+// aggregated for the pushgateway, so that the call is never delayed by a push.
+func (m *callMetrics) record(method string, duration time.Duration, code codes.Code) {
+	if m.statsd != nil {
+		name := m.settings.Prefix + "." + strings.ReplaceAll(strings.TrimPrefix(method, "/"), "/", ".")
+		fmt.Fprintf(m.statsd, "%s.calls.%s:1|c\n%s.duration:%d|ms\n", name, code, name, duration.Milliseconds())
+	}
+
+	if m.settings.PushgatewayURL != "" {
+		m.mu.Lock()
+		key := [2]string{method, code.String()}
+		if m.calls[key] == nil {
+			m.calls[key] = &callMetric{}
+		}
+		m.calls[key].count++
+		m.calls[key].duration += duration
+		m.dirty = true
+		m.mu.Unlock()
+	}
+}
+
+// pushLoop pushes the aggregated metrics periodically for long running commands
+func (m *callMetrics) pushLoop() {
+	ticker := time.NewTicker(metricsPushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.flush()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// flush pushes the aggregated metrics if calls were recorded since the last push
+func (m *callMetrics) flush() {
+	m.pushMu.Lock()
+	defer m.pushMu.Unlock()
+
+	m.mu.Lock()
+	if !m.dirty {
+		m.mu.Unlock()
+		return
+	}
+	body := m.prometheusText()
+	m.dirty = false
+	m.mu.Unlock()
+
+	if err := m.push(body); err != nil {
+		m.warnOnce.Do(func() {
+			pterm.Warning.WithWriter(os.Stderr).Printf("Failed to push metrics: %v\n", err)
+		})
+	}
+}
+
+// prometheusText formats the calls of the process in the Prometheus text format
+func (m *callMetrics) prometheusText() []byte {
+	keys := make([][2]string, 0, len(m.calls))
+	for key := range m.calls {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i][0]+" "+keys[i][1] < keys[j][0]+" "+keys[j][1]
+	})
+
+	var buf bytes.Buffer
+	prefix := strings.ReplaceAll(m.settings.Prefix, ".", "_")
+	fmt.Fprintf(&buf, "# TYPE %s_calls_total counter\n", prefix)
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "%s_calls_total{method=%q,code=%q} %d\n", prefix, key[0], key[1], m.calls[key].count)
+	}
+	fmt.Fprintf(&buf, "# TYPE %s_call_duration_seconds_total counter\n", prefix)
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "%s_call_duration_seconds_total{method=%q,code=%q} %g\n", prefix, key[0], key[1], m.calls[key].duration.Seconds())
+	}
+	return buf.Bytes()
+}
+
+// push replaces the metrics of the job and instance on the pushgateway
+func (m *callMetrics) push(body []byte) error {
+	pushURL := strings.TrimRight(m.settings.PushgatewayURL, "/") + "/metrics/job/" + url.PathEscape(m.settings.Job)
+	if m.settings.Instance != "" {
+		pushURL += "/instance/" + url.PathEscape(m.settings.Instance)
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Post(pushURL, "text/plain; version=0.0.4", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("pushgateway returned %s", resp.Status)
+	}
+	return nil
+}
+
+// metricsOptions returns the interceptors recording the metrics of calls when metrics are configured
+func metricsOptions() []grpc.DialOption {
+	m := loadCallMetrics()
+	if m == nil {
+		return nil
+	}
+
+	unary := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		m.record(method, time.Since(start), status.Code(err))
+		return err
+	}
+	stream := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		// Server reflection is part of every command rather than an API call
+		if strings.HasPrefix(method, "/grpc.reflection.") {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+
+		start := time.Now()
+		clientStream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			m.record(method, time.Since(start), status.Code(err))
+			return nil, err
+		}
+		return &metricsStream{ClientStream: clientStream, metrics: m, method: method, start: start}, nil
+	}
+
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(unary),
+		grpc.WithChainStreamInterceptor(stream),
+	}
+}
+
+// metricsStream records the metrics of a stream when it ends
+type metricsStream struct {
+	grpc.ClientStream
+	metrics *callMetrics
+	method  string
+	start   time.Time
+	once    sync.Once
+}
+
+func (s *metricsStream) RecvMsg(msg interface{}) error {
+	err := s.ClientStream.RecvMsg(msg)
+	if err != nil {
+		code := status.Code(err)
+		if err == io.EOF {
+			code = codes.OK
+		}
+		s.once.Do(func() {
+			s.metrics.record(s.method, time.Since(s.start), code)
+		})
+	}
+	return err
+}
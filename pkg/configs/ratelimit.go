@@ -0,0 +1,105 @@
+package configs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// rateLimiter is a token bucket shared by all connections of the process,
+// since servers throttle per token rather than per connection
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64   // Tokens added per second
+	burst  float64   // Maximum number of tokens
+	tokens float64   // Tokens currently available
+	last   time.Time // Last time tokens were added
+}
+
+var (
+	callLimiter   *rateLimiter
+	callLimiterMu sync.Mutex
+)
+
+// newRateLimiter creates a full bucket allowing rps calls per second with bursts of burst calls
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a call is allowed or the context is done
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// sharedRateLimiter returns the limiter of the process, which is replaced when the rate limit
+// of the settings changed after a reload
+func sharedRateLimiter(rps float64, burst int) *rateLimiter {
+	callLimiterMu.Lock()
+	defer callLimiterMu.Unlock()
+
+	if callLimiter == nil || callLimiter.rate != rps || callLimiter.burst != float64(max(burst, 1)) {
+		callLimiter = newRateLimiter(rps, burst)
+	}
+	return callLimiter
+}
+
+// rateLimitOptions returns the interceptors delaying calls beyond the rate limit of the environment
+func rateLimitOptions(settings TransportSettings) []grpc.DialOption {
+	if settings.RateLimit <= 0 {
+		return nil
+	}
+
+	limiter := sharedRateLimiter(settings.RateLimit, settings.RateBurst)
+
+	unary := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	stream := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(unary),
+		grpc.WithChainStreamInterceptor(stream),
+	}
+}
@@ -0,0 +1,14 @@
+package configs
+
+import (
+	"context"
+
+	"github.com/jhump/protoreflect/grpcreflect"
+	"google.golang.org/grpc"
+)
+
+// NewReflectionClient creates a reflection client for the connection.
+// It tries grpc.reflection.v1 first and falls back to v1alpha for servers that do not implement it.
+func NewReflectionClient(ctx context.Context, conn grpc.ClientConnInterface) *grpcreflect.Client {
+	return grpcreflect.NewClientAuto(ctx, conn)
+}
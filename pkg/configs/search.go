@@ -0,0 +1,40 @@
+package configs
+
+// SearchTarget is a resource type searched by 'cfctl search'
+type SearchTarget struct {
+	Service  string `mapstructure:"service"`
+	Resource string `mapstructure:"resource"`
+	IDField  string `mapstructure:"id_field"` // Empty unless set in the setting file
+}
+
+// defaultSearchTargets are searched when the setting file has no search targets
+var defaultSearchTargets = []SearchTarget{
+	{Service: "inventory", Resource: "Server"},
+	{Service: "identity", Resource: "Project"},
+	{Service: "identity", Resource: "User"},
+	{Service: "identity", Resource: "ServiceAccount"},
+}
+
+// LoadSearchTargets reads the resource types searched by 'cfctl search' from the setting file
+// Example:
+//
+//	search:
+//	  targets:
+//	    - service: inventory
+//	      resource: CloudService
+//	    - service: identity
+//	      resource: Project
+func LoadSearchTargets() []SearchTarget {
+	targets := defaultSearchTargets
+
+	if settingPath, err := GetSettingFilePath(); err == nil {
+		if v, err := setViperWithSetting(settingPath); err == nil && v.IsSet("search.targets") {
+			var configured []SearchTarget
+			if err := v.UnmarshalKey("search.targets", &configured); err == nil && len(configured) > 0 {
+				targets = configured
+			}
+		}
+	}
+
+	return targets
+}
@@ -0,0 +1,69 @@
+package configs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// builtinServiceNames are the endpoint names of services whose names differ from their host labels
+var builtinServiceNames = map[string]string{
+	"cost_analysis": "cost-analysis",
+	"file_manager":  "file-manager",
+	"alert_manager": "alert-manager",
+}
+
+// LoadServiceNames returns the endpoint names of services, the built-in ones overridden by
+// the service_names section of the setting file
+// Example:
+//
+//	service_names:
+//	  new_service: new-service
+func LoadServiceNames() map[string]string {
+	names := make(map[string]string, len(builtinServiceNames))
+	for service, name := range builtinServiceNames {
+		names[service] = name
+	}
+
+	settingPath, err := GetSettingFilePath()
+	if err != nil {
+		return names
+	}
+	v, err := setViperWithSetting(settingPath)
+	if err != nil {
+		return names
+	}
+	for service, name := range v.GetStringMapString("service_names") {
+		names[service] = name
+	}
+	return names
+}
+
+// ServiceEndpointName returns the endpoint name of a service, replacing underscores
+// with hyphens for services without a configured name
+// Example:
+//
+//	cost_analysis -> cost-analysis
+func ServiceEndpointName(serviceName string) string {
+	if name, ok := LoadServiceNames()[serviceName]; ok && name != "" {
+		return name
+	}
+	return strings.ReplaceAll(serviceName, "_", "-")
+}
+
+// SetServiceName saves the endpoint name of a service in the setting file
+func SetServiceName(serviceName, name string) error {
+	settingPath, err := GetSettingFilePath()
+	if err != nil {
+		return err
+	}
+	v, err := setViperWithSetting(settingPath)
+	if err != nil {
+		return err
+	}
+
+	v.Set(fmt.Sprintf("service_names.%s", serviceName), name)
+	if err := v.WriteConfig(); err != nil {
+		return fmt.Errorf("failed to save service name: %v", err)
+	}
+	return nil
+}
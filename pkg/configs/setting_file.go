@@ -17,9 +17,12 @@ type Environments struct {
 
 // Environment represents a single environment configuration
 type Environment struct {
-	Endpoint string `yaml:"endpoint"` // gRPC or HTTP endpoint URL
-	Proxy    string `yaml:"proxy"`    // Proxy server address if required
-	Token    string `yaml:"token"`    // Authentication token
+	Endpoint  string            `yaml:"endpoint"`  // gRPC or HTTP endpoint URL
+	Proxy     string            `yaml:"proxy"`     // Proxy server address if required
+	Token     string            `yaml:"token"`     // Authentication token
+	Mode      string            `yaml:"mode"`      // Call mode, "admin" uses a domain scope token
+	Endpoints map[string]string `yaml:"endpoints"` // Explicit gRPC endpoints by service name
+	Auth      AuthHeader        `yaml:"-"`         // Metadata carrying the token, see LoadAuthHeader
 }
 
 // SetSettingFile loads the setting from the default location (~/.cfctl/setting.yaml)
@@ -87,6 +90,10 @@ func getCurrentEnvValues(env string) (*Environment, error) {
 	envSetting := &Environment{
 		Endpoint: v.GetString(fmt.Sprintf("environments.%s.endpoint", env)),
 		Proxy:    v.GetString(fmt.Sprintf("environments.%s.proxy", env)),
+		Mode:     v.GetString(fmt.Sprintf("environments.%s.mode", env)),
+
+		Endpoints: v.GetStringMapString(fmt.Sprintf("environments.%s.endpoints", env)),
+		Auth:      authHeader(v, env),
 	}
 
 	if err := loadToken(env, envSetting); err != nil {
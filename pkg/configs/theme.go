@@ -0,0 +1,40 @@
+package configs
+
+// ThemeRule colors the table values of a field which equal a value or match a regular expression
+type ThemeRule struct {
+	Field string `mapstructure:"field"` // Any field when empty
+	Value string `mapstructure:"value"` // Compared ignoring case
+	Match string `mapstructure:"match"` // Regular expression, used when value is empty
+	Color string `mapstructure:"color"`
+}
+
+// Theme is the coloring of table and watch output
+type Theme struct {
+	Preset string      `mapstructure:"preset"` // dark, light or none
+	Rules  []ThemeRule `mapstructure:"rules"`  // Checked in order before the colors of the preset
+}
+
+// LoadTheme reads the theme section of the setting file
+// Example:
+//
+//	theme:
+//	  preset: light
+//	  rules:
+//	    - field: state
+//	      value: DELETED
+//	      color: gray
+//	    - field: provider
+//	      match: ^aws
+//	      color: yellow
+func LoadTheme() Theme {
+	var theme Theme
+	if settingPath, err := GetSettingFilePath(); err == nil {
+		if v, err := setViperWithSetting(settingPath); err == nil && v.IsSet("theme") {
+			_ = v.UnmarshalKey("theme", &theme)
+		}
+	}
+	if theme.Preset == "" {
+		theme.Preset = "dark"
+	}
+	return theme
+}
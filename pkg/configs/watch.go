@@ -0,0 +1,92 @@
+package configs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// settingWatchDelay coalesces the events of one write, e.g. an editor saving through a temporary file
+const settingWatchDelay = 200 * time.Millisecond
+
+// SettingWatcher reports changes of the setting file and the token cache files of an environment
+type SettingWatcher struct {
+	Changes <-chan string // Paths of the changed files
+	watcher *fsnotify.Watcher
+}
+
+// WatchSettings watches ~/.cfctl/setting.yaml and the access and refresh token files of the environment
+// for long running commands. The directories are watched rather than the files, since the files are
+// replaced by renames, e.g. by cfctl session refresh.
+func WatchSettings(environment string) (*SettingWatcher, error) {
+	settingPath, err := GetSettingFilePath()
+	if err != nil {
+		return nil, err
+	}
+	cacheDir := filepath.Join(filepath.Dir(settingPath), "cache", environment)
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %v", err)
+	}
+
+	watched := map[string]bool{
+		filepath.Clean(settingPath):              true,
+		filepath.Join(cacheDir, "access_token"):  true,
+		filepath.Join(cacheDir, "refresh_token"): true,
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %v", err)
+	}
+	for _, dir := range []string{filepath.Dir(settingPath), cacheDir} {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return nil, fmt.Errorf("failed to watch %s: %v", dir, err)
+		}
+	}
+
+	changes := make(chan string, len(watched))
+	go func() {
+		defer close(changes)
+
+		pending := make(map[string]bool)
+		var timer <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if !watched[filepath.Clean(event.Name)] || event.Op == fsnotify.Chmod {
+					continue
+				}
+				pending[filepath.Clean(event.Name)] = true
+				timer = time.After(settingWatchDelay)
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			case <-timer:
+				for path := range pending {
+					// A full channel already has changes for the reader to handle
+					select {
+					case changes <- path:
+					default:
+					}
+				}
+				pending = make(map[string]bool)
+				timer = nil
+			}
+		}
+	}()
+
+	return &SettingWatcher{Changes: changes, watcher: watcher}, nil
+}
+
+// Close stops watching and closes the changes channel
+func (w *SettingWatcher) Close() error {
+	return w.watcher.Close()
+}
@@ -0,0 +1,79 @@
+package endpoints
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// cacheTTL is how long the cached endpoints of an environment are used before they are fetched again
+const cacheTTL = 24 * time.Hour
+
+// cacheFile returns the path of the endpoints cache of the environment
+func cacheFile(env string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find home directory: %v", err)
+	}
+
+	return filepath.Join(home, ".cfctl", "cache", env, "endpoints.yaml"), nil
+}
+
+// LoadCache reads the service endpoints cached for the environment.
+// An expired cache is reported as an error.
+func LoadCache(env string) (map[string]string, error) {
+	if env == "" {
+		return nil, fmt.Errorf("no environment set")
+	}
+
+	path, err := cacheFile(env)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(info.ModTime()) > cacheTTL {
+		return nil, fmt.Errorf("cache expired")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpointsMap map[string]string
+	if err := yaml.Unmarshal(data, &endpointsMap); err != nil {
+		return nil, err
+	}
+
+	return endpointsMap, nil
+}
+
+// SaveCache writes the service endpoints of the environment to the cache
+func SaveCache(env string, endpointsMap map[string]string) error {
+	if env == "" {
+		return fmt.Errorf("no environment set")
+	}
+
+	path, err := cacheFile(env)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(endpointsMap)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
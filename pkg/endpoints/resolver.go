@@ -0,0 +1,267 @@
+package endpoints
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"google.golang.org/grpc"
+)
+
+// defaultPort is used when an endpoint does not specify a port
+const defaultPort = "443"
+
+// Target is the resolved gRPC address of a service
+type Target struct {
+	HostPort string // Address to dial, always in host:port form
+	Insecure bool   // Plaintext connection for grpc:// endpoints
+}
+
+// String returns the target in endpoint form
+// Example:
+//
+//	grpc+ssl://inventory.api.example.com:443
+func (t *Target) String() string {
+	if t.Insecure {
+		return "grpc://" + t.HostPort
+	}
+	return "grpc+ssl://" + t.HostPort
+}
+
+// Credentials returns the transport credentials dial option of the target
+func (t *Target) Credentials() grpc.DialOption {
+	return configs.Credentials(!t.Insecure)
+}
+
+// Resolver resolves the endpoints of services for an environment.
+// Overrides are read from the endpoints map of the environment setting
+// Example:
+//
+//	environments:
+//	  onprem-user:
+//	    endpoint: https://console.example.com
+//	    endpoints:
+//	      inventory: grpc+ssl://inventory.internal:443
+type Resolver struct {
+	Environment        string            // Name of the environment, used for the endpoints cache
+	Endpoint           string            // Endpoint configured for the environment
+	Overrides          map[string]string // Explicit endpoints by service name
+	APIEndpoint        string            // Console API endpoint discovered from Endpoint
+	IdentityEndpoint   string            // Identity service endpoint discovered from APIEndpoint
+	HasIdentityService bool              // Whether the identity service endpoint was found
+
+	routes       map[string]string // Service endpoints listed by the identity service
+	routesLoaded bool
+}
+
+// NewResolver creates a resolver for the given environment endpoint.
+// Console endpoints are resolved to their API and identity endpoints once, local
+// grpc:// endpoints need no discovery since every service is served at the same address.
+func NewResolver(env, endpoint string, overrides map[string]string) (*Resolver, error) {
+	r := &Resolver{
+		Environment: env,
+		Endpoint:    endpoint,
+		Overrides:   overrides,
+	}
+
+	if strings.HasPrefix(endpoint, "grpc://") {
+		return r, nil
+	}
+
+	apiEndpoint, err := configs.GetAPIEndpoint(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API endpoint: %v", err)
+	}
+	r.APIEndpoint = apiEndpoint
+
+	r.IdentityEndpoint, r.HasIdentityService, err = configs.GetIdentityEndpoint(apiEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identity endpoint: %v", err)
+	}
+
+	return r, nil
+}
+
+// Resolve returns the target of the given service.
+// An explicit override takes precedence, then the endpoint listed by the identity service.
+// The address is only derived from the identity, grpc+ssl or console API endpoint hostname
+// when the endpoint list is unavailable.
+func (r *Resolver) Resolve(serviceName string) (*Target, error) {
+	if override, ok := r.Overrides[serviceName]; ok && override != "" {
+		return ParseTarget(override)
+	}
+
+	if strings.HasPrefix(r.Endpoint, "grpc://") {
+		return ParseTarget(r.Endpoint)
+	}
+
+	if endpoint, ok := r.route(serviceName); ok {
+		return ParseTarget(endpoint)
+	}
+
+	switch {
+	case r.HasIdentityService:
+		return ReplaceService(r.IdentityEndpoint, serviceName)
+	case strings.HasPrefix(r.Endpoint, "grpc+ssl://"):
+		return ReplaceService(r.Endpoint, serviceName)
+	case r.APIEndpoint != "":
+		return ReplaceService(r.APIEndpoint, serviceName)
+	}
+
+	return nil, fmt.Errorf("unable to resolve endpoint of %s service from %s", serviceName, r.Endpoint)
+}
+
+// Endpoints returns the endpoints of all services known to the environment, including overrides.
+// The list is read from the environment cache or fetched from the identity service and cached.
+func (r *Resolver) Endpoints() (map[string]string, error) {
+	if err := r.loadRoutes(); err != nil {
+		return nil, err
+	}
+
+	endpointsMap := make(map[string]string, len(r.routes)+len(r.Overrides))
+	for service, endpoint := range r.routes {
+		endpointsMap[service] = endpoint
+	}
+	for service, endpoint := range r.Overrides {
+		if endpoint != "" {
+			endpointsMap[service] = endpoint
+		}
+	}
+
+	return endpointsMap, nil
+}
+
+// loadRoutes reads the endpoint list of the identity service once
+func (r *Resolver) loadRoutes() error {
+	if r.routesLoaded {
+		return nil
+	}
+
+	routes, err := LoadCache(r.Environment)
+	if err != nil {
+		discoveryEndpoint := r.APIEndpoint
+		if strings.HasPrefix(r.Endpoint, "grpc://") {
+			discoveryEndpoint = r.Endpoint
+		}
+		if discoveryEndpoint == "" {
+			return fmt.Errorf("no endpoint to discover services from")
+		}
+
+		routes, err = configs.FetchEndpointsMap(discoveryEndpoint)
+		if err != nil {
+			return fmt.Errorf("failed to fetch endpoints: %v", err)
+		}
+		if r.Environment != "" {
+			_ = SaveCache(r.Environment, routes)
+		}
+	}
+
+	r.routes = routes
+	r.routesLoaded = true
+	return nil
+}
+
+// route looks up the service in the endpoint list of the identity service
+func (r *Resolver) route(serviceName string) (string, bool) {
+	if err := r.loadRoutes(); err != nil {
+		// Remember the failure so that the heuristic is used without retrying
+		r.routesLoaded = true
+		return "", false
+	}
+
+	for _, name := range []string{serviceName, hostLabel(serviceName)} {
+		if endpoint, ok := r.routes[name]; ok && endpoint != "" {
+			return endpoint, true
+		}
+	}
+
+	return "", false
+}
+
+// ParseTarget parses an endpoint like grpc+ssl://identity.example.com:443/v1.
+// Paths are dropped and the port defaults to 443.
+func ParseTarget(endpoint string) (*Target, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint format: %s", endpoint)
+	}
+
+	target := &Target{}
+	switch u.Scheme {
+	case "grpc":
+		target.Insecure = true
+	case "grpc+ssl", "https":
+	default:
+		return nil, fmt.Errorf("unsupported scheme in endpoint: %s", endpoint)
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return nil, fmt.Errorf("invalid endpoint format: %s", endpoint)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	target.HostPort = net.JoinHostPort(host, port)
+
+	return target, nil
+}
+
+// ReplaceService derives the target of a service by replacing the first label of the
+// endpoint's hostname with the service name.
+// Example:
+//
+//	grpc+ssl://identity.api.example.com:443, cost_analysis -> cost-analysis.api.example.com:443
+func ReplaceService(endpoint, serviceName string) (*Target, error) {
+	target, err := ParseTarget(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	host, port, err := net.SplitHostPort(target.HostPort)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint format: %s", endpoint)
+	}
+
+	labels := strings.Split(host, ".")
+	if net.ParseIP(host) != nil || len(labels) < 2 {
+		return nil, fmt.Errorf("cannot derive %s endpoint from %s, configure the service endpoint explicitly", serviceName, endpoint)
+	}
+
+	labels[0] = hostLabel(serviceName)
+	target.HostPort = net.JoinHostPort(strings.Join(labels, "."), port)
+
+	return target, nil
+}
+
+// ServiceOf returns the service a grpc+ssl endpoint points to, which is the first label of its hostname.
+// Other endpoints do not point to a single service and return an empty string.
+func ServiceOf(endpoint string) string {
+	if !strings.HasPrefix(endpoint, "grpc+ssl://") {
+		return ""
+	}
+
+	target, err := ParseTarget(endpoint)
+	if err != nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(target.HostPort)
+	if err != nil || net.ParseIP(host) != nil {
+		return ""
+	}
+
+	return strings.Split(host, ".")[0]
+}
+
+// hostLabel converts a service name to its DNS label, see configs.LoadServiceNames
+// Example:
+//
+//	cost_analysis -> cost-analysis
+func hostLabel(serviceName string) string {
+	return configs.ServiceEndpointName(serviceName)
+}
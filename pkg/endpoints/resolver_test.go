@@ -0,0 +1,231 @@
+package endpoints
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+)
+
+// testHome points the home directory to a temporary directory whose setting file keeps
+// failed discovery calls short
+func testHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	dir := filepath.Join(home, ".cfctl")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	setting := "environment: test-user\nenvironments:\n  test-user:\n    dial_timeout: 100ms\n"
+	if err := os.WriteFile(filepath.Join(dir, "setting.yaml"), []byte(setting), 0600); err != nil {
+		t.Fatal(err)
+	}
+	configs.ReloadTransportSettings()
+	t.Cleanup(configs.ReloadTransportSettings)
+	return home
+}
+
+func TestResolverResolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoint  string
+		overrides map[string]string
+		cache     map[string]string // Endpoints cache of the environment, nil for none
+		cacheAge  time.Duration
+		service   string
+		want      string
+		insecure  bool
+		wantErr   string
+	}{
+		{
+			name:      "static endpoint",
+			endpoint:  "grpc+ssl://identity.api.example.com:443",
+			overrides: map[string]string{"inventory": "grpc+ssl://inventory.internal:8443"},
+			cache:     map[string]string{"inventory": "grpc+ssl://inventory.api.example.com:443"},
+			service:   "inventory",
+			want:      "inventory.internal:8443",
+		},
+		{
+			name:     "identity discovered endpoint from cache",
+			endpoint: "grpc+ssl://identity.api.example.com:443",
+			cache:    map[string]string{"inventory": "grpc+ssl://inventory-v2.api.example.com:443/v1"},
+			service:  "inventory",
+			want:     "inventory-v2.api.example.com:443",
+		},
+		{
+			name:     "discovered endpoint by host label",
+			endpoint: "grpc+ssl://identity.api.example.com:443",
+			cache:    map[string]string{"cost-analysis": "grpc+ssl://cost.api.example.com:443"},
+			service:  "cost_analysis",
+			want:     "cost.api.example.com:443",
+		},
+		{
+			name:     "cache miss derives from identity endpoint",
+			endpoint: "grpc+ssl://identity.api.example.com:443",
+			service:  "inventory",
+			want:     "inventory.api.example.com:443",
+		},
+		{
+			name:     "expired cache derives from identity endpoint",
+			endpoint: "grpc+ssl://identity.api.example.com:443",
+			cache:    map[string]string{"inventory": "grpc+ssl://inventory-v2.api.example.com:443"},
+			cacheAge: 25 * time.Hour,
+			service:  "inventory",
+			want:     "inventory.api.example.com:443",
+		},
+		{
+			name:     "unknown service of an address endpoint",
+			endpoint: "grpc+ssl://10.0.0.1:443",
+			cache:    map[string]string{"identity": "grpc+ssl://10.0.0.1:443"},
+			service:  "inventory",
+			wantErr:  "configure the service endpoint explicitly",
+		},
+		{
+			name:     "local endpoint serves every service",
+			endpoint: "grpc://localhost:50051",
+			service:  "inventory",
+			want:     "localhost:50051",
+			insecure: true,
+		},
+		{
+			name:      "local endpoint with override",
+			endpoint:  "grpc://localhost:50051",
+			overrides: map[string]string{"inventory": "grpc://localhost:50052"},
+			service:   "inventory",
+			want:      "localhost:50052",
+			insecure:  true,
+		},
+		{
+			name:     "cluster endpoint",
+			endpoint: "grpc://identity.spaceone.svc.cluster.local:50051",
+			service:  "inventory",
+			want:     "identity.spaceone.svc.cluster.local:50051",
+			insecure: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testHome(t)
+			if tt.cache != nil {
+				if err := SaveCache("test-user", tt.cache); err != nil {
+					t.Fatal(err)
+				}
+				if tt.cacheAge > 0 {
+					path, _ := cacheFile("test-user")
+					old := time.Now().Add(-tt.cacheAge)
+					if err := os.Chtimes(path, old, old); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			resolver, err := NewResolver("test-user", tt.endpoint, tt.overrides)
+			if err != nil {
+				t.Fatalf("NewResolver() error = %v", err)
+			}
+			target, err := resolver.Resolve(tt.service)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if target.HostPort != tt.want || target.Insecure != tt.insecure {
+				t.Errorf("Resolve() = %s (insecure %v), want %s (insecure %v)", target.HostPort, target.Insecure, tt.want, tt.insecure)
+			}
+		})
+	}
+}
+
+func TestResolverWithoutEndpoint(t *testing.T) {
+	testHome(t)
+
+	resolver := &Resolver{Environment: "test-user"}
+	if _, err := resolver.Resolve("inventory"); err == nil {
+		t.Error("Resolve() without endpoint succeeded, want an error")
+	}
+
+	resolver = &Resolver{Environment: "test-user"}
+	if _, err := resolver.Endpoints(); err == nil {
+		t.Error("Endpoints() without endpoint succeeded, want an error")
+	}
+}
+
+func TestResolverEndpoints(t *testing.T) {
+	testHome(t)
+	if err := SaveCache("test-user", map[string]string{
+		"identity":  "grpc+ssl://identity.api.example.com:443",
+		"inventory": "grpc+ssl://inventory.api.example.com:443",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resolver, err := NewResolver("test-user", "grpc+ssl://identity.api.example.com:443", map[string]string{
+		"inventory": "grpc+ssl://inventory.internal:443",
+		"board":     "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := resolver.Endpoints()
+	if err != nil {
+		t.Fatalf("Endpoints() error = %v", err)
+	}
+	want := map[string]string{
+		"identity":  "grpc+ssl://identity.api.example.com:443",
+		"inventory": "grpc+ssl://inventory.internal:443",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Endpoints() = %v, want %v", got, want)
+	}
+	for service, endpoint := range want {
+		if got[service] != endpoint {
+			t.Errorf("Endpoints()[%s] = %s, want %s", service, got[service], endpoint)
+		}
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+		insecure bool
+		wantErr  bool
+	}{
+		{endpoint: "grpc+ssl://identity.example.com:443/v1", want: "identity.example.com:443"},
+		{endpoint: "grpc+ssl://identity.example.com", want: "identity.example.com:443"},
+		{endpoint: "https://identity.example.com:8443", want: "identity.example.com:8443"},
+		{endpoint: "grpc://localhost:50051", want: "localhost:50051", insecure: true},
+		{endpoint: "ftp://identity.example.com", wantErr: true},
+		{endpoint: "grpc+ssl://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			target, err := ParseTarget(tt.endpoint)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseTarget() = %v, want an error", target)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTarget() error = %v", err)
+			}
+			if target.HostPort != tt.want || target.Insecure != tt.insecure {
+				t.Errorf("ParseTarget() = %s (insecure %v), want %s (insecure %v)", target.HostPort, target.Insecure, tt.want, tt.insecure)
+			}
+		})
+	}
+}
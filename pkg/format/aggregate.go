@@ -0,0 +1,112 @@
+package format
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AggregateResults groups list results by one or more fields and aggregates each group.
+// Supported aggregations are "count" and "sum:<field>".
+// Example:
+//
+//	AggregateResults(results, "provider", "count")
+//	-> [{provider: aws, count: 10}, {provider: google_cloud, count: 3}]
+func AggregateResults(results []interface{}, groupBy string, agg string) ([]interface{}, error) {
+	groupFields := strings.Split(groupBy, ",")
+	for i := range groupFields {
+		groupFields[i] = strings.TrimSpace(groupFields[i])
+	}
+
+	if agg == "" {
+		agg = "count"
+	}
+
+	var sumField string
+	switch {
+	case agg == "count":
+	case strings.HasPrefix(agg, "sum:") && len(agg) > len("sum:"):
+		sumField = strings.TrimPrefix(agg, "sum:")
+	default:
+		return nil, fmt.Errorf("unsupported aggregation '%s' (use count or sum:<field>)", agg)
+	}
+	aggKey := "count"
+	if sumField != "" {
+		aggKey = fmt.Sprintf("sum(%s)", sumField)
+	}
+
+	type group struct {
+		values []interface{}
+		value  float64
+	}
+	groups := make(map[string]*group)
+	var order []string
+
+	for _, result := range results {
+		row, ok := result.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		values := make([]interface{}, len(groupFields))
+		keyParts := make([]string, len(groupFields))
+		for i, field := range groupFields {
+			if val, ok := GetValueByPath(row, field); ok {
+				values[i] = val
+				keyParts[i] = fmt.Sprintf("%v", val)
+			}
+		}
+		key := strings.Join(keyParts, "\x00")
+
+		g, exists := groups[key]
+		if !exists {
+			g = &group{values: values}
+			groups[key] = g
+			order = append(order, key)
+		}
+
+		if sumField == "" {
+			g.value++
+			continue
+		}
+		if val, ok := GetValueByPath(row, sumField); ok {
+			if n, ok := toFloat64(val); ok {
+				g.value += n
+			}
+		}
+	}
+
+	// Largest groups first for a more readable report
+	sort.SliceStable(order, func(i, j int) bool {
+		return groups[order[i]].value > groups[order[j]].value
+	})
+
+	aggregated := make([]interface{}, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		row := make(map[string]interface{})
+		for i, field := range groupFields {
+			row[field] = g.values[i]
+		}
+		row[aggKey] = g.value
+		aggregated = append(aggregated, row)
+	}
+
+	return aggregated, nil
+}
+
+func toFloat64(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		n, err := strconv.ParseFloat(v, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
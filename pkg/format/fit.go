@@ -0,0 +1,150 @@
+package format
+
+import (
+	"os"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/pterm/pterm"
+	"golang.org/x/term"
+)
+
+// maxCellWidth is the width long cells are shortened to before columns are hidden
+const maxCellWidth = 40
+
+// minCellWidth is the narrowest a column is shortened to when it is the only one left
+const minCellWidth = 8
+
+// tableSeparatorWidth is the width of the separator of pterm tables between columns
+var tableSeparatorWidth = runewidth.StringWidth(pterm.DefaultTable.Separator)
+
+// TerminalWidth returns the width of the terminal of stdout, or 0 when the output is not a terminal
+func TerminalWidth() int {
+	fd := int(os.Stdout.Fd())
+	if !term.IsTerminal(fd) {
+		return 0
+	}
+	width, _, err := term.GetSize(fd)
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// FitTable shortens long cells and hides the lowest priority columns of a table with a header row
+// so that its rows fit in the width instead of wrapping across lines. Columns named in priority
+// rank first in that order, the other columns keep their order after them.
+// It returns the fitted table and the names of the hidden columns. Tables are unchanged for a width of 0.
+func FitTable(table pterm.TableData, width int, priority []string) (pterm.TableData, []string) {
+	if width <= 0 || len(table) == 0 || tableWidth(columnWidths(table)) <= width {
+		return table, nil
+	}
+
+	headers := table[0]
+	table = shortenCells(table, maxCellWidth)
+	widths := columnWidths(table)
+
+	// Rank the columns, the last one is hidden first
+	ranked := make([]int, 0, len(headers))
+	used := make(map[int]bool)
+	for _, name := range priority {
+		for i, header := range headers {
+			if header == name && !used[i] {
+				ranked = append(ranked, i)
+				used[i] = true
+			}
+		}
+	}
+	for i := range headers {
+		if !used[i] {
+			ranked = append(ranked, i)
+		}
+	}
+
+	visible := make(map[int]bool, len(headers))
+	for i := range headers {
+		visible[i] = true
+	}
+	var hidden []string
+	for len(ranked) > 1 && tableWidth(visibleWidths(widths, visible)) > width {
+		last := ranked[len(ranked)-1]
+		ranked = ranked[:len(ranked)-1]
+		visible[last] = false
+		hidden = append(hidden, headers[last])
+	}
+
+	fitted := make(pterm.TableData, len(table))
+	for r, row := range table {
+		for i, cell := range row {
+			if visible[i] {
+				fitted[r] = append(fitted[r], cell)
+			}
+		}
+	}
+
+	// Shorten the last column left when it is still too wide
+	if len(ranked) == 1 && widths[ranked[0]] > width {
+		fitted = shortenCells(fitted, max(width, minCellWidth))
+	}
+
+	// Hidden columns are reported in the order of the table
+	ordered := make([]string, 0, len(hidden))
+	for i, header := range headers {
+		if !visible[i] {
+			ordered = append(ordered, header)
+		}
+	}
+	return fitted, ordered
+}
+
+// shortenCells returns the table with the cells wider than the limit cut off with an ellipsis
+func shortenCells(table pterm.TableData, limit int) pterm.TableData {
+	shortened := make(pterm.TableData, len(table))
+	for r, row := range table {
+		shortened[r] = make([]string, len(row))
+		for i, cell := range row {
+			plain := pterm.RemoveColorFromString(cell)
+			if runewidth.StringWidth(plain) > limit {
+				cell = runewidth.Truncate(plain, limit, "…")
+			}
+			shortened[r][i] = cell
+		}
+	}
+	return shortened
+}
+
+// columnWidths returns the widest cell of each column
+func columnWidths(table pterm.TableData) []int {
+	var widths []int
+	for _, row := range table {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], runewidth.StringWidth(pterm.RemoveColorFromString(cell)))
+		}
+	}
+	return widths
+}
+
+// visibleWidths returns the widths of the visible columns
+func visibleWidths(widths []int, visible map[int]bool) []int {
+	var result []int
+	for i, width := range widths {
+		if visible[i] {
+			result = append(result, width)
+		}
+	}
+	return result
+}
+
+// tableWidth returns the width of a table row with columns of the widths
+func tableWidth(widths []int) int {
+	total := 0
+	for _, width := range widths {
+		total += width
+	}
+	if len(widths) > 1 {
+		total += tableSeparatorWidth * (len(widths) - 1)
+	}
+	return total
+}
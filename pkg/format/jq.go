@@ -0,0 +1,47 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/itchyny/gojq"
+)
+
+// ApplyJQ runs a jq expression on a response and returns its outputs
+// Example:
+//
+//	.results[] | select(.state == "ACTIVE") | {name, project_id}
+func ApplyJQ(expr string, data map[string]interface{}) ([]interface{}, error) {
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq expression: %v", err)
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq expression: %v", err)
+	}
+
+	// gojq accepts the plain JSON types only
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response for jq: %v", err)
+	}
+	var input interface{}
+	if err := json.Unmarshal(jsonBytes, &input); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response for jq: %v", err)
+	}
+
+	var outputs []interface{}
+	iter := code.Run(input)
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := value.(error); ok {
+			return nil, fmt.Errorf("jq: %v", err)
+		}
+		outputs = append(outputs, value)
+	}
+	return outputs, nil
+}
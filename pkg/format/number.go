@@ -0,0 +1,106 @@
+package format
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// sizeFieldPattern matches the last segment of fields holding byte sizes. Counts like page_size,
+// batch_size and size_limit are not matched.
+var sizeFieldPattern = regexp.MustCompile(`(?i)^(size|bytes|memory|disk_size|file_size|volume_size)$|_bytes$`)
+
+// IsSizeField reports whether the field name refers to a byte size
+// Example:
+//
+//	disk_size, data.hardware.memory, total_bytes, data.size_bytes
+func IsSizeField(field string) bool {
+	if i := strings.LastIndex(field, "."); i >= 0 {
+		field = field[i+1:]
+	}
+	return sizeFieldPattern.MatchString(field)
+}
+
+// FormatNumber renders a number with locale-aware thousands separators
+func FormatNumber(v float64) string {
+	printer := message.NewPrinter(detectLocale())
+	if v == math.Trunc(v) && math.Abs(v) < 1e18 {
+		return printer.Sprintf("%d", int64(v))
+	}
+	return printer.Sprintf("%.2f", v)
+}
+
+// FormatBytes renders a byte count using binary units (KiB, MiB, GiB, ...)
+func FormatBytes(v float64) string {
+	const unit = 1024
+	if math.Abs(v) < unit {
+		return fmt.Sprintf("%d B", int64(v))
+	}
+
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	value := v / unit
+	i := 0
+	for math.Abs(value) >= unit && i < len(units)-1 {
+		value /= unit
+		i++
+	}
+
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
+
+// detectLocale returns the language tag from the standard locale environment variables
+func detectLocale() language.Tag {
+	for _, env := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		value := os.Getenv(env)
+		if value == "" || value == "C" || value == "POSIX" {
+			continue
+		}
+
+		// ko_KR.UTF-8 -> ko-KR
+		value = strings.SplitN(value, ".", 2)[0]
+		value = strings.ReplaceAll(value, "_", "-")
+		if tag, err := language.Parse(value); err == nil {
+			return tag
+		}
+	}
+
+	return language.English
+}
+
+// ParseBytes parses a byte size with an optional unit
+// Example:
+//
+//	1048576, 512KB, 64MiB, 1GiB
+func ParseBytes(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	units := []struct {
+		suffix     string
+		multiplier float64
+	}{
+		{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30},
+		{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9},
+		{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30},
+		{"B", 1},
+	}
+
+	multiplier := 1.0
+	for _, unit := range units {
+		if strings.HasSuffix(strings.ToUpper(value), strings.ToUpper(unit.suffix)) {
+			value = strings.TrimSpace(value[:len(value)-len(unit.suffix)])
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size '%s'", value)
+	}
+	return int(n * multiplier), nil
+}
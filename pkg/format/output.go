@@ -8,17 +8,18 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/cloudforet-io/cfctl/pkg/configs"
 	"github.com/spf13/cobra"
 
 	"github.com/pterm/pterm"
 )
 
-// ConvertServiceName converts service name to endpoint format
+// ConvertServiceName converts service name to endpoint format, see configs.LoadServiceNames
 // Example:
 //
 //	cost_analysis -> cost-analysis
 func ConvertServiceName(serviceName string) string {
-	return strings.ReplaceAll(serviceName, "_", "-")
+	return configs.ServiceEndpointName(serviceName)
 }
 
 // SetParentHelp customizes the help output for the parent command
@@ -123,7 +124,7 @@ func RenderTable(data [][]string) {
 	previousService := ""
 
 	// Create table with headers
-	table := pterm.TableData{{"Service", "Verb", "Resource", "Alias"}}
+	table := pterm.TableData{{"Service", "Verb", "Resource", "Alias", "Package"}}
 
 	for _, row := range data {
 		service := row[0]
@@ -142,14 +143,18 @@ func RenderTable(data [][]string) {
 		serviceColored := coloredStyle.Sprint(service)
 		resourceColored := coloredStyle.Sprint(row[2])
 		shortNamesColored := coloredStyle.Sprint(row[3])
+		packageColored := ""
+		if len(row) > 4 {
+			packageColored = coloredStyle.Sprint(row[4])
+		}
 
 		// Split verbs into multiple lines if needed
 		verbs := splitIntoLinesWithComma(row[1], verbColumnWidth)
 		for i, line := range verbs {
 			if i == 0 {
-				table = append(table, []string{serviceColored, coloredStyle.Sprint(line), resourceColored, shortNamesColored})
+				table = append(table, []string{serviceColored, coloredStyle.Sprint(line), resourceColored, shortNamesColored, packageColored})
 			} else {
-				table = append(table, []string{"", coloredStyle.Sprint(line), "", ""})
+				table = append(table, []string{"", coloredStyle.Sprint(line), "", "", ""})
 			}
 		}
 	}
@@ -218,7 +223,7 @@ func PrintNewItems(items []map[string]interface{}) {
 		row := make([]string, len(headers))
 		for i, header := range headers {
 			if val, ok := item[header]; ok {
-				row[i] = formatTableValue(val)
+				row[i] = formatTableValue(header, val)
 			}
 		}
 		tableData = append(tableData, row)
@@ -227,24 +232,12 @@ func PrintNewItems(items []map[string]interface{}) {
 	pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
 }
 
-func formatTableValue(val interface{}) string {
+func formatTableValue(field string, val interface{}) string {
 	switch v := val.(type) {
 	case nil:
 		return ""
 	case string:
-		// Add colors for status values
-		switch strings.ToUpper(v) {
-		case "SUCCESS":
-			return pterm.FgGreen.Sprint(v)
-		case "FAILURE":
-			return pterm.FgRed.Sprint(v)
-		case "PENDING":
-			return pterm.FgYellow.Sprint(v)
-		case "RUNNING":
-			return pterm.FgBlue.Sprint(v)
-		default:
-			return v
-		}
+		return ColorValue(field, v)
 	case float64, float32, int, int32, int64, uint, uint32, uint64:
 		return fmt.Sprintf("%v", v)
 	case bool:
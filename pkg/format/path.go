@@ -0,0 +1,81 @@
+package format
+
+import (
+	"strconv"
+	"strings"
+)
+
+// GetValueByPath looks up a value in nested maps and lists using a dot separated path,
+// list elements are selected with an index
+// Example:
+//
+//	data.os.os_distro -> data["data"]["os"]["os_distro"]
+//	results[0].server_id -> data["results"][0]["server_id"]
+func GetValueByPath(data map[string]interface{}, path string) (interface{}, bool) {
+	// Top-level keys take precedence so that keys containing dots still match
+	if val, ok := data[path]; ok {
+		return val, true
+	}
+
+	var current interface{} = data
+	for _, part := range strings.Split(path, ".") {
+		name, indexes, ok := splitPathIndexes(part)
+		if !ok {
+			return nil, false
+		}
+
+		if name != "" {
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+
+			current, ok = m[name]
+			if !ok {
+				return nil, false
+			}
+		}
+
+		for _, index := range indexes {
+			list, ok := current.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			// Negative indexes count from the end
+			if index < 0 {
+				index += len(list)
+			}
+			if index < 0 || index >= len(list) {
+				return nil, false
+			}
+			current = list[index]
+		}
+	}
+
+	return current, true
+}
+
+// splitPathIndexes splits a path part like results[0][1] into its key and list indexes
+func splitPathIndexes(part string) (string, []int, bool) {
+	open := strings.Index(part, "[")
+	if open < 0 {
+		return part, nil, true
+	}
+
+	name := part[:open]
+	var indexes []int
+	rest := part[open:]
+	for rest != "" {
+		end := strings.Index(rest, "]")
+		if rest[0] != '[' || end < 0 {
+			return "", nil, false
+		}
+		index, err := strconv.Atoi(rest[1:end])
+		if err != nil {
+			return "", nil, false
+		}
+		indexes = append(indexes, index)
+		rest = rest[end+1:]
+	}
+	return name, indexes, true
+}
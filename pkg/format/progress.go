@@ -0,0 +1,128 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pterm/pterm"
+	"golang.org/x/term"
+)
+
+// progressInterval is how often progress lines are written when stderr is not a terminal
+const progressInterval = 5 * time.Second
+
+// Progress reports the progress of operations performing many calls.
+// A progress bar is shown on terminals, otherwise a line is written to stderr periodically.
+// It is safe for concurrent use.
+type Progress struct {
+	mu        sync.Mutex
+	title     string
+	total     int
+	done      int
+	failures  []Failure
+	bar       *pterm.ProgressbarPrinter
+	lastPrint time.Time
+}
+
+// Failure describes an item whose call failed
+type Failure struct {
+	Item  string `json:"item"`
+	Error string `json:"error"`
+}
+
+// ProgressSummary is the machine-readable result of an operation
+type ProgressSummary struct {
+	Total     int       `json:"total"`
+	Succeeded int       `json:"succeeded"`
+	Failed    int       `json:"failed"`
+	Failures  []Failure `json:"failures,omitempty"`
+}
+
+// NewProgress starts reporting the progress of total calls
+func NewProgress(title string, total int) *Progress {
+	p := &Progress{title: title, total: total, lastPrint: time.Now()}
+
+	if total > 1 && term.IsTerminal(int(os.Stderr.Fd())) {
+		bar, err := pterm.DefaultProgressbar.
+			WithTotal(total).
+			WithTitle(title).
+			WithWriter(os.Stderr).
+			WithRemoveWhenDone(true).
+			Start()
+		if err == nil {
+			p.bar = bar
+		}
+	}
+
+	return p
+}
+
+// Succeed records a successful call
+func (p *Progress) Succeed() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.advance()
+}
+
+// Fail records a failed call of the item
+func (p *Progress) Fail(item string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures = append(p.failures, Failure{Item: item, Error: err.Error()})
+	p.advance()
+}
+
+func (p *Progress) advance() {
+	p.done++
+
+	if p.bar != nil {
+		p.bar.Increment()
+		return
+	}
+
+	if time.Since(p.lastPrint) >= progressInterval || p.done == p.total {
+		fmt.Fprintf(os.Stderr, "%s: %d/%d done, %d failed\n", p.title, p.done, p.total, len(p.failures))
+		p.lastPrint = time.Now()
+	}
+}
+
+// Stop ends the progress report and returns the summary of the operation
+func (p *Progress) Stop() *ProgressSummary {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.bar != nil {
+		p.bar.Stop()
+	}
+
+	failures := append([]Failure(nil), p.failures...)
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Item < failures[j].Item
+	})
+
+	return &ProgressSummary{
+		Total:     p.total,
+		Succeeded: p.done - len(p.failures),
+		Failed:    len(p.failures),
+		Failures:  failures,
+	}
+}
+
+// PrintPartialFailure writes the summary as JSON to stderr when some calls failed
+func (s *ProgressSummary) PrintPartialFailure() {
+	if s.Failed == 0 {
+		return
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return
+	}
+
+	pterm.Warning.WithWriter(os.Stderr).Printf("%d of %d calls failed\n", s.Failed, s.Total)
+	fmt.Fprintln(os.Stderr, string(data))
+}
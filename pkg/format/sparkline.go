@@ -0,0 +1,32 @@
+package format
+
+import "math"
+
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a single line bar chart
+// Example:
+//
+//	[1, 5, 3, 8] -> ▁▅▃█
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+
+	line := make([]rune, len(values))
+	for i, v := range values {
+		if max == min {
+			line[i] = sparkTicks[len(sparkTicks)/2]
+			continue
+		}
+		idx := int((v - min) / (max - min) * float64(len(sparkTicks)-1))
+		line[i] = sparkTicks[idx]
+	}
+	return string(line)
+}
@@ -0,0 +1,94 @@
+package format
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// statusFields are the fields used to compute per-status counts of list results
+var statusFields = []string{"status", "state"}
+
+// ListSummary describes the results of a list call
+type ListSummary struct {
+	Shown        int            `json:"shown"`
+	Total        int            `json:"total"`
+	Page         int            `json:"page,omitempty"`
+	PageSize     int            `json:"page_size,omitempty"`
+	StatusField  string         `json:"status_field,omitempty"`
+	StatusCounts map[string]int `json:"status_counts,omitempty"`
+}
+
+// SummarizeResults builds a summary from a list response.
+// Total falls back to the number of results when total_count is missing.
+func SummarizeResults(data map[string]interface{}, results []interface{}) *ListSummary {
+	summary := &ListSummary{
+		Shown: len(results),
+		Total: len(results),
+	}
+
+	if total, ok := parseCount(data["total_count"]); ok {
+		summary.Total = total
+	}
+
+	for _, field := range statusFields {
+		counts := make(map[string]int)
+		for _, result := range results {
+			if row, ok := result.(map[string]interface{}); ok {
+				if value, ok := row[field].(string); ok && value != "" {
+					counts[value]++
+				}
+			}
+		}
+		if len(counts) > 0 {
+			summary.StatusField = field
+			summary.StatusCounts = counts
+			break
+		}
+	}
+
+	return summary
+}
+
+// StatusLine renders the status counts ordered by count
+// Example:
+//
+//	12 RUNNING / 3 FAILURE
+func (s *ListSummary) StatusLine() string {
+	if len(s.StatusCounts) == 0 {
+		return ""
+	}
+
+	statuses := make([]string, 0, len(s.StatusCounts))
+	for status := range s.StatusCounts {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if s.StatusCounts[statuses[i]] != s.StatusCounts[statuses[j]] {
+			return s.StatusCounts[statuses[i]] > s.StatusCounts[statuses[j]]
+		}
+		return statuses[i] < statuses[j]
+	})
+
+	parts := make([]string, len(statuses))
+	for i, status := range statuses {
+		parts[i] = fmt.Sprintf("%d %s", s.StatusCounts[status], status)
+	}
+
+	return strings.Join(parts, " / ")
+}
+
+func parseCount(val interface{}) (int, bool) {
+	switch v := val.(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	case string:
+		// int64 values are encoded as strings in protobuf JSON
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	}
+	return 0, false
+}
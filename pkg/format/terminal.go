@@ -0,0 +1,51 @@
+package format
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/pterm/pterm"
+	"golang.org/x/term"
+)
+
+var (
+	terminalOnce  sync.Once
+	ansiSupported bool
+)
+
+// InitTerminal prepares the console for ANSI escape codes. On Windows, virtual terminal processing
+// is enabled for cmd.exe and PowerShell; where that is not possible, colors are disabled.
+func InitTerminal() {
+	terminalOnce.Do(func() {
+		ansiSupported = enableVirtualTerminal()
+		if !ansiSupported {
+			pterm.DisableColor()
+		}
+	})
+}
+
+// SupportsANSI reports whether the console handles ANSI escape codes such as clearing the screen
+func SupportsANSI() bool {
+	InitTerminal()
+	return ansiSupported
+}
+
+// IsInteractive reports whether stdin and stdout are terminals so that keyboard driven views can run
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// ClearScreen clears the console and moves the cursor to the top left corner
+func ClearScreen() {
+	if SupportsANSI() {
+		fmt.Print("\033[H\033[2J")
+	}
+}
+
+// SetQuiet hides info and success messages so that only the requested output and
+// warnings and errors are printed. The printers only print in pterm debug mode then.
+func SetQuiet() {
+	pterm.Info.Debugger = true
+	pterm.Success.Debugger = true
+}
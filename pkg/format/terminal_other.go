@@ -0,0 +1,8 @@
+//go:build !windows
+
+package format
+
+// enableVirtualTerminal reports that the terminal handles ANSI escape codes, as terminals of Unix do
+func enableVirtualTerminal() bool {
+	return true
+}
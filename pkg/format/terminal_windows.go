@@ -0,0 +1,25 @@
+//go:build windows
+
+package format
+
+import "golang.org/x/sys/windows"
+
+// enableVirtualTerminal turns on the processing of ANSI escape codes of the Windows console.
+// Output which is redirected is not a console and needs no changes.
+func enableVirtualTerminal() bool {
+	supported := true
+	for _, handle := range []windows.Handle{windows.Stdout, windows.Stderr} {
+		var mode uint32
+		if err := windows.GetConsoleMode(handle, &mode); err != nil {
+			continue
+		}
+		if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+			continue
+		}
+		if err := windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+			// Consoles before Windows 10 do not support escape codes
+			supported = false
+		}
+	}
+	return supported
+}
@@ -0,0 +1,116 @@
+package format
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/pterm/pterm"
+)
+
+// themeColors are the color names of theme rules
+var themeColors = map[string]pterm.Color{
+	"black":         pterm.FgBlack,
+	"red":           pterm.FgRed,
+	"green":         pterm.FgGreen,
+	"yellow":        pterm.FgYellow,
+	"blue":          pterm.FgBlue,
+	"magenta":       pterm.FgMagenta,
+	"cyan":          pterm.FgCyan,
+	"white":         pterm.FgWhite,
+	"gray":          pterm.FgGray,
+	"light_red":     pterm.FgLightRed,
+	"light_green":   pterm.FgLightGreen,
+	"light_yellow":  pterm.FgLightYellow,
+	"light_blue":    pterm.FgLightBlue,
+	"light_magenta": pterm.FgLightMagenta,
+	"light_cyan":    pterm.FgLightCyan,
+	"light_white":   pterm.FgLightWhite,
+}
+
+// themePresets color status values of any field. The light preset avoids yellow,
+// which is hard to read on light backgrounds.
+var themePresets = map[string]map[string]pterm.Color{
+	"dark": {
+		"SUCCESS": pterm.FgGreen,
+		"FAILURE": pterm.FgRed,
+		"PENDING": pterm.FgYellow,
+		"RUNNING": pterm.FgBlue,
+	},
+	"light": {
+		"SUCCESS": pterm.FgGreen,
+		"FAILURE": pterm.FgRed,
+		"PENDING": pterm.FgMagenta,
+		"RUNNING": pterm.FgBlue,
+	},
+	"none": {},
+}
+
+// themeRule is a theme rule of the setting file ready to be matched
+type themeRule struct {
+	field string
+	value string
+	match *regexp.Regexp
+	color pterm.Color
+}
+
+var (
+	themeOnce   sync.Once
+	themeRules  []themeRule
+	themePreset map[string]pterm.Color
+)
+
+// loadTheme prepares the theme of the setting file once. Rules with unknown colors
+// or invalid regular expressions are skipped.
+func loadTheme() {
+	themeOnce.Do(func() {
+		theme := configs.LoadTheme()
+
+		preset, ok := themePresets[strings.ToLower(theme.Preset)]
+		if !ok {
+			preset = themePresets["dark"]
+		}
+		themePreset = preset
+
+		for _, rule := range theme.Rules {
+			color, ok := themeColors[strings.ToLower(rule.Color)]
+			if !ok {
+				continue
+			}
+			compiled := themeRule{field: rule.Field, value: rule.Value, color: color}
+			if rule.Value == "" {
+				if rule.Match == "" {
+					continue
+				}
+				re, err := regexp.Compile(rule.Match)
+				if err != nil {
+					continue
+				}
+				compiled.match = re
+			}
+			themeRules = append(themeRules, compiled)
+		}
+	})
+}
+
+// ColorValue colors a table value of a field by the first matching rule of the theme,
+// or by the status colors of the theme preset
+func ColorValue(field, value string) string {
+	loadTheme()
+
+	for _, rule := range themeRules {
+		if rule.field != "" && rule.field != field {
+			continue
+		}
+		if (rule.match != nil && rule.match.MatchString(value)) ||
+			(rule.match == nil && strings.EqualFold(rule.value, value)) {
+			return rule.color.Sprint(value)
+		}
+	}
+
+	if color, ok := themePreset[strings.ToUpper(value)]; ok {
+		return color.Sprint(value)
+	}
+	return value
+}
@@ -0,0 +1,131 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseTimestamp detects timestamp values in a response and converts them to time.Time.
+// Supported formats are RFC3339 strings and {seconds, nanos} objects.
+func ParseTimestamp(val interface{}) (time.Time, bool) {
+	switch v := val.(type) {
+	case string:
+		if len(v) < len("2006-01-02T15:04:05") {
+			return time.Time{}, false
+		}
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t, true
+		}
+		// Some services omit the timezone designator
+		if t, err := time.Parse("2006-01-02T15:04:05", v); err == nil {
+			return t.UTC(), true
+		}
+	case map[string]interface{}:
+		for key := range v {
+			if key != "seconds" && key != "nanos" {
+				return time.Time{}, false
+			}
+		}
+		seconds, ok := toInt64(v["seconds"])
+		if !ok {
+			return time.Time{}, false
+		}
+		nanos, _ := toInt64(v["nanos"])
+		return time.Unix(seconds, nanos), true
+	}
+
+	return time.Time{}, false
+}
+
+// FormatRelativeTime renders a time relative to now
+// Example:
+//
+//	3h ago, 2d ago, in 5m
+func FormatRelativeTime(t time.Time) string {
+	diff := time.Since(t)
+	suffix := "ago"
+	if diff < 0 {
+		diff = -diff
+		suffix = ""
+	}
+
+	var value string
+	switch {
+	case diff < time.Minute:
+		value = fmt.Sprintf("%ds", int(diff.Seconds()))
+	case diff < time.Hour:
+		value = fmt.Sprintf("%dm", int(diff.Minutes()))
+	case diff < 24*time.Hour:
+		value = fmt.Sprintf("%dh", int(diff.Hours()))
+	case diff < 365*24*time.Hour:
+		value = fmt.Sprintf("%dd", int(diff.Hours()/24))
+	default:
+		value = fmt.Sprintf("%dy", int(diff.Hours()/(24*365)))
+	}
+
+	if suffix == "" {
+		return "in " + value
+	}
+	return value + " " + suffix
+}
+
+// HumanizeTime returns the relative representation of a timestamp value
+func HumanizeTime(val interface{}) (string, bool) {
+	t, ok := ParseTimestamp(val)
+	if !ok {
+		return "", false
+	}
+	return FormatRelativeTime(t), true
+}
+
+func toInt64(val interface{}) (int64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case string:
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// ParseTimeArg parses a time argument given either as RFC3339 or as a duration relative to now
+// Example:
+//
+//	-1h, 30m (both mean 30 minutes ago), now, 2024-01-02T15:04:05Z
+func ParseTimeArg(value string, now time.Time) (time.Time, error) {
+	if value == "" || value == "now" {
+		return now, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	d, err := parseDayDuration(strings.TrimPrefix(value, "-"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time '%s'. Use a duration like -1h or an RFC3339 timestamp", value)
+	}
+	return now.Add(-d), nil
+}
+
+// parseDayDuration parses a duration which may use a day unit (e.g. 7d)
+func parseDayDuration(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
@@ -2,21 +2,16 @@ package format
 
 import (
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
 	"github.com/spf13/viper"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
-	"google.golang.org/protobuf/proto"
-	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 // ValidateServiceCommand checks if the given verb and resource are valid for the service
@@ -66,7 +61,8 @@ func ValidateServiceCommand(service, verb, resourceName string) error {
 
 	// Fetch service resources
 	resources, err := FetchServiceResources(service, serviceEndpoint, nil)
-	if err != nil {
+	var unresolved *UnresolvedServicesError
+	if err != nil && !errors.As(err, &unresolved) {
 		return fmt.Errorf("failed to fetch service resources: %v", err)
 	}
 
@@ -99,53 +95,49 @@ func ValidateServiceCommand(service, verb, resourceName string) error {
 	return nil
 }
 
-func FetchServiceResources(service, endpoint string, shortNamesMap map[string]string) ([][]string, error) {
-	parts := strings.Split(endpoint, "://")
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid endpoint format: %s", endpoint)
-	}
-
-	scheme := parts[0]
-	hostPort := strings.SplitN(parts[1], "/", 2)[0]
+// UnresolvedServicesError lists the services whose descriptors could not be resolved.
+// FetchServiceResources returns it with the resources of the other services.
+type UnresolvedServicesError struct {
+	Errors map[string]error // Errors by service name
+}
 
-	var opts []grpc.DialOption
-	if scheme == "grpc+ssl" {
-		tlsConfig := &tls.Config{
-			InsecureSkipVerify: false,
-		}
-		creds := credentials.NewTLS(tlsConfig)
-		opts = append(opts, grpc.WithTransportCredentials(creds))
-	} else {
-		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func (e *UnresolvedServicesError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	conn, err := grpc.Dial(hostPort, opts...)
-	if err != nil {
-		return nil, fmt.Errorf("connection failed: unable to connect to %s: %v", endpoint, err)
+	messages := make([]string, len(names))
+	for i, name := range names {
+		messages[i] = fmt.Sprintf("%s: %v", name, e.Errors[name])
 	}
-	defer conn.Close()
+	return "failed to resolve services: " + strings.Join(messages, "; ")
+}
 
-	client := grpc_reflection_v1alpha.NewServerReflectionClient(conn)
-	stream, err := client.ServerReflectionInfo(context.Background())
+// FetchServiceResources lists the resources and verbs of a service by server reflection.
+// Services which cannot be resolved are skipped and reported as *UnresolvedServicesError
+// together with the resources of the other services.
+func FetchServiceResources(service, endpoint string, shortNamesMap map[string]string) ([][]string, error) {
+	target, err := endpoints.ParseTarget(endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create reflection client: %v", err)
+		return nil, err
 	}
 
-	req := &grpc_reflection_v1alpha.ServerReflectionRequest{
-		MessageRequest: &grpc_reflection_v1alpha.ServerReflectionRequest_ListServices{ListServices: ""},
+	conn, err := configs.Dial(target.HostPort, target.Credentials())
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: unable to connect to %s: %v", endpoint, err)
 	}
+	defer conn.Close()
 
-	if err := stream.Send(req); err != nil {
-		return nil, fmt.Errorf("failed to send reflection request: %v", err)
-	}
+	refClient := configs.NewReflectionClient(context.Background(), conn)
+	defer refClient.Reset()
 
-	resp, err := stream.Recv()
+	services, err := refClient.ListServices()
 	if err != nil {
-		return nil, fmt.Errorf("failed to receive reflection response: %v", err)
+		return nil, fmt.Errorf("failed to list services: %v", err)
 	}
 
-	services := resp.GetListServicesResponse().Service
-
 	// Load aliases
 	aliases, err := configs.LoadAliases()
 	if err != nil {
@@ -153,12 +145,22 @@ func FetchServiceResources(service, endpoint string, shortNamesMap map[string]st
 	}
 
 	data := [][]string{}
+	unresolved := make(map[string]error)
 	for _, s := range services {
-		if strings.HasPrefix(s.Name, "grpc.reflection.v1alpha.") {
+		if strings.HasPrefix(s, "grpc.reflection.") {
 			continue
 		}
-		resourceName := s.Name[strings.LastIndex(s.Name, ".")+1:]
-		verbs := getServiceMethods(client, s.Name)
+		resourceName := s[strings.LastIndex(s, ".")+1:]
+
+		serviceDesc, err := refClient.ResolveService(s)
+		if err != nil {
+			unresolved[s] = err
+			continue
+		}
+		verbs := []string{}
+		for _, method := range serviceDesc.GetMethods() {
+			verbs = append(verbs, method.GetName())
+		}
 
 		// Group verbs by alias
 		verbsWithAlias := make(map[string]string)
@@ -196,47 +198,8 @@ func FetchServiceResources(service, endpoint string, shortNamesMap map[string]st
 		}
 	}
 
-	return data, nil
-}
-
-func getServiceMethods(client grpc_reflection_v1alpha.ServerReflectionClient, serviceName string) []string {
-	stream, err := client.ServerReflectionInfo(context.Background())
-	if err != nil {
-		log.Fatalf("Failed to create reflection client: %v", err)
-	}
-
-	req := &grpc_reflection_v1alpha.ServerReflectionRequest{
-		MessageRequest: &grpc_reflection_v1alpha.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: serviceName},
-	}
-
-	if err := stream.Send(req); err != nil {
-		log.Fatalf("Failed to send reflection request: %v", err)
-	}
-
-	resp, err := stream.Recv()
-	if err != nil {
-		log.Fatalf("Failed to receive reflection response: %v", err)
+	if len(unresolved) > 0 {
+		return data, &UnresolvedServicesError{Errors: unresolved}
 	}
-
-	fileDescriptor := resp.GetFileDescriptorResponse()
-	if fileDescriptor == nil {
-		return []string{}
-	}
-
-	methods := []string{}
-	for _, fdBytes := range fileDescriptor.FileDescriptorProto {
-		fd := &descriptorpb.FileDescriptorProto{}
-		if err := proto.Unmarshal(fdBytes, fd); err != nil {
-			log.Fatalf("Failed to unmarshal file descriptor: %v", err)
-		}
-		for _, service := range fd.GetService() {
-			if service.GetName() == serviceName[strings.LastIndex(serviceName, ".")+1:] {
-				for _, method := range service.GetMethod() {
-					methods = append(methods, method.GetName())
-				}
-			}
-		}
-	}
-
-	return methods
+	return data, nil
 }
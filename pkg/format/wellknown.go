@@ -0,0 +1,120 @@
+package format
+
+import "time"
+
+// structValueKinds are the JSON keys of the kinds of a google.protobuf.Value
+var structValueKinds = map[string]bool{
+	"nullValue":   true,
+	"numberValue": true,
+	"stringValue": true,
+	"boolValue":   true,
+	"structValue": true,
+	"listValue":   true,
+}
+
+// NormalizeWellKnownTypes converts well-known types that were rendered as raw messages into natural JSON.
+// google.protobuf.Struct values ({"fields": {"key": {"stringValue": "..."}}}) become plain objects
+// and Timestamps ({"seconds": ..., "nanos": ...}) become RFC3339 strings.
+func NormalizeWellKnownTypes(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		if fields, ok := structFields(v); ok {
+			normalized := make(map[string]interface{}, len(fields))
+			for key, field := range fields {
+				normalized[key] = normalizeStructValue(field.(map[string]interface{}))
+			}
+			return normalized
+		}
+		if t, ok := timestampValue(v); ok {
+			return t.UTC().Format(time.RFC3339Nano)
+		}
+
+		for key, item := range v {
+			v[key] = NormalizeWellKnownTypes(item)
+		}
+		return v
+
+	case []interface{}:
+		for i, item := range v {
+			v[i] = NormalizeWellKnownTypes(item)
+		}
+		return v
+	}
+
+	return val
+}
+
+// structFields returns the fields of an object rendered as a raw google.protobuf.Struct
+func structFields(v map[string]interface{}) (map[string]interface{}, bool) {
+	if len(v) != 1 {
+		return nil, false
+	}
+	fields, ok := v["fields"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	for _, field := range fields {
+		value, ok := field.(map[string]interface{})
+		if !ok || !isStructValue(value) {
+			return nil, false
+		}
+	}
+
+	return fields, true
+}
+
+// isStructValue reports whether an object is a raw google.protobuf.Value
+func isStructValue(v map[string]interface{}) bool {
+	if len(v) != 1 {
+		return false
+	}
+	for key := range v {
+		return structValueKinds[key]
+	}
+	return false
+}
+
+// normalizeStructValue converts a raw google.protobuf.Value into its plain value
+func normalizeStructValue(v map[string]interface{}) interface{} {
+	for kind, value := range v {
+		switch kind {
+		case "nullValue":
+			return nil
+		case "structValue":
+			if s, ok := value.(map[string]interface{}); ok {
+				if len(s) == 0 {
+					return map[string]interface{}{}
+				}
+				return NormalizeWellKnownTypes(s)
+			}
+		case "listValue":
+			list, _ := value.(map[string]interface{})
+			values, _ := list["values"].([]interface{})
+			normalized := make([]interface{}, 0, len(values))
+			for _, item := range values {
+				if itemValue, ok := item.(map[string]interface{}); ok && isStructValue(itemValue) {
+					normalized = append(normalized, normalizeStructValue(itemValue))
+				} else {
+					normalized = append(normalized, NormalizeWellKnownTypes(item))
+				}
+			}
+			return normalized
+		default:
+			return value
+		}
+	}
+	return nil
+}
+
+// timestampValue converts an object rendered as a raw google.protobuf.Timestamp.
+// Both seconds and nanos must be present so that other objects with a seconds field are kept.
+func timestampValue(v map[string]interface{}) (time.Time, bool) {
+	if len(v) != 2 {
+		return time.Time{}, false
+	}
+	if _, ok := v["nanos"]; !ok {
+		return time.Time{}, false
+	}
+	return ParseTimestamp(v)
+}
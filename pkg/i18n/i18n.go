@@ -0,0 +1,62 @@
+// Package i18n translates the guidance messages of cfctl (login instructions, error boxes and prompts).
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+)
+
+// defaultLanguage is used for languages without translations and messages missing in a translation
+const defaultLanguage = "en"
+
+var (
+	languageOnce sync.Once
+	language     string
+)
+
+// Language returns the language of messages: the language of the setting file,
+// otherwise the language of LC_ALL, LC_MESSAGES or LANG (e.g. ko_KR.UTF-8 -> ko)
+func Language() string {
+	languageOnce.Do(func() {
+		language = detectLanguage()
+	})
+	return language
+}
+
+func detectLanguage() string {
+	candidates := []string{configs.LoadLanguage(), os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")}
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		code := strings.ToLower(candidate)
+		if i := strings.IndexAny(code, "_.-@"); i >= 0 {
+			code = code[:i]
+		}
+		if _, ok := messages[code]; ok {
+			return code
+		}
+		// The first language set is used even without translations
+		return defaultLanguage
+	}
+	return defaultLanguage
+}
+
+// T returns the message of a key in the current language formatted with the arguments
+func T(key string, args ...interface{}) string {
+	message, ok := messages[Language()][key]
+	if !ok {
+		message, ok = messages[defaultLanguage][key]
+	}
+	if !ok {
+		message = key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
@@ -0,0 +1,153 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// messages are the translations of the guidance messages by language
+var messages = map[string]map[string]string{
+	"en": {
+		// Prompts
+		"prompt.token":    "Enter your token:",
+		"prompt.user_id":  "Enter your User ID",
+		"prompt.password": "Enter your password",
+		"login.as":        "Logging in as: %s",
+
+		// Authentication
+		"auth.required":       "Authentication required.",
+		"auth.login_first":    "To see Available Commands, please authenticate first:",
+		"auth_required.title": "Authentication Required",
+		"auth_required.explain": "Please login to SpaceONE Console first.\n" +
+			"This requires your SpaceONE credentials.",
+		"auth_error.title": "Authentication Error",
+		"auth_error.explain": "Your authentication token has expired or is invalid.\n" +
+			"Please login again to refresh your credentials.",
+		"required_steps.title":    "Required Steps",
+		"steps.run_login":         "Run 'cfctl login'",
+		"steps.enter_credentials": "Enter your credentials when prompted",
+		"steps.select_scope":      "Select your scope",
+		"steps.retry":             "Try your command again",
+
+		// App tokens
+		"app_guide.title":          "App Guide",
+		"app_token_required.title": "App Token Required",
+		"app_token.explain": "Please create a Domain Admin App in SpaceONE Console.\n" +
+			"This requires Domain Admin privilege.\n\n" +
+			"Or Please create a Workspace App in SpaceONE Console.\n" +
+			"This requires Workspace Owner privilege.",
+		"config_example.title":     "Config Example",
+		"steps.go_console":         "Go to SpaceONE Console",
+		"steps.navigate_app":       "Navigate to either 'Admin > App Page' or specific 'Workspace > App page'",
+		"steps.create_app":         "Click 'Create' to create your App",
+		"steps.copy_secret":        "Copy value of either 'client_secret' from Client ID or 'token' from Spacectl (CLI)",
+		"steps.copy_token":         "Copy the generated App Token",
+		"steps.add_proxy_token":    "Add the token under the proxy in your config file:\n%s",
+		"steps.update_token":       "Update token in your config file:\n   Path: ~/.cfctl/setting.yaml\n   Environment: %s",
+		"steps.login_again":        "Run 'cfctl login' again",
+		"token_not_found.title":    "Token Not Found",
+		"token_not_found.body":     "Please follow the instructions below to obtain an App Token.",
+		"token_not_found.retry":    "After updating the token, please try your command again.",
+		"setup_instructions.title": "Setup Instructions",
+		"setup_instructions.body": `Please follow these steps to obtain an App Token:
+
+1. Visit %s
+2. Go to Admin page or Workspace page
+3. Navigate to the App page
+4. Click [Create] button
+5. Copy the generated App Token
+6. Update your settings:
+     Path: %s
+     Environment: %s
+     Field: "token"`,
+		"app_env.title":           "App Environment Detected",
+		"app_env.body":            "Login command is not available for app environments.\nPlease use the app token directly in your configuration file.",
+		"expired_app_token.title": "Expired App Token",
+		"expired_app_token.body":  "Your App token has expired.\nPlease generate a new App and update your config file.",
+		"invalid_app_token.title": "Invalid App Token",
+		"invalid_app_token.body":  "App token must have either DOMAIN_ADMIN or WORKSPACE_OWNER role.\nPlease generate a new App with appropriate permissions and update your config file.",
+
+		// Endpoints
+		"local_grpc.title":     "Local gRPC Server Not Found",
+		"local_grpc.body":      "Current environment: %s\nUnable to connect to local gRPC server.\nPlease make sure your gRPC server is running on %s",
+		"proxy_required.title": "Proxy Mode Required",
+		"proxy_required.body": "Current endpoint is not configured for identity service.\n" +
+			"Please enable proxy mode and set identity endpoint first.",
+	},
+	"ko": {
+		// Prompts
+		"prompt.token":    "토큰을 입력하세요:",
+		"prompt.user_id":  "사용자 ID를 입력하세요",
+		"prompt.password": "비밀번호를 입력하세요",
+		"login.as":        "%s(으)로 로그인합니다",
+
+		// Authentication
+		"auth.required":       "인증이 필요합니다.",
+		"auth.login_first":    "사용 가능한 명령어를 보려면 먼저 인증하세요:",
+		"auth_required.title": "인증 필요",
+		"auth_required.explain": "먼저 SpaceONE 콘솔에 로그인하세요.\n" +
+			"SpaceONE 계정 정보가 필요합니다.",
+		"auth_error.title": "인증 오류",
+		"auth_error.explain": "인증 토큰이 만료되었거나 유효하지 않습니다.\n" +
+			"다시 로그인하여 인증 정보를 갱신하세요.",
+		"required_steps.title":    "진행 단계",
+		"steps.run_login":         "'cfctl login'을 실행하세요",
+		"steps.enter_credentials": "안내에 따라 계정 정보를 입력하세요",
+		"steps.select_scope":      "사용할 범위(scope)를 선택하세요",
+		"steps.retry":             "명령어를 다시 실행하세요",
+
+		// App tokens
+		"app_guide.title":          "앱 안내",
+		"app_token_required.title": "앱 토큰 필요",
+		"app_token.explain": "SpaceONE 콘솔에서 도메인 관리자 앱을 생성하세요.\n" +
+			"도메인 관리자 권한이 필요합니다.\n\n" +
+			"또는 SpaceONE 콘솔에서 워크스페이스 앱을 생성하세요.\n" +
+			"워크스페이스 소유자 권한이 필요합니다.",
+		"config_example.title":     "설정 예시",
+		"steps.go_console":         "SpaceONE 콘솔로 이동하세요",
+		"steps.navigate_app":       "'관리자 > 앱' 페이지 또는 특정 '워크스페이스 > 앱' 페이지로 이동하세요",
+		"steps.create_app":         "'생성'을 눌러 앱을 만드세요",
+		"steps.copy_secret":        "Client ID의 'client_secret' 또는 Spacectl (CLI)의 'token' 값을 복사하세요",
+		"steps.copy_token":         "생성된 앱 토큰을 복사하세요",
+		"steps.add_proxy_token":    "설정 파일의 proxy 아래에 토큰을 추가하세요:\n%s",
+		"steps.update_token":       "설정 파일의 토큰을 변경하세요:\n   경로: ~/.cfctl/setting.yaml\n   환경: %s",
+		"steps.login_again":        "'cfctl login'을 다시 실행하세요",
+		"token_not_found.title":    "토큰 없음",
+		"token_not_found.body":     "아래 안내에 따라 앱 토큰을 발급받으세요.",
+		"token_not_found.retry":    "토큰을 변경한 뒤 명령어를 다시 실행하세요.",
+		"setup_instructions.title": "설정 방법",
+		"setup_instructions.body": `다음 단계에 따라 앱 토큰을 발급받으세요:
+
+1. %s 에 접속하세요
+2. 관리자 페이지 또는 워크스페이스 페이지로 이동하세요
+3. 앱 페이지로 이동하세요
+4. [생성] 버튼을 누르세요
+5. 생성된 앱 토큰을 복사하세요
+6. 설정을 변경하세요:
+     경로: %s
+     환경: %s
+     필드: "token"`,
+		"app_env.title":           "앱 환경 감지됨",
+		"app_env.body":            "앱 환경에서는 login 명령어를 사용할 수 없습니다.\n설정 파일에 앱 토큰을 직접 입력하세요.",
+		"expired_app_token.title": "앱 토큰 만료",
+		"expired_app_token.body":  "앱 토큰이 만료되었습니다.\n새 앱을 생성하고 설정 파일을 변경하세요.",
+		"invalid_app_token.title": "유효하지 않은 앱 토큰",
+		"invalid_app_token.body":  "앱 토큰에는 DOMAIN_ADMIN 또는 WORKSPACE_OWNER 역할이 필요합니다.\n적절한 권한으로 새 앱을 생성하고 설정 파일을 변경하세요.",
+
+		// Endpoints
+		"local_grpc.title":     "로컬 gRPC 서버를 찾을 수 없음",
+		"local_grpc.body":      "현재 환경: %s\n로컬 gRPC 서버에 연결할 수 없습니다.\ngRPC 서버가 %s 에서 실행 중인지 확인하세요",
+		"proxy_required.title": "프록시 모드 필요",
+		"proxy_required.body": "현재 엔드포인트는 identity 서비스용으로 설정되어 있지 않습니다.\n" +
+			"먼저 프록시 모드를 켜고 identity 엔드포인트를 설정하세요.",
+	},
+}
+
+// Steps numbers the translated steps and separates them with blank lines for instruction boxes
+func Steps(steps ...string) string {
+	numbered := make([]string, len(steps))
+	for i, step := range steps {
+		numbered[i] = fmt.Sprintf("%d. %s", i+1, step)
+	}
+	return strings.Join(numbered, "\n\n")
+}
@@ -0,0 +1,101 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+
+	"github.com/pterm/pterm"
+)
+
+// adminModeName is the environment mode which calls APIs with a domain scope token
+const adminModeName = "admin"
+
+// isAdminMode reports whether the call is made in admin mode by flag or environment config
+func isAdminMode(config *Config, options *FetchOptions) bool {
+	return options.Admin || config.Environments[config.Environment].Mode == adminModeName
+}
+
+// adminToken returns a domain scope token of the current user.
+// User environments grant one with the cached refresh token, app tokens are used as is.
+func adminToken(config *Config, resolver *endpoints.Resolver) (string, error) {
+	token := config.Environments[config.Environment].Token
+	claims, err := DecodeTokenClaims(token)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasSuffix(config.Environment, "-user") {
+		if wid, _ := claims["wid"].(string); wid != "" {
+			pterm.Warning.Println("The app token is workspace scoped, admin mode requires a Domain Admin App.")
+		}
+		return token, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %v", err)
+	}
+	cacheDir := filepath.Join(home, ".cfctl", "cache", config.Environment)
+
+	// Reuse the cached admin token while it is valid
+	if data, err := os.ReadFile(filepath.Join(cacheDir, "admin_access_token")); err == nil {
+		cached := strings.TrimSpace(string(data))
+		if cachedClaims, err := DecodeTokenClaims(cached); err == nil {
+			if exp, ok := cachedClaims["exp"].(float64); ok && time.Now().Unix() < int64(exp)-60 {
+				return cached, nil
+			}
+		}
+	}
+
+	refreshToken, err := os.ReadFile(filepath.Join(cacheDir, "refresh_token"))
+	if err != nil {
+		return "", fmt.Errorf("no refresh token found. Please run 'cfctl login' first")
+	}
+
+	domainID, _ := claims["did"].(string)
+	accessToken, err := grantScopedToken(config, resolver, strings.TrimSpace(string(refreshToken)), "DOMAIN", domainID, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to grant admin token (requires Domain Admin role): %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(cacheDir, "admin_access_token"), []byte(accessToken), 0600); err != nil {
+		pterm.Warning.Printf("Failed to cache admin token: %v\n", err)
+	}
+
+	return accessToken, nil
+}
+
+// grantScopedToken grants an access token of the scope with a refresh token
+func grantScopedToken(config *Config, resolver *endpoints.Resolver, refreshToken, scope, domainID, workspaceID string) (string, error) {
+	grant := map[string]interface{}{
+		"grant_type": "REFRESH_TOKEN",
+		"token":      refreshToken,
+		"scope":      scope,
+		"domain_id":  domainID,
+		"timeout":    10800,
+	}
+	if workspaceID != "" {
+		grant["workspace_id"] = workspaceID
+	}
+	params, err := json.Marshal(grant)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal grant request: %v", err)
+	}
+
+	resp, err := fetchResponse(config, "identity", "grant", "Token", &FetchOptions{JSONParameter: string(params)}, resolver)
+	if err != nil {
+		return "", err
+	}
+
+	accessToken, ok := resp["access_token"].(string)
+	if !ok {
+		return "", fmt.Errorf("access token not found in grant response")
+	}
+	return accessToken, nil
+}
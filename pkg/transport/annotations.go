@@ -0,0 +1,98 @@
+package transport
+
+import (
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// fieldBehaviorExtension is the field number of the google.api.field_behavior option
+const fieldBehaviorExtension = 1052
+
+// Values of the google.api.FieldBehavior enum used to pick minimal columns and writable fields
+const (
+	fieldBehaviorRequired   = 2
+	fieldBehaviorOutputOnly = 3
+	fieldBehaviorImmutable  = 5
+	fieldBehaviorIdentifier = 8
+)
+
+// fieldBehaviors returns the google.api.field_behavior annotations of a field.
+// The extension is not registered in cfctl, so it is read from the unknown fields of the options.
+// Example:
+//
+//	string cloud_service_id = 1 [(google.api.field_behavior) = IDENTIFIER];
+func fieldBehaviors(field *desc.FieldDescriptor) []int {
+	opts := field.GetFieldOptions()
+	if opts == nil {
+		return nil
+	}
+
+	var behaviors []int
+	unknown := opts.ProtoReflect().GetUnknown()
+	for len(unknown) > 0 {
+		num, typ, n := protowire.ConsumeTag(unknown)
+		if n < 0 {
+			return behaviors
+		}
+		unknown = unknown[n:]
+
+		switch {
+		case num == fieldBehaviorExtension && typ == protowire.VarintType:
+			value, n := protowire.ConsumeVarint(unknown)
+			if n < 0 {
+				return behaviors
+			}
+			behaviors = append(behaviors, int(value))
+			unknown = unknown[n:]
+
+		case num == fieldBehaviorExtension && typ == protowire.BytesType:
+			// Packed repeated enum
+			packed, n := protowire.ConsumeBytes(unknown)
+			if n < 0 {
+				return behaviors
+			}
+			for len(packed) > 0 {
+				value, m := protowire.ConsumeVarint(packed)
+				if m < 0 {
+					break
+				}
+				behaviors = append(behaviors, int(value))
+				packed = packed[m:]
+			}
+			unknown = unknown[n:]
+
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, unknown)
+			if n < 0 {
+				return behaviors
+			}
+			unknown = unknown[n:]
+		}
+	}
+
+	return behaviors
+}
+
+// annotatedMinimalFields returns the identifier and required fields of a message
+// when its fields carry google.api.field_behavior annotations.
+// The second return value is false when none of the fields are annotated.
+func annotatedMinimalFields(msgDesc *desc.MessageDescriptor) ([]string, bool) {
+	annotated := false
+	var fields []string
+
+	for _, field := range msgDesc.GetFields() {
+		behaviors := fieldBehaviors(field)
+		if len(behaviors) > 0 {
+			annotated = true
+		}
+
+		for _, behavior := range behaviors {
+			if behavior == fieldBehaviorIdentifier || behavior == fieldBehaviorRequired {
+				fields = append(fields, field.GetName())
+				break
+			}
+		}
+	}
+
+	return fields, annotated && len(fields) > 0
+}
@@ -0,0 +1,202 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+	"github.com/jhump/protoreflect/dynamic"
+	"google.golang.org/grpc/status"
+)
+
+// BenchmarkResult holds the latency and error statistics of a benchmark run
+type BenchmarkResult struct {
+	Method      string         `json:"method"`
+	Requests    int            `json:"requests"`
+	Concurrency int            `json:"concurrency"`
+	Errors      int            `json:"errors"`
+	ErrorRate   float64        `json:"error_rate"`
+	Duration    time.Duration  `json:"duration"`
+	Throughput  float64        `json:"throughput"` // Requests per second
+	Min         time.Duration  `json:"min"`
+	P50         time.Duration  `json:"p50"`
+	P90         time.Duration  `json:"p90"`
+	P95         time.Duration  `json:"p95"`
+	P99         time.Duration  `json:"p99"`
+	Max         time.Duration  `json:"max"`
+	ErrorCodes  map[string]int `json:"error_codes,omitempty"` // Number of errors by gRPC status code
+}
+
+// Benchmark calls a unary method the given number of times with a bounded number of concurrent
+// callers and measures the latency of each call.
+// The method is resolved and the connection is established once before the run,
+// so that only the calls themselves are measured.
+func Benchmark(serviceName, verb, resourceName string, options *FetchOptions, requests, concurrency int) (*BenchmarkResult, error) {
+	if requests <= 0 {
+		return nil, fmt.Errorf("number of requests must be positive")
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %v", err)
+	}
+	if config.Environments[config.Environment].Token == "" {
+		return nil, fmt.Errorf("no token found for authentication. Please run 'cfctl login' first")
+	}
+
+	resolver, err := endpoints.NewResolver(config.Environment, config.Environments[config.Environment].Endpoint, config.Environments[config.Environment].Endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAdminMode(config, options) {
+		adminTokenValue, err := adminToken(config, resolver)
+		if err != nil {
+			return nil, err
+		}
+		env := config.Environments[config.Environment]
+		env.Token = adminTokenValue
+		config.Environments[config.Environment] = env
+	}
+
+	target, err := resolver.Resolve(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer shareConnections()()
+	conn, closeConn, err := dialTarget(target)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: unable to connect to %s: %v", target.HostPort, err)
+	}
+	defer closeConn()
+
+	callOptions, err := defaultCallOptions(config, options)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err := outgoingContext(config, options)
+	if err != nil {
+		return nil, err
+	}
+
+	refClient, err := newDescriptorSource(ctx, conn, config)
+	if err != nil {
+		return nil, err
+	}
+	defer refClient.Reset()
+
+	resourceName = normalizeResourceName(refClient, serviceName, resourceName)
+	fullServiceName, err := discoverService(refClient, serviceName, resourceName, options.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover service: %v", err)
+	}
+	serviceDesc, err := refClient.ResolveService(fullServiceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve service %s: %v", fullServiceName, err)
+	}
+	methodDesc := serviceDesc.FindMethodByName(verb)
+	if methodDesc == nil {
+		return nil, fmt.Errorf("%w: %s%s", errMethodNotFound, verb, notFoundHint(verb, methodNames(serviceDesc), "verbs"))
+	}
+	if methodDesc.IsClientStreaming() || methodDesc.IsServerStreaming() {
+		return nil, fmt.Errorf("benchmark supports unary methods only, %s is a streaming method", verb)
+	}
+
+	inputParams, err := parseParameters(options)
+	if err != nil {
+		return nil, err
+	}
+	if err := normalizeEnumParams(methodDesc.GetInputType(), inputParams); err != nil {
+		return nil, err
+	}
+	requestJSON, err := json.Marshal(inputParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal input parameters to JSON: %v", err)
+	}
+	if err := dynamic.NewMessage(methodDesc.GetInputType()).UnmarshalJSON(requestJSON); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON into request message: %v", err)
+	}
+
+	fullMethod := fmt.Sprintf("/%s/%s", fullServiceName, verb)
+	latencies := make([]time.Duration, 0, requests)
+	errorCodes := make(map[string]int)
+	var mu sync.Mutex
+
+	calls := make(chan struct{})
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// Each caller owns its messages since dynamic messages are not safe for concurrent use
+			reqMsg := dynamic.NewMessage(methodDesc.GetInputType())
+			_ = reqMsg.UnmarshalJSON(requestJSON)
+
+			for range calls {
+				respMsg := dynamic.NewMessage(methodDesc.GetOutputType())
+				callStart := time.Now()
+				err := conn.Invoke(ctx, fullMethod, reqMsg, respMsg, callOptions...)
+				latency := time.Since(callStart)
+
+				mu.Lock()
+				if err != nil {
+					errorCodes[status.Code(err).String()]++
+				} else {
+					latencies = append(latencies, latency)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := 0; i < requests; i++ {
+		calls <- struct{}{}
+	}
+	close(calls)
+	wg.Wait()
+
+	duration := time.Since(start)
+	errors := requests - len(latencies)
+
+	result := &BenchmarkResult{
+		Method:      fullMethod,
+		Requests:    requests,
+		Concurrency: concurrency,
+		Errors:      errors,
+		ErrorRate:   float64(errors) / float64(requests),
+		Duration:    duration,
+		Throughput:  float64(requests) / duration.Seconds(),
+		ErrorCodes:  errorCodes,
+	}
+
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		result.Min = latencies[0]
+		result.P50 = percentile(latencies, 50)
+		result.P90 = percentile(latencies, 90)
+		result.P95 = percentile(latencies, 95)
+		result.P99 = percentile(latencies, 99)
+		result.Max = latencies[len(latencies)-1]
+	}
+
+	return result, nil
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
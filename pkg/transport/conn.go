@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"sync"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+	"google.golang.org/grpc"
+)
+
+// sharedConn is a shared connection with the number of calls using it
+type sharedConn struct {
+	conn    *grpc.ClientConn
+	users   int
+	retired bool // Replaced after a reload, closed when the last call releases it
+}
+
+var (
+	sharedConnsMu sync.Mutex
+	sharedConns   map[string]*sharedConn // Connections by target while sharing is enabled
+)
+
+// shareConnections makes calls reuse one connection per target, e.g. for concurrent watch loops.
+// The returned function closes the shared connections and disables sharing again.
+// Nested calls keep the connections of the outermost call.
+func shareConnections() func() {
+	sharedConnsMu.Lock()
+	if sharedConns != nil {
+		sharedConnsMu.Unlock()
+		return func() {}
+	}
+	sharedConns = make(map[string]*sharedConn)
+	sharedConnsMu.Unlock()
+
+	return func() {
+		sharedConnsMu.Lock()
+		defer sharedConnsMu.Unlock()
+		for _, shared := range sharedConns {
+			shared.conn.Close()
+		}
+		sharedConns = nil
+	}
+}
+
+// dialTarget connects to the target and returns the function which releases the connection.
+// Shared connections are kept open until sharing ends or they are replaced after a reload.
+func dialTarget(target *endpoints.Target) (*grpc.ClientConn, func(), error) {
+	sharedConnsMu.Lock()
+	defer sharedConnsMu.Unlock()
+
+	if sharedConns == nil {
+		conn, err := configs.Dial(target.HostPort, target.Credentials())
+		if err != nil {
+			return nil, nil, err
+		}
+		return conn, func() { conn.Close() }, nil
+	}
+
+	key := target.String()
+	shared, ok := sharedConns[key]
+	if !ok {
+		conn, err := configs.Dial(target.HostPort, target.Credentials())
+		if err != nil {
+			return nil, nil, err
+		}
+		shared = &sharedConn{conn: conn}
+		sharedConns[key] = shared
+	}
+	shared.users++
+
+	return shared.conn, func() { releaseSharedConn(shared) }, nil
+}
+
+// releaseSharedConn ends a call on a shared connection and closes the connection
+// if it was replaced and this was its last call
+func releaseSharedConn(shared *sharedConn) {
+	sharedConnsMu.Lock()
+	defer sharedConnsMu.Unlock()
+
+	shared.users--
+	if shared.retired && shared.users == 0 {
+		shared.conn.Close()
+	}
+}
+
+// resetSharedConnections makes the next calls dial new connections with the current endpoint and
+// transport settings. Calls in flight finish on the replaced connections, which are closed once
+// they are released. Sharing stays enabled.
+func resetSharedConnections() {
+	sharedConnsMu.Lock()
+	defer sharedConnsMu.Unlock()
+
+	for key, shared := range sharedConns {
+		shared.retired = true
+		if shared.users == 0 {
+			shared.conn.Close()
+		}
+		delete(sharedConns, key)
+	}
+}
@@ -0,0 +1,65 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/jhump/protoreflect/dynamic"
+)
+
+// decodeMessage converts a response message to the map used for output.
+// The results of list responses are converted one at a time, so that only the JSON of a
+// single item is buffered next to the decoded results instead of the whole response.
+func decodeMessage(msg *dynamic.Message) (map[string]interface{}, error) {
+	resultsField := msg.GetMessageDescriptor().FindFieldByName("results")
+	if resultsField == nil || !resultsField.IsRepeated() || resultsField.GetMessageType() == nil || resultsField.IsMap() {
+		return unmarshalMessage(msg)
+	}
+
+	items, _ := msg.GetField(resultsField).([]interface{})
+	if len(items) == 0 {
+		return unmarshalMessage(msg)
+	}
+
+	results := make([]interface{}, len(items))
+	for i, item := range items {
+		itemMsg, ok := item.(*dynamic.Message)
+		if !ok {
+			return unmarshalMessage(msg)
+		}
+
+		decoded, err := unmarshalMessage(itemMsg)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = decoded
+		items[i] = nil // Release the message once it is decoded
+	}
+
+	msg.ClearField(resultsField)
+	decoded, err := unmarshalMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	decoded[resultsField.GetJSONName()] = results
+
+	return decoded, nil
+}
+
+// unmarshalMessage converts a message to a map through its JSON form.
+// Well-known types rendered as raw messages are normalized to natural JSON.
+func unmarshalMessage(msg *dynamic.Message) (map[string]interface{}, error) {
+	jsonBytes, err := msg.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %v", err)
+	}
+
+	format.NormalizeWellKnownTypes(decoded)
+	return decoded, nil
+}
@@ -0,0 +1,87 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// DescriptorSource provides the service descriptors used to build requests and discover methods.
+// It is implemented by the reflection client and by compiled protoset files.
+type DescriptorSource interface {
+	ListServices() ([]string, error)
+	ResolveService(serviceName string) (*desc.ServiceDescriptor, error)
+	Reset()
+}
+
+// newDescriptorSource returns the protoset of the environment if configured, otherwise server reflection
+func newDescriptorSource(ctx context.Context, conn *grpc.ClientConn, config *Config) (DescriptorSource, error) {
+	if protoset := config.Environments[config.Environment].Protoset; protoset != "" {
+		return loadProtoset(protoset)
+	}
+	return configs.NewReflectionClient(ctx, conn), nil
+}
+
+// protosetSource resolves services from a compiled descriptor set
+type protosetSource struct {
+	services map[string]*desc.ServiceDescriptor
+}
+
+// loadProtoset reads a descriptor set created with
+//
+//	protoc --descriptor_set_out=spaceone.pb --include_imports -I. spaceone/api/**/*.proto
+func loadProtoset(path string) (*protosetSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read protoset file: %v", err)
+	}
+
+	var fds descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &fds); err != nil {
+		return nil, fmt.Errorf("failed to parse protoset file %s: %v", path, err)
+	}
+
+	files, err := desc.CreateFileDescriptorsFromSet(&fds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load protoset file %s: %v", path, err)
+	}
+
+	source := &protosetSource{services: make(map[string]*desc.ServiceDescriptor)}
+	for _, file := range files {
+		for _, service := range file.GetServices() {
+			source.services[service.GetFullyQualifiedName()] = service
+		}
+	}
+
+	return source, nil
+}
+
+// ListServices returns the fully qualified names of all services in the protoset
+func (s *protosetSource) ListServices() ([]string, error) {
+	names := make([]string, 0, len(s.services))
+	for name := range s.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ResolveService returns the descriptor of a fully qualified service name
+func (s *protosetSource) ResolveService(serviceName string) (*desc.ServiceDescriptor, error) {
+	service, ok := s.services[strings.TrimPrefix(serviceName, ".")]
+	if !ok {
+		return nil, fmt.Errorf("service %s not found in protoset", serviceName)
+	}
+	return service, nil
+}
+
+// Reset is a no-op since a protoset holds no connection
+func (s *protosetSource) Reset() {}
@@ -0,0 +1,153 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+)
+
+// diagnoseTimeout bounds each step of the connection diagnostics
+const diagnoseTimeout = 10 * time.Second
+
+// ConnectionReport holds the result of the connection diagnostics of a service
+type ConnectionReport struct {
+	Service     string        `json:"service"`
+	Target      string        `json:"target"`
+	HostPort    string        `json:"host_port"`
+	Addresses   []string      `json:"addresses,omitempty"`
+	ConnectTime time.Duration `json:"connect_time"`
+	TLS         *TLSReport    `json:"tls,omitempty"`
+	Reflection  bool          `json:"reflection"`
+	RoundTrip   time.Duration `json:"round_trip,omitempty"` // Time of a reflection ListServices call
+	Services    int           `json:"services,omitempty"`   // Number of services listed by reflection
+	Errors      []string      `json:"errors,omitempty"`
+}
+
+// TLSReport holds the negotiated parameters and the certificate chain of a TLS connection
+type TLSReport struct {
+	Version       string              `json:"version"`
+	CipherSuite   string              `json:"cipher_suite"`
+	ALPN          string              `json:"alpn"`
+	HandshakeTime time.Duration       `json:"handshake_time"`
+	Certificates  []CertificateReport `json:"certificates"`
+}
+
+// CertificateReport describes a certificate of the chain presented by the server
+type CertificateReport struct {
+	Subject  string    `json:"subject"`
+	Issuer   string    `json:"issuer"`
+	NotAfter time.Time `json:"not_after"`
+	DNSNames []string  `json:"dns_names,omitempty"`
+}
+
+// DiagnoseConnection runs the usual connectivity triage steps against the endpoint of a service:
+// endpoint resolution, DNS lookup, TCP connect, TLS handshake and a reflection call.
+// Failing steps are recorded in the report instead of aborting the diagnostics.
+func DiagnoseConnection(serviceName string) (*ConnectionReport, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %v", err)
+	}
+
+	env := config.Environments[config.Environment]
+	resolver, err := endpoints.NewResolver(config.Environment, env.Endpoint, env.Endpoints)
+	if err != nil {
+		return nil, err
+	}
+	target, err := resolver.Resolve(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ConnectionReport{
+		Service:  serviceName,
+		Target:   target.String(),
+		HostPort: target.HostPort,
+	}
+
+	host, _, err := net.SplitHostPort(target.HostPort)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target %s: %v", target.HostPort, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), diagnoseTimeout)
+	defer cancel()
+
+	if addrs, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("DNS lookup failed: %v", err))
+	} else {
+		report.Addresses = addrs
+	}
+
+	dialer := &net.Dialer{Timeout: diagnoseTimeout}
+	start := time.Now()
+	rawConn, err := dialer.DialContext(ctx, "tcp", target.HostPort)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("TCP connect failed: %v", err))
+		return report, nil
+	}
+	report.ConnectTime = time.Since(start)
+
+	if target.Insecure {
+		rawConn.Close()
+	} else {
+		tlsConn := tls.Client(rawConn, &tls.Config{ServerName: host, NextProtos: []string{"h2"}})
+		start = time.Now()
+		err := tlsConn.HandshakeContext(ctx)
+		handshakeTime := time.Since(start)
+		tlsConn.Close()
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("TLS handshake failed: %v", err))
+			return report, nil
+		}
+		report.TLS = tlsReport(tlsConn.ConnectionState(), handshakeTime)
+	}
+
+	conn, err := configs.Dial(target.HostPort, target.Credentials())
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("gRPC dial failed: %v", err))
+		return report, nil
+	}
+	defer conn.Close()
+
+	refClient := configs.NewReflectionClient(ctx, conn)
+	defer refClient.Reset()
+
+	start = time.Now()
+	services, err := refClient.ListServices()
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("reflection failed: %v", err))
+		return report, nil
+	}
+	report.RoundTrip = time.Since(start)
+	report.Reflection = true
+	report.Services = len(services)
+
+	return report, nil
+}
+
+// tlsReport summarizes the state of a completed TLS handshake
+func tlsReport(state tls.ConnectionState, handshakeTime time.Duration) *TLSReport {
+	report := &TLSReport{
+		Version:       tls.VersionName(state.Version),
+		CipherSuite:   tls.CipherSuiteName(state.CipherSuite),
+		ALPN:          state.NegotiatedProtocol,
+		HandshakeTime: handshakeTime,
+	}
+
+	for _, cert := range state.PeerCertificates {
+		report.Certificates = append(report.Certificates, CertificateReport{
+			Subject:  cert.Subject.String(),
+			Issuer:   cert.Issuer.String(),
+			NotAfter: cert.NotAfter,
+			DNSNames: cert.DNSNames,
+		})
+	}
+
+	return report
+}
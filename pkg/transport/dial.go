@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"fmt"
+
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// defaultMaxMessageSize is used when neither a flag nor the environment sets a limit
+const defaultMaxMessageSize = "10MiB"
+
+// defaultCallOptions returns the message size and compression options of a call.
+// Flags take precedence over the environment config.
+func defaultCallOptions(config *Config, options *FetchOptions) ([]grpc.CallOption, error) {
+	env := config.Environments[config.Environment]
+
+	recvSize, err := format.ParseBytes(firstNonEmpty(options.MaxRecvSize, env.MaxRecvSize, defaultMaxMessageSize))
+	if err != nil {
+		return nil, fmt.Errorf("invalid max receive size: %v", err)
+	}
+	sendSize, err := format.ParseBytes(firstNonEmpty(options.MaxSendSize, env.MaxSendSize, defaultMaxMessageSize))
+	if err != nil {
+		return nil, fmt.Errorf("invalid max send size: %v", err)
+	}
+
+	callOptions := []grpc.CallOption{
+		grpc.MaxCallRecvMsgSize(recvSize),
+		grpc.MaxCallSendMsgSize(sendSize),
+	}
+
+	switch compress := firstNonEmpty(options.Compress, env.Compress); compress {
+	case "", "none":
+	case gzip.Name:
+		callOptions = append(callOptions, grpc.UseCompressor(gzip.Name))
+	default:
+		return nil, fmt.Errorf("unsupported compression '%s' (use gzip or none)", compress)
+	}
+
+	return callOptions, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,195 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v3"
+)
+
+// EditResource fetches a resource, opens it in $EDITOR as YAML and submits
+// the changed fields with the update verb after a confirmation preview
+func EditResource(serviceName, resourceName string, options *FetchOptions) error {
+	original, err := FetchService(serviceName, "get", resourceName, &FetchOptions{
+		Parameters:    options.Parameters,
+		JSONParameter: options.JSONParameter,
+		FileParameter: options.FileParameter,
+		APIVersion:    options.APIVersion,
+		OutputFormat:  "",
+		Requested:     options.Requested,
+	})
+	if err != nil {
+		return err
+	}
+	if original == nil {
+		return nil
+	}
+
+	tmpFile, err := os.CreateTemp("", fmt.Sprintf("cfctl-%s-%s-*.yaml", serviceName, strings.ToLower(resourceName)))
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	originalYAML, err := printYAMLDoc(original)
+	if err != nil {
+		return err
+	}
+	if _, err := tmpFile.WriteString(originalYAML); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	tmpFile.Close()
+
+	if err := openEditor(tmpFile.Name()); err != nil {
+		return err
+	}
+
+	editedYAML, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return fmt.Errorf("failed to read edited file: %v", err)
+	}
+	if string(editedYAML) == originalYAML {
+		pterm.Info.Println("Edit cancelled, no changes made.")
+		return nil
+	}
+
+	before, err := normalizeYAML([]byte(originalYAML))
+	if err != nil {
+		return err
+	}
+	after, err := normalizeYAML(editedYAML)
+	if err != nil {
+		return fmt.Errorf("invalid YAML in edited file: %v", err)
+	}
+
+	changed := make(map[string]interface{})
+	for key, value := range after {
+		if !reflect.DeepEqual(before[key], value) {
+			changed[key] = value
+		}
+	}
+	if len(changed) == 0 {
+		pterm.Info.Println("Edit cancelled, no changes made.")
+		return nil
+	}
+
+	// Preview changes
+	keys := make([]string, 0, len(changed))
+	for key := range changed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pterm.DefaultSection.Println("Changes")
+	for _, key := range keys {
+		beforeYAML, err := printYAMLDoc(map[string]interface{}{key: before[key]})
+		if err != nil {
+			return err
+		}
+		afterYAML, err := printYAMLDoc(map[string]interface{}{key: changed[key]})
+		if err != nil {
+			return err
+		}
+		pterm.FgRed.Printf("- %s", beforeYAML)
+		pterm.FgGreen.Printf("+ %s", afterYAML)
+	}
+	fmt.Println()
+
+	confirmed := false
+	prompt := &survey.Confirm{
+		Message: fmt.Sprintf("Update %s with these changes?", resourceName),
+	}
+	if err := survey.AskOne(prompt, &confirmed); err != nil {
+		return err
+	}
+	if !confirmed {
+		pterm.Info.Println("Update cancelled.")
+		return nil
+	}
+
+	// Identify the resource with the same parameters used for get
+	updateParams, err := parseParameters(options)
+	if err != nil {
+		return err
+	}
+	for key, value := range changed {
+		updateParams[key] = value
+	}
+
+	jsonBytes, err := json.Marshal(updateParams)
+	if err != nil {
+		return fmt.Errorf("failed to marshal update parameters: %v", err)
+	}
+
+	outputFormat := options.OutputFormat
+	if outputFormat == "" {
+		outputFormat = "yaml"
+	}
+
+	_, err = FetchService(serviceName, "update", resourceName, &FetchOptions{
+		JSONParameter:     string(jsonBytes),
+		APIVersion:        options.APIVersion,
+		OutputFormat:      outputFormat,
+		HumanizeTime:      options.HumanizeTime,
+		RawValues:         options.RawValues,
+		DropUnknownFields: true,
+		Requested:         options.Requested,
+	})
+	return err
+}
+
+// openEditor opens the file with $VISUAL, $EDITOR or the platform default editor
+func openEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		} else {
+			editor = "vi"
+		}
+	}
+
+	// Editors can be configured with arguments (e.g. "code --wait")
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %s: %v", editor, err)
+	}
+	return nil
+}
+
+// normalizeYAML decodes YAML into JSON-compatible values so that they can be compared
+// with and sent like responses
+func normalizeYAML(data []byte) (map[string]interface{}, error) {
+	var decoded interface{}
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+)
+
+// EnrichSpec describes a client-side join of a referenced resource
+// Example:
+//
+//	project_id=identity.Project.name
+type EnrichSpec struct {
+	Field    string
+	Service  string
+	Resource string
+	Key      string
+}
+
+// ParseEnrichSpec parses an --enrich value in the form <field>=<service>.<Resource>.<key>
+func ParseEnrichSpec(value string) (*EnrichSpec, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return nil, fmt.Errorf("invalid enrich format '%s'. Use <field>=<service>.<Resource>.<key>", value)
+	}
+
+	target := strings.Split(parts[1], ".")
+	if len(target) != 3 || target[0] == "" || target[1] == "" || target[2] == "" {
+		return nil, fmt.Errorf("invalid enrich format '%s'. Use <field>=<service>.<Resource>.<key>", value)
+	}
+
+	return &EnrichSpec{
+		Field:    parts[0],
+		Service:  target[0],
+		Resource: target[1],
+		Key:      target[2],
+	}, nil
+}
+
+// Column returns the name of the column added to the output rows
+// Example:
+//
+//	project_id=identity.Project.name -> project_name
+func (s *EnrichSpec) Column() string {
+	return strings.TrimSuffix(s.Field, "_id") + "_" + s.Key
+}
+
+// enrichResults resolves referenced ids of list results with one batched list call per spec
+// and adds the referenced value to each row
+func enrichResults(config *Config, results []interface{}, enrich []string, resolver *endpoints.Resolver) error {
+	for _, value := range enrich {
+		spec, err := ParseEnrichSpec(value)
+		if err != nil {
+			return err
+		}
+
+		// Collect distinct ids
+		var ids []interface{}
+		seen := make(map[string]bool)
+		for _, result := range results {
+			if row, ok := result.(map[string]interface{}); ok {
+				if id, ok := row[spec.Field].(string); ok && id != "" && !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		query := map[string]interface{}{
+			"query": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{"k": spec.Field, "v": ids, "o": "in"},
+				},
+				"only": []string{spec.Field, spec.Key},
+			},
+		}
+		queryBytes, err := json.Marshal(query)
+		if err != nil {
+			return fmt.Errorf("failed to marshal enrich query: %v", err)
+		}
+
+		refOptions := &FetchOptions{JSONParameter: string(queryBytes)}
+		refResp, err := fetchResponse(config, spec.Service, "list", spec.Resource, refOptions, resolver)
+		if err != nil {
+			return fmt.Errorf("failed to enrich %s from %s.%s: %v", spec.Field, spec.Service, spec.Resource, err)
+		}
+
+		lookup := make(map[string]interface{})
+		if refResults, ok := refResp["results"].([]interface{}); ok {
+			for _, refResult := range refResults {
+				if refRow, ok := refResult.(map[string]interface{}); ok {
+					if id, ok := refRow[spec.Field].(string); ok {
+						lookup[id] = refRow[spec.Key]
+					}
+				}
+			}
+		}
+
+		column := spec.Column()
+		for _, result := range results {
+			if row, ok := result.(map[string]interface{}); ok {
+				if id, ok := row[spec.Field].(string); ok {
+					if refValue, exists := lookup[id]; exists {
+						row[column] = refValue
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package transport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+)
+
+// normalizeEnumParams converts the enum values of request parameters to their canonical names
+// using the request descriptor, so that -p state=enabled and -p state=1 are accepted as ENABLED.
+// Responses need no conversion since enums are always rendered by name.
+func normalizeEnumParams(msgDesc *desc.MessageDescriptor, params map[string]interface{}) error {
+	for key, value := range params {
+		field := msgDesc.FindFieldByName(key)
+		if field == nil {
+			field = msgDesc.FindFieldByJSONName(key)
+		}
+		if field == nil || field.IsMap() {
+			continue
+		}
+
+		normalized, err := normalizeFieldValue(field, value)
+		if err != nil {
+			return err
+		}
+		params[key] = normalized
+	}
+
+	return nil
+}
+
+// normalizeFieldValue converts the enum values of a single, possibly repeated, field
+func normalizeFieldValue(field *desc.FieldDescriptor, value interface{}) (interface{}, error) {
+	if items, ok := value.([]interface{}); ok && field.IsRepeated() {
+		for i, item := range items {
+			normalized, err := normalizeFieldValue(field, item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = normalized
+		}
+		return items, nil
+	}
+
+	if enumDesc := field.GetEnumType(); enumDesc != nil {
+		return enumValueName(field, enumDesc, value)
+	}
+
+	if msgDesc := field.GetMessageType(); msgDesc != nil {
+		if nested, ok := value.(map[string]interface{}); ok {
+			if err := normalizeEnumParams(msgDesc, nested); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return value, nil
+}
+
+// enumValueName returns the name of an enum value given by name in any case or by number
+func enumValueName(field *desc.FieldDescriptor, enumDesc *desc.EnumDescriptor, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		for _, enumValue := range enumDesc.GetValues() {
+			if strings.EqualFold(enumValue.GetName(), v) {
+				return enumValue.GetName(), nil
+			}
+		}
+	case float64:
+		if enumValue := enumDesc.FindValueByNumber(int32(v)); enumValue != nil {
+			return enumValue.GetName(), nil
+		}
+	default:
+		return value, nil
+	}
+
+	names := make([]string, 0, len(enumDesc.GetValues()))
+	for _, enumValue := range enumDesc.GetValues() {
+		names = append(names, enumValue.GetName())
+	}
+	return nil, fmt.Errorf("invalid value '%v' for %s (use %s)", value, field.GetName(), strings.Join(names, ", "))
+}
@@ -0,0 +1,96 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+)
+
+// ShellCommand runs a user command through the shell of the platform
+func ShellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// hookEnv returns the environment variables describing the call to a hook
+func hookEnv(serviceName, verb, resourceName string) []string {
+	return append(os.Environ(),
+		"CFCTL_SERVICE="+serviceName,
+		"CFCTL_VERB="+verb,
+		"CFCTL_RESOURCE="+resourceName,
+	)
+}
+
+// runPreRequestHook passes the request parameters to the pre_request hook on stdin.
+// A JSON object written to stdout replaces the parameters, no output keeps them unchanged.
+// A failing hook aborts the call.
+// Example:
+//
+//	#!/bin/sh
+//	# Add the owner tag to every create request
+//	[ "$CFCTL_VERB" = "create" ] && exec jq '.tags.owner = "platform-team"'
+func runPreRequestHook(hooks configs.Hooks, serviceName, verb, resourceName string, params map[string]interface{}) (map[string]interface{}, error) {
+	if hooks.PreRequest == "" {
+		return params, nil
+	}
+
+	requestJSON, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request for pre_request hook: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	cmd := ShellCommand(hooks.PreRequest)
+	cmd.Stdin = bytes.NewReader(requestJSON)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = hookEnv(serviceName, verb, resourceName)
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("pre_request hook failed: %v", err)
+	}
+
+	output := bytes.TrimSpace(stdout.Bytes())
+	if len(output) == 0 {
+		return params, nil
+	}
+
+	var mutated map[string]interface{}
+	if err := json.Unmarshal(output, &mutated); err != nil {
+		return nil, fmt.Errorf("pre_request hook returned invalid JSON: %v", err)
+	}
+
+	return mutated, nil
+}
+
+// runPostRequestHook passes the response to the post_request hook on stdin.
+// The output of the hook is written to stderr so that it does not mix with the command output.
+func runPostRequestHook(hooks configs.Hooks, serviceName, verb, resourceName string, response map[string]interface{}) error {
+	if hooks.PostRequest == "" {
+		return nil
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response for post_request hook: %v", err)
+	}
+
+	cmd := ShellCommand(hooks.PostRequest)
+	cmd.Stdin = bytes.NewReader(responseJSON)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	cmd.Env = hookEnv(serviceName, verb, resourceName)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("post_request hook failed: %v", err)
+	}
+
+	return nil
+}
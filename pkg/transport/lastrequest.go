@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestInfo describes a call for diagnostic bundles. Only the names of parameters and
+// headers are kept since their values may be secrets.
+type RequestInfo struct {
+	Method     string    `json:"method"`
+	HostPort   string    `json:"host_port"`
+	Parameters []string  `json:"parameters"`
+	Headers    []string  `json:"headers"`
+	Time       time.Time `json:"time"`
+}
+
+var (
+	lastRequestMu sync.Mutex
+	lastRequest   *RequestInfo
+)
+
+// LastRequest returns the last call made by the process, or nil if there was none
+func LastRequest() *RequestInfo {
+	lastRequestMu.Lock()
+	defer lastRequestMu.Unlock()
+	return lastRequest
+}
+
+// recordLastRequest remembers a call before it is sent, so that a crash during the call can be reported
+func recordLastRequest(ctx context.Context, method, hostPort string, params map[string]interface{}) {
+	info := &RequestInfo{Method: method, HostPort: hostPort, Time: time.Now()}
+	for key := range params {
+		info.Parameters = append(info.Parameters, key)
+	}
+	sort.Strings(info.Parameters)
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		for key := range md {
+			info.Headers = append(info.Headers, key)
+		}
+		sort.Strings(info.Headers)
+	}
+
+	lastRequestMu.Lock()
+	lastRequest = info
+	lastRequestMu.Unlock()
+}
@@ -0,0 +1,175 @@
+package transport
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+	"github.com/jhump/protoreflect/desc"
+)
+
+// isUpdateVerb reports whether a verb changes an existing resource
+// Example:
+//
+//	update, update_plugin, update_secret_data
+func isUpdateVerb(verb string) bool {
+	return strings.HasPrefix(verb, "update")
+}
+
+// currentResource gets the current state of the resource of an update call
+// and keeps only the fields which the update request accepts
+func currentResource(config *Config, serviceName, verb, resourceName, id string, inputType *desc.MessageDescriptor, options *FetchOptions, resolver *endpoints.Resolver) (map[string]interface{}, error) {
+	if !isUpdateVerb(verb) {
+		return nil, fmt.Errorf("the current resource can only be fetched for update verbs, not %s", verb)
+	}
+
+	idField := ResourceIDField(resourceName)
+	current, err := fetchResponse(config, serviceName, "get", resourceName, &FetchOptions{
+		Parameters: []string{fmt.Sprintf("%s=%s", idField, id)},
+		APIVersion: options.APIVersion,
+	}, resolver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current %s %s: %v", resourceName, id, err)
+	}
+
+	request := writableFields(inputType, current)
+	if inputType.FindFieldByName(idField) != nil {
+		request[idField] = id
+	}
+	return request, nil
+}
+
+// writableFields keeps the values of a response which are fields of the request message
+// and are not annotated as output only or immutable, dropping server managed fields
+// such as created_at
+func writableFields(inputType *desc.MessageDescriptor, values map[string]interface{}) map[string]interface{} {
+	writable := make(map[string]interface{})
+	for key, value := range values {
+		field := inputType.FindFieldByName(key)
+		if field == nil {
+			continue
+		}
+		if isReadOnlyField(field) {
+			continue
+		}
+		writable[key] = value
+	}
+	return writable
+}
+
+// isReadOnlyField reports whether a field is annotated as output only or immutable
+func isReadOnlyField(field *desc.FieldDescriptor) bool {
+	for _, behavior := range fieldBehaviors(field) {
+		if behavior == fieldBehaviorOutputOnly || behavior == fieldBehaviorImmutable {
+			return true
+		}
+	}
+	return false
+}
+
+// Strategies for arrays of merge patches
+const (
+	patchArraysReplace = "replace" // RFC 7386, the array of the patch replaces the current array
+	patchArraysAppend  = "append"  // Items of the patch missing from the current array are appended
+	patchArraysMerge   = "merge"   // Objects are merged with the current item of the same merge key, others appended
+)
+
+// patchMergeKeys are the fields identifying the objects of arrays merged with the merge strategy
+var patchMergeKeys = []string{"key", "name", "id"}
+
+// loadPatch reads a JSON merge patch from a YAML or JSON file
+func loadPatch(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read patch file: %v", err)
+	}
+	patch, err := normalizeYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse patch file: %v", err)
+	}
+	return patch, nil
+}
+
+// mergePatch applies a JSON merge patch (RFC 7386) to a value. Objects are merged recursively,
+// null removes a field and other values replace the current ones. Arrays are handled by the strategy.
+func mergePatch(target, patch interface{}, arrays string) interface{} {
+	switch patchValue := patch.(type) {
+	case map[string]interface{}:
+		targetMap, ok := target.(map[string]interface{})
+		if !ok {
+			targetMap = make(map[string]interface{})
+		}
+		merged := make(map[string]interface{}, len(targetMap))
+		for key, value := range targetMap {
+			merged[key] = value
+		}
+		for key, value := range patchValue {
+			if value == nil {
+				delete(merged, key)
+				continue
+			}
+			merged[key] = mergePatch(merged[key], value, arrays)
+		}
+		return merged
+
+	case []interface{}:
+		targetList, ok := target.([]interface{})
+		if !ok || arrays == patchArraysReplace || arrays == "" {
+			return patchValue
+		}
+		return mergeArrays(targetList, patchValue, arrays)
+
+	default:
+		return patch
+	}
+}
+
+// mergeArrays combines the current items of an array with the items of a patch
+func mergeArrays(current, patch []interface{}, arrays string) []interface{} {
+	merged := append([]interface{}(nil), current...)
+	for _, item := range patch {
+		if arrays == patchArraysMerge {
+			if index := matchingItem(merged, item); index >= 0 {
+				merged[index] = mergePatch(merged[index], item, arrays)
+				continue
+			}
+		}
+		if !containsValue(merged, item) {
+			merged = append(merged, item)
+		}
+	}
+	return merged
+}
+
+// matchingItem returns the index of the object with the same merge key as the item, or -1
+func matchingItem(items []interface{}, item interface{}) int {
+	object, ok := item.(map[string]interface{})
+	if !ok {
+		return -1
+	}
+	for _, key := range patchMergeKeys {
+		value, ok := object[key]
+		if !ok {
+			continue
+		}
+		for i, candidate := range items {
+			if candidateObject, ok := candidate.(map[string]interface{}); ok && reflect.DeepEqual(candidateObject[key], value) {
+				return i
+			}
+		}
+		return -1
+	}
+	return -1
+}
+
+// containsValue reports whether the items contain a value
+func containsValue(items []interface{}, value interface{}) bool {
+	for _, item := range items {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
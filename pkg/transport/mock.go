@@ -0,0 +1,243 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	v1reflectiongrpc "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	v1alphareflectiongrpc "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// MockServer serves canned responses for the services of a protoset and advertises them via server reflection
+type MockServer struct {
+	files    *protoregistry.Files
+	types    *dynamicpb.Types
+	services map[string]grpc.ServiceInfo
+	fixtures string
+	server   *grpc.Server
+}
+
+// NewMockServer loads the services of a protoset file created with
+//
+//	protoc --descriptor_set_out=spaceone.pb --include_imports -I. spaceone/api/**/*.proto
+//
+// Responses are read from <fixtures>/<package.Service>/<Method>.json in the protobuf JSON format.
+// A JSON array holds the messages sent by a server streaming method.
+func NewMockServer(protoset, fixtures string) (*MockServer, error) {
+	data, err := os.ReadFile(protoset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read protoset file: %v", err)
+	}
+
+	var fds descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &fds); err != nil {
+		return nil, fmt.Errorf("failed to parse protoset file %s: %v", protoset, err)
+	}
+
+	files, err := protodesc.NewFiles(&fds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load protoset file %s: %v", protoset, err)
+	}
+
+	info, err := os.Stat(fixtures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures directory: %v", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixtures path %s is not a directory", fixtures)
+	}
+
+	m := &MockServer{
+		files:    files,
+		types:    dynamicpb.NewTypes(files),
+		services: make(map[string]grpc.ServiceInfo),
+		fixtures: fixtures,
+	}
+
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			service := services.Get(i)
+			methods := make([]grpc.MethodInfo, 0, service.Methods().Len())
+			for j := 0; j < service.Methods().Len(); j++ {
+				method := service.Methods().Get(j)
+				methods = append(methods, grpc.MethodInfo{
+					Name:           string(method.Name()),
+					IsClientStream: method.IsStreamingClient(),
+					IsServerStream: method.IsStreamingServer(),
+				})
+			}
+			m.services[string(service.FullName())] = grpc.ServiceInfo{Methods: methods, Metadata: file.Path()}
+		}
+		return true
+	})
+
+	if len(m.services) == 0 {
+		return nil, fmt.Errorf("no services found in protoset file %s", protoset)
+	}
+
+	return m, nil
+}
+
+// GetServiceInfo returns the services of the protoset for the reflection service
+func (m *MockServer) GetServiceInfo() map[string]grpc.ServiceInfo {
+	return m.services
+}
+
+// Serve listens on the address and serves requests until Stop is called
+func (m *MockServer) Serve(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", address, err)
+	}
+
+	m.server = grpc.NewServer(grpc.UnknownServiceHandler(m.handle))
+
+	reflectionOptions := reflection.ServerOptions{
+		Services:           m,
+		DescriptorResolver: m.files,
+		ExtensionResolver:  extensionTypes(m.files),
+	}
+	// Register both versions since clients fall back to v1alpha on older servers
+	v1reflectiongrpc.RegisterServerReflectionServer(m.server, reflection.NewServerV1(reflectionOptions))
+	v1alphareflectiongrpc.RegisterServerReflectionServer(m.server, reflection.NewServer(reflectionOptions))
+
+	return m.server.Serve(listener)
+}
+
+// Stop stops the server gracefully
+func (m *MockServer) Stop() {
+	if m.server != nil {
+		m.server.GracefulStop()
+	}
+}
+
+// handle serves a call of any method of the protoset with its fixture
+func (m *MockServer) handle(_ interface{}, stream grpc.ServerStream) error {
+	fullMethod, ok := grpc.MethodFromServerStream(stream)
+	if !ok {
+		return status.Error(codes.Internal, "failed to determine the called method")
+	}
+
+	serviceName, methodName, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "malformed method name: %s", fullMethod)
+	}
+
+	descriptor, err := m.files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return status.Errorf(codes.Unimplemented, "unknown service %s", serviceName)
+	}
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "unknown service %s", serviceName)
+	}
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return status.Errorf(codes.Unimplemented, "unknown method %s", fullMethod)
+	}
+
+	// Drain the request messages so that the call completes like on a real server
+	for {
+		request := dynamicpb.NewMessage(method.Input())
+		if err := stream.RecvMsg(request); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		if !method.IsStreamingClient() {
+			break
+		}
+	}
+
+	responses, err := m.loadFixture(serviceName, method)
+	if err != nil {
+		pterm.Warning.WithWriter(os.Stderr).Printf("%s: %v\n", fullMethod, err)
+		return err
+	}
+	pterm.Info.WithWriter(os.Stderr).Printf("%s: served %d message(s)\n", fullMethod, len(responses))
+
+	for _, response := range responses {
+		if err := stream.SendMsg(response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadFixture reads the response messages of a method from the fixtures directory
+func (m *MockServer) loadFixture(serviceName string, method protoreflect.MethodDescriptor) ([]proto.Message, error) {
+	path := filepath.Join(m.fixtures, serviceName, string(method.Name())+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, status.Errorf(codes.Unimplemented, "no fixture found at %s", path)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to read fixture %s: %v", path, err)
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		items = []json.RawMessage{data}
+	}
+	if len(items) != 1 && !method.IsStreamingServer() {
+		return nil, status.Errorf(codes.Internal, "fixture %s must hold a single message for a unary method", path)
+	}
+
+	unmarshalOptions := protojson.UnmarshalOptions{DiscardUnknown: true, Resolver: m.types}
+	responses := make([]proto.Message, 0, len(items))
+	for _, item := range items {
+		response := dynamicpb.NewMessage(method.Output())
+		if err := unmarshalOptions.Unmarshal(item, response); err != nil {
+			return nil, status.Errorf(codes.Internal, "invalid fixture %s: %v", path, err)
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+// extensionTypes registers the extensions declared in the files for the reflection service
+func extensionTypes(files *protoregistry.Files) *protoregistry.Types {
+	types := new(protoregistry.Types)
+
+	var registerMessages func(messages protoreflect.MessageDescriptors)
+	registerExtensions := func(extensions protoreflect.ExtensionDescriptors) {
+		for i := 0; i < extensions.Len(); i++ {
+			_ = types.RegisterExtension(dynamicpb.NewExtensionType(extensions.Get(i)))
+		}
+	}
+	registerMessages = func(messages protoreflect.MessageDescriptors) {
+		for i := 0; i < messages.Len(); i++ {
+			registerExtensions(messages.Get(i).Extensions())
+			registerMessages(messages.Get(i).Messages())
+		}
+	}
+
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		registerExtensions(file.Extensions())
+		registerMessages(file.Messages())
+		return true
+	})
+
+	return types
+}
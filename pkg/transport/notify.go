@@ -0,0 +1,71 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/pterm/pterm"
+)
+
+// notifyNewItems runs the notification hooks of watch mode for each new or changed item
+func notifyNewItems(serviceName, resource string, items []map[string]interface{}, options *FetchOptions) {
+	if options.NotifyCommand != "" {
+		for _, item := range items {
+			if err := runNotifyCommand(options.NotifyCommand, serviceName, resource, item); err != nil {
+				pterm.Warning.Printf("Notify command failed: %v\n", err)
+			}
+		}
+	}
+
+	if options.NotifyDesktop {
+		message := fmt.Sprintf("%d new %s items", len(items), resource)
+		if err := sendDesktopNotification(fmt.Sprintf("cfctl %s", serviceName), message); err != nil {
+			pterm.Warning.Printf("Desktop notification failed: %v\n", err)
+		}
+	}
+}
+
+// runNotifyCommand executes the user hook with the item JSON on stdin.
+// The service and resource are passed as CFCTL_SERVICE and CFCTL_RESOURCE.
+// Example:
+//
+//	--notify-command 'jq -r .state | grep -q FAILURE && slack-notify "collection failed"'
+func runNotifyCommand(command, serviceName, resource string, item map[string]interface{}) error {
+	itemJSON, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal item: %v", err)
+	}
+
+	cmd := ShellCommand(command)
+	cmd.Stdin = bytes.NewReader(itemJSON)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"CFCTL_SERVICE="+serviceName,
+		"CFCTL_RESOURCE="+resource,
+	)
+
+	return cmd.Run()
+}
+
+// sendDesktopNotification shows a native notification with notify-send on Linux and osascript on macOS
+func sendDesktopNotification(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		cmd = exec.Command("notify-send", title, message)
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", message, title))
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, bytes.TrimSpace(output))
+	}
+	return nil
+}
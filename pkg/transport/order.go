@@ -0,0 +1,136 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/jhump/protoreflect/desc"
+	"gopkg.in/yaml.v3"
+)
+
+// orderedMap is an object whose keys are encoded in a fixed order in JSON and YAML
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyBytes, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueBytes, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyBytes)
+		buf.WriteByte(':')
+		buf.Write(valueBytes)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (m orderedMap) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range m.keys {
+		var keyNode, valueNode yaml.Node
+		if err := keyNode.Encode(key); err != nil {
+			return nil, err
+		}
+		if err := valueNode.Encode(m.values[key]); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %v", key, err)
+		}
+		node.Content = append(node.Content, &keyNode, &valueNode)
+	}
+	return node, nil
+}
+
+// sortKeys orders the keys of the objects in a value by the field order of the proto message,
+// followed by the keys which are not fields in alphabetical order, so that the output of
+// different runs can be compared line by line. Objects without a message, e.g. Struct
+// fields and maps, are ordered alphabetically.
+func sortKeys(value interface{}, msgDesc *desc.MessageDescriptor) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		var keys []string
+		fields := make(map[string]*desc.FieldDescriptor)
+		if msgDesc != nil {
+			for _, field := range msgDesc.GetFields() {
+				for _, name := range []string{field.GetName(), field.GetJSONName()} {
+					if _, ok := v[name]; ok && fields[name] == nil {
+						keys = append(keys, name)
+						fields[name] = field
+					}
+				}
+			}
+		}
+
+		var others []string
+		for key := range v {
+			if fields[key] == nil {
+				others = append(others, key)
+			}
+		}
+		sort.Strings(others)
+		keys = append(keys, others...)
+
+		values := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			values[key] = sortKeys(item, fieldMessage(fields[key]))
+		}
+		return orderedMap{keys: keys, values: values}
+
+	case []interface{}:
+		sorted := make([]interface{}, len(v))
+		for i, item := range v {
+			sorted[i] = sortKeys(item, msgDesc)
+		}
+		return sorted
+
+	default:
+		return value
+	}
+}
+
+// fieldMessage returns the message of the values of a field, or nil for scalars and maps
+func fieldMessage(field *desc.FieldDescriptor) *desc.MessageDescriptor {
+	if field == nil || field.IsMap() {
+		return nil
+	}
+	return field.GetMessageType()
+}
+
+// outputDescriptor returns the response message for ordering the printed data,
+// or nil when jq or grouping changed the data into another shape
+func outputDescriptor(options *FetchOptions, serviceName, verb, resourceName string, refClient DescriptorSource) *desc.MessageDescriptor {
+	if options.JQ != "" || options.GroupBy != "" {
+		return nil
+	}
+	return responseDescriptor(serviceName, verb, resourceName, refClient)
+}
+
+// responseDescriptor returns the response message of a method of a resource, or nil if it is unknown
+func responseDescriptor(serviceName, verb, resourceName string, refClient DescriptorSource) *desc.MessageDescriptor {
+	if refClient == nil {
+		return nil
+	}
+	for _, version := range []string{"v1", "v2"} {
+		serviceDesc, err := refClient.ResolveService(fmt.Sprintf("spaceone.api.%s.%s.%s", serviceName, version, resourceName))
+		if err != nil {
+			continue
+		}
+		if methodDesc := serviceDesc.FindMethodByName(verb); methodDesc != nil {
+			return methodDesc.GetOutputType()
+		}
+	}
+	return nil
+}
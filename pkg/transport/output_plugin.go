@@ -0,0 +1,56 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// outputPluginPrefix selects an external formatter as output format (-o plugin:<name>)
+const outputPluginPrefix = "plugin:"
+
+// isOutputPlugin reports whether the output format is handled by an external formatter
+func isOutputPlugin(outputFormat string) bool {
+	return strings.HasPrefix(outputFormat, outputPluginPrefix)
+}
+
+// runOutputPlugin feeds the JSON response to the cfctl-format-<name> executable on PATH
+// and returns what it writes to stdout.
+// The call is described to the plugin by CFCTL_SERVICE, CFCTL_VERB and CFCTL_RESOURCE.
+func runOutputPlugin(outputFormat string, data map[string]interface{}, serviceName, verbName, resourceName string) (string, error) {
+	name := strings.TrimPrefix(outputFormat, outputPluginPrefix)
+	if name == "" {
+		return "", fmt.Errorf("output plugin name is required (e.g. -o plugin:myformatter)")
+	}
+
+	binary := "cfctl-format-" + name
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return "", fmt.Errorf("output plugin %s not found in PATH", binary)
+	}
+
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response to JSON: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(path)
+	cmd.Stdin = bytes.NewReader(dataBytes)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"CFCTL_SERVICE="+serviceName,
+		"CFCTL_VERB="+verbName,
+		"CFCTL_RESOURCE="+resourceName,
+	)
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("output plugin %s failed: %v", binary, err)
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,175 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+	"github.com/cloudforet-io/cfctl/pkg/format"
+)
+
+const (
+	// allPagesLimit is the number of items requested per page with --all-pages
+	allPagesLimit = 1000
+	// defaultConcurrency is the number of pages fetched at once with --all-pages
+	defaultConcurrency = 4
+)
+
+// fetchAllPages fetches every page of a list call and returns them as a single response
+func fetchAllPages(config *Config, serviceName, resourceName string, options *FetchOptions, resolver *endpoints.Resolver) (map[string]interface{}, error) {
+	var allResults []interface{}
+	totalCount, err := forEachPage(config, serviceName, resourceName, options, resolver, func(results []interface{}) error {
+		allResults = append(allResults, results...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"results":     allResults,
+		"total_count": totalCount,
+	}, nil
+}
+
+// forEachPage fetches every page of a list call and passes the results of each page to emit in order.
+// The first page tells the total count, the remaining pages are fetched concurrently by a bounded
+// number of workers. Workers stay at most two pages per worker ahead of the last emitted page
+// so that only a few pages are held in memory at once.
+func forEachPage(config *Config, serviceName, resourceName string, options *FetchOptions, resolver *endpoints.Resolver, emit func(results []interface{}) error) (int, error) {
+	params, err := parseParameters(options)
+	if err != nil {
+		return 0, err
+	}
+
+	defer shareConnections()()
+
+	first, err := fetchPage(config, serviceName, resourceName, options, resolver, params, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	totalCount := 0
+	if count, ok := first["total_count"].(float64); ok {
+		totalCount = int(count)
+	}
+	results, _ := first["results"].([]interface{})
+	if err := emit(results); err != nil {
+		return 0, err
+	}
+
+	// Responses without a total count are returned as the single page
+	pageCount := (totalCount + allPagesLimit - 1) / allPagesLimit
+	if pageCount <= 1 {
+		if totalCount == 0 {
+			totalCount = len(results)
+		}
+		return totalCount, nil
+	}
+
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	progress := format.NewProgress("Fetching pages", pageCount-1)
+	pageNumbers := make(chan int)
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		pending = make(map[int][]interface{}) // Fetched pages waiting for the previous pages
+		next    = 2                           // Next page to emit
+		emitErr error
+	)
+	emitted := sync.NewCond(&mu)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range pageNumbers {
+				resp, err := fetchPage(config, serviceName, resourceName, options, resolver, params, page)
+
+				mu.Lock()
+				if err != nil {
+					progress.Fail(fmt.Sprintf("page %d", page), err)
+					pending[page] = nil
+				} else {
+					pending[page], _ = resp["results"].([]interface{})
+					progress.Succeed()
+				}
+
+				// Emit the pages which are complete up to this point
+				for {
+					pageResults, ok := pending[next]
+					if !ok {
+						break
+					}
+					delete(pending, next)
+					if emitErr == nil && pageResults != nil {
+						emitErr = emit(pageResults)
+					}
+					next++
+				}
+				emitted.Broadcast()
+				mu.Unlock()
+			}
+		}()
+	}
+
+	window := 2 * concurrency
+	for page := 2; page <= pageCount; page++ {
+		mu.Lock()
+		for page >= next+window {
+			emitted.Wait()
+		}
+		mu.Unlock()
+		pageNumbers <- page
+	}
+	close(pageNumbers)
+	wg.Wait()
+
+	summary := progress.Stop()
+	if summary.Failed > 0 {
+		summary.PrintPartialFailure()
+		return 0, fmt.Errorf("failed to fetch %d of %d pages", summary.Failed, pageCount)
+	}
+	if emitErr != nil {
+		return 0, emitErr
+	}
+
+	return totalCount, nil
+}
+
+// fetchPage requests a single page of a list call using the query.page parameter of SpaceONE APIs
+func fetchPage(config *Config, serviceName, resourceName string, options *FetchOptions, resolver *endpoints.Resolver, params map[string]interface{}, page int) (map[string]interface{}, error) {
+	pageParams := make(map[string]interface{}, len(params)+1)
+	for key, value := range params {
+		pageParams[key] = value
+	}
+
+	query := make(map[string]interface{})
+	if existing, ok := params["query"].(map[string]interface{}); ok {
+		for key, value := range existing {
+			query[key] = value
+		}
+	}
+	query["page"] = map[string]interface{}{
+		"start": (page-1)*allPagesLimit + 1,
+		"limit": allPagesLimit,
+	}
+	pageParams["query"] = query
+
+	paramBytes, err := json.Marshal(pageParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal page parameters: %v", err)
+	}
+
+	pageOptions := *options
+	pageOptions.Parameters = nil
+	pageOptions.FileParameter = ""
+	pageOptions.JSONParameter = string(paramBytes)
+
+	return fetchResponse(config, serviceName, "list", resourceName, &pageOptions, resolver)
+}
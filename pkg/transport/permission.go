@@ -0,0 +1,178 @@
+package transport
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Identity describes the caller of the current token and its role
+type Identity struct {
+	UserID      string
+	OwnerType   string
+	DomainID    string
+	WorkspaceID string
+	RoleType    string
+	RoleID      string
+	RoleName    string
+	Permissions []string
+	ExpiresAt   float64
+}
+
+// DecodeTokenClaims decodes the payload of a JWT token without verifying it
+func DecodeTokenClaims(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid token format")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode token payload: %v", err)
+	}
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("failed to parse token payload: %v", err)
+	}
+
+	return claims, nil
+}
+
+// CurrentIdentity decodes the token of the current environment.
+// The role and its permissions are fetched from identity when withPermissions is set.
+func CurrentIdentity(withPermissions bool) (*Identity, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	token := config.Environments[config.Environment].Token
+	if token == "" {
+		return nil, fmt.Errorf("no token found. Please run 'cfctl login' first")
+	}
+
+	claims, err := DecodeTokenClaims(token)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{}
+	identity.UserID, _ = claims["aud"].(string)
+	identity.OwnerType, _ = claims["own"].(string)
+	identity.DomainID, _ = claims["did"].(string)
+	identity.WorkspaceID, _ = claims["wid"].(string)
+	identity.RoleType, _ = claims["rol"].(string)
+	identity.ExpiresAt, _ = claims["exp"].(float64)
+
+	if !withPermissions {
+		return identity, nil
+	}
+
+	identity.RoleID, err = lookupRoleID(identity)
+	if err != nil {
+		return nil, err
+	}
+	if identity.RoleID == "" {
+		return identity, nil
+	}
+
+	role, err := FetchService("identity", "get", "Role", &FetchOptions{
+		Parameters: []string{fmt.Sprintf("role_id=%s", identity.RoleID)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role %s: %v", identity.RoleID, err)
+	}
+	identity.RoleName, _ = role["name"].(string)
+	if roleType, ok := role["role_type"].(string); ok {
+		identity.RoleType = roleType
+	}
+	if permissions, ok := role["permissions"].([]interface{}); ok {
+		for _, p := range permissions {
+			identity.Permissions = append(identity.Permissions, fmt.Sprintf("%v", p))
+		}
+	}
+
+	return identity, nil
+}
+
+// lookupRoleID finds the role bound to the app or the user in the current scope
+func lookupRoleID(identity *Identity) (string, error) {
+	if identity.OwnerType == "APP" {
+		app, err := FetchService("identity", "get", "App", &FetchOptions{
+			Parameters: []string{fmt.Sprintf("app_id=%s", identity.UserID)},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to get app %s: %v", identity.UserID, err)
+		}
+		roleID, _ := app["role_id"].(string)
+		return roleID, nil
+	}
+
+	parameters := []string{fmt.Sprintf("user_id=%s", identity.UserID)}
+	if identity.WorkspaceID != "" {
+		parameters = append(parameters, fmt.Sprintf("workspace_id=%s", identity.WorkspaceID))
+	}
+	bindings, err := FetchService("identity", "list", "RoleBinding", &FetchOptions{
+		Parameters: parameters,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list role bindings: %v", err)
+	}
+
+	results, _ := bindings["results"].([]interface{})
+	for _, result := range results {
+		if binding, ok := result.(map[string]interface{}); ok {
+			if roleID, ok := binding["role_id"].(string); ok {
+				return roleID, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// PermissionString maps a call to its SpaceONE permission
+// Example:
+//
+//	inventory, list, Server -> inventory:Server.list
+func PermissionString(serviceName, verb, resourceName string) string {
+	return fmt.Sprintf("%s:%s.%s", serviceName, resourceName, verb)
+}
+
+// HasPermission reports whether one of the role permissions grants the permission.
+// Role permissions may use wildcards and '/' separators (e.g. inventory/*, identity:Project.*).
+func (i *Identity) HasPermission(permission string) bool {
+	for _, granted := range i.Permissions {
+		pattern := strings.Replace(granted, "/", ":", 1)
+		pattern = strings.ReplaceAll(pattern, "/", ".")
+		if pattern == "*" || pattern == permission {
+			return true
+		}
+		if matched, _ := path.Match(pattern, permission); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPermission returns an error when the role of the caller does not grant the call.
+// The check is skipped when the role cannot be looked up or has no explicit permissions.
+func checkPermission(serviceName, verb, resourceName string) error {
+	identity, err := CurrentIdentity(true)
+	if err != nil || len(identity.Permissions) == 0 {
+		return nil
+	}
+
+	permission := PermissionString(serviceName, verb, resourceName)
+	if identity.HasPermission(permission) {
+		return nil
+	}
+
+	role := identity.RoleName
+	if role == "" {
+		role = identity.RoleID
+	}
+	return fmt.Errorf("your role %s lacks permission %s (see 'cfctl identity whoami --permissions')", role, permission)
+}
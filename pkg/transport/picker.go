@@ -0,0 +1,101 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/pterm/pterm"
+	"golang.org/x/term"
+)
+
+// pickerLimit is the number of resources listed in the picker
+const pickerLimit = 1000
+
+// pickerVerbs are the verbs whose missing id is chosen from a picker
+var pickerVerbs = map[string]bool{"get": true, "update": true, "delete": true}
+
+// pickerKeyFields are shown next to the name and id of a resource when it has them
+var pickerKeyFields = []string{"state", "status", "provider", "cloud_service_group", "cloud_service_type", "region_code"}
+
+// pickResourceID lets the user choose the resource of a get, update or delete call
+// from a fuzzy searchable list when the id was not given in a terminal
+func pickResourceID(config *Config, serviceName, verb, resourceName string, inputType *desc.MessageDescriptor, params map[string]interface{}, resolver *endpoints.Resolver) error {
+	if !pickerVerbs[verb] {
+		return nil
+	}
+	idField := ResourceIDField(resourceName)
+	if inputType.FindFieldByName(idField) == nil {
+		return nil
+	}
+	if _, ok := params[idField]; ok {
+		return nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return nil
+	}
+
+	query, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"page": map[string]interface{}{"limit": pickerLimit},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal list query: %v", err)
+	}
+
+	resp, err := fetchResponse(config, serviceName, "list", resourceName, &FetchOptions{JSONParameter: string(query)}, resolver)
+	if err != nil {
+		return fmt.Errorf("failed to list %s to pick from: %v", resourceName, err)
+	}
+
+	var labels []string
+	ids := make(map[string]string)
+	results, _ := resp["results"].([]interface{})
+	for _, result := range results {
+		row, ok := result.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, ok := row[idField].(string)
+		if !ok {
+			continue
+		}
+
+		label := pickerLabel(row, id)
+		labels = append(labels, label)
+		ids[label] = id
+	}
+	if len(labels) == 0 {
+		return fmt.Errorf("no %s found to %s", resourceName, verb)
+	}
+
+	selected, err := pterm.DefaultInteractiveSelect.
+		WithOptions(labels).
+		WithMaxHeight(15).
+		Show(fmt.Sprintf("Select %s to %s (type to search)", resourceName, verb))
+	if err != nil {
+		return fmt.Errorf("failed to read selection: %v", err)
+	}
+
+	params[idField] = ids[selected]
+	return nil
+}
+
+// pickerLabel describes a resource in the picker by its name, id and key fields
+func pickerLabel(row map[string]interface{}, id string) string {
+	parts := []string{}
+	if name, ok := row["name"].(string); ok && name != "" {
+		parts = append(parts, name)
+	}
+	parts = append(parts, id)
+	for _, field := range pickerKeyFields {
+		if value, ok := row[field].(string); ok && value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,87 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/format"
+)
+
+// defaultTimeField is the field filtered by --since and --until
+const defaultTimeField = "created_at"
+
+// queryFilterOptions appends filters to the query of the call parameters
+func queryFilterOptions(options *FetchOptions, filters ...map[string]interface{}) (*FetchOptions, error) {
+	params, err := parseParameters(options)
+	if err != nil {
+		return nil, err
+	}
+
+	query, _ := params["query"].(map[string]interface{})
+	if query == nil {
+		query = make(map[string]interface{})
+	}
+	existing, _ := query["filter"].([]interface{})
+	for _, filter := range filters {
+		existing = append(existing, filter)
+	}
+	query["filter"] = existing
+	params["query"] = query
+
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	options.Parameters = nil
+	options.FileParameter = ""
+	options.JSONParameter = string(paramsJSON)
+	return options, nil
+}
+
+// timeRangeOptions converts --since and --until into a datetime range filter of the query.
+// Times are given as durations relative to now (24h, 7d) or as dates (2024-06-01).
+// Example:
+//
+//	--since 24h -> {"k": "created_at", "v": "2024-06-01T09:00:00Z", "o": "datetime_gte"}
+func timeRangeOptions(options *FetchOptions, now time.Time) (*FetchOptions, error) {
+	field := options.TimeField
+	if field == "" {
+		field = defaultTimeField
+	}
+
+	var filters []map[string]interface{}
+	if options.Since != "" {
+		since, err := format.ParseTimeArg(options.Since, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --since: %v", err)
+		}
+		filters = append(filters, map[string]interface{}{
+			"k": field,
+			"v": since.UTC().Format(time.RFC3339),
+			"o": "datetime_gte",
+		})
+	}
+	if options.Until != "" {
+		until, err := format.ParseTimeArg(options.Until, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --until: %v", err)
+		}
+		filters = append(filters, map[string]interface{}{
+			"k": field,
+			"v": until.UTC().Format(time.RFC3339),
+			"o": "datetime_lt",
+		})
+	}
+
+	options, err := queryFilterOptions(options, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The range is part of the parameters now and must not be applied twice
+	options.Since = ""
+	options.Until = ""
+	return options, nil
+}
@@ -0,0 +1,128 @@
+package transport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// recording is a request and response pair saved with --record
+type recording struct {
+	Service    string                 `json:"service"`
+	Verb       string                 `json:"verb"`
+	Resource   string                 `json:"resource"`
+	Request    map[string]interface{} `json:"request"`
+	Response   map[string]interface{} `json:"response"`
+	RecordedAt time.Time              `json:"recorded_at"`
+}
+
+// recordingRequest returns the parameters identifying a call in a recording.
+// The flags are used as given, so that relative times like --since 24h replay the same response.
+func recordingRequest(options *FetchOptions) (map[string]interface{}, error) {
+	params, err := parseParameters(options)
+	if err != nil {
+		return nil, err
+	}
+	if options.Since != "" {
+		params["--since"] = options.Since
+	}
+	if options.Until != "" {
+		params["--until"] = options.Until
+	}
+	if options.AllPages {
+		params["--all-pages"] = true
+	}
+	return params, nil
+}
+
+// recordingPath returns the file of a call in the recording directory
+// Example:
+//
+//	<dir>/identity.list.Workspace.3f2a9c1b7d4e.json
+func recordingPath(dir, serviceName, verb, resourceName string, request map[string]interface{}) (string, error) {
+	key, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %v", err)
+	}
+	sum := sha256.Sum256(key)
+
+	name := fmt.Sprintf("%s.%s.%s.%s.json", serviceName, verb, resourceName, hex.EncodeToString(sum[:6]))
+	return filepath.Join(dir, name), nil
+}
+
+// saveRecording writes the request and response of a call to the recording directory.
+// The values of secret keys, e.g. passwords in the request and tokens in the response, are redacted
+// and the files are only readable by the user. The file name is derived from the request as given,
+// so that the call is still found on replay.
+func saveRecording(dir, serviceName, verb, resourceName string, request, response map[string]interface{}) error {
+	path, err := recordingPath(dir, serviceName, verb, resourceName, request)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(recording{
+		Service:    serviceName,
+		Verb:       verb,
+		Resource:   resourceName,
+		Request:    RedactSecrets(request).(map[string]interface{}),
+		Response:   RedactSecrets(response).(map[string]interface{}),
+		RecordedAt: time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recording: %v", err)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create recording directory: %v", err)
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// loadRecording reads the recorded response of a call from the recording directory
+func loadRecording(dir, serviceName, verb, resourceName string, request map[string]interface{}) (map[string]interface{}, error) {
+	path, err := recordingPath(dir, serviceName, verb, resourceName, request)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no recording of %s %s %s with these parameters in %s", serviceName, verb, resourceName, dir)
+		}
+		return nil, fmt.Errorf("failed to read recording: %v", err)
+	}
+
+	var rec recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to parse recording %s: %v", path, err)
+	}
+
+	return rec.Response, nil
+}
+
+// replayService prints the recorded response of a call instead of calling the service.
+// No connection is made, so minimal columns fall back to the default fields and --enrich is skipped.
+func replayService(serviceName, verb, resourceName string, options *FetchOptions) (map[string]interface{}, error) {
+	request, err := recordingRequest(options)
+	if err != nil {
+		return nil, err
+	}
+
+	respMap, err := loadRecording(options.Replay, serviceName, verb, resourceName, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.OutputFormat != "" {
+		if err := printResponse(nil, nil, respMap, options, serviceName, verb, resourceName, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return respMap, nil
+}
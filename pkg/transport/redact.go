@@ -0,0 +1,44 @@
+package transport
+
+import "strings"
+
+// RedactedValue replaces secrets in files written for later inspection, e.g. recordings and diagnostic bundles
+const RedactedValue = "<redacted>"
+
+// secretKeyParts mark the keys whose values are secrets
+var secretKeyParts = []string{"token", "password", "secret", "api_key", "access_key", "private_key", "credential"}
+
+// RedactSecrets returns a copy of a nested value with the values of secret keys replaced
+func RedactSecrets(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if isSecretKey(key) {
+				redacted[key] = RedactedValue
+				continue
+			}
+			redacted[key] = RedactSecrets(item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = RedactSecrets(item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// isSecretKey reports whether the value of a key is a secret
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
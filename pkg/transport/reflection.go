@@ -2,16 +2,13 @@ package transport
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"log"
-	"net/url"
 	"strings"
 
-	"github.com/jhump/protoreflect/grpcreflect"
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 )
 
 // ListGRPCServices retrieves a list of available gRPC services from the specified endpoint.
@@ -32,18 +29,7 @@ import (
 //	    log.Fatalf("Failed to get services: %v", err)
 //	}
 func ListGRPCServices(endpoint string) ([]string, error) {
-	parsedURL, err := url.Parse(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse endpoint: %w", err)
-	}
-
-	host := parsedURL.Hostname()
-	port := parsedURL.Port()
-	if port == "" {
-		port = "443"
-	}
-
-	conn, err := dialGRPC(endpoint, host, port)
+	conn, err := dialGRPC(endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -58,18 +44,7 @@ func ListGRPCServices(endpoint string) ([]string, error) {
 
 // GetGrpcConnection establishes a gRPC connection with the specified endpoint
 func GetGrpcConnection(endpoint string) (*grpc.ClientConn, error) {
-	parsedURL, err := url.Parse(endpoint)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse endpoint: %v", err)
-	}
-
-	host := parsedURL.Hostname()
-	port := parsedURL.Port()
-	if port == "" {
-		port = "443"
-	}
-
-	return dialGRPC(endpoint, host, port)
+	return dialGRPC(endpoint)
 }
 
 // CheckIdentityProxyAvailable checks if the given gRPC endpoint can be used as an identity proxy
@@ -92,19 +67,16 @@ func CheckIdentityProxyAvailable(endpoint string) (bool, error) {
 }
 
 // dialGRPC establishes a gRPC connection with the specified endpoint
-func dialGRPC(endpoint, host, port string) (*grpc.ClientConn, error) {
-	var opts []grpc.DialOption
-	if strings.HasPrefix(endpoint, "grpc+ssl://") {
-		tlsSetting := &tls.Config{
-			InsecureSkipVerify: false,
-		}
-		credential := credentials.NewTLS(tlsSetting)
-		opts = append(opts, grpc.WithTransportCredentials(credential))
-	} else {
+func dialGRPC(endpoint string) (*grpc.ClientConn, error) {
+	target, err := endpoints.ParseTarget(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse endpoint: %w", err)
+	}
+	if target.Insecure {
 		return nil, fmt.Errorf("unsupported scheme in endpoint: %s", endpoint)
 	}
 
-	conn, err := grpc.Dial(fmt.Sprintf("%s:%s", host, port), opts...)
+	conn, err := configs.Dial(target.HostPort, target.Credentials())
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial gRPC endpoint: %w", err)
 	}
@@ -114,7 +86,7 @@ func dialGRPC(endpoint, host, port string) (*grpc.ClientConn, error) {
 // listServices uses gRPC reflection to list available services
 func listServices(conn *grpc.ClientConn) ([]string, error) {
 	ctx := context.Background()
-	refClient := grpcreflect.NewClientV1Alpha(ctx, grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+	refClient := configs.NewReflectionClient(ctx, conn)
 	defer refClient.Reset()
 
 	services, err := refClient.ListServices()
@@ -0,0 +1,43 @@
+package transport
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+	"github.com/pterm/pterm"
+)
+
+// hotReload follows changes of the setting file and the token cache of the current environment
+// while a long running command is active, so that a token renewed in another terminal or an
+// edited endpoint is used without restarting. Every call reads the endpoint and token from the
+// files, so a change of the setting file only drops what is kept in memory: the transport, rate limit
+// and metrics settings and the shared connections, which the next call dials again. notify is called with the path of each change.
+// The returned function stops following changes.
+func hotReload(notify func(path string)) func() {
+	config, err := loadConfig()
+	if err != nil {
+		return func() {}
+	}
+
+	watcher, err := configs.WatchSettings(config.Environment)
+	if err != nil {
+		pterm.Warning.WithWriter(os.Stderr).Printf("Changes of the setting file are not followed: %v\n", err)
+		return func() {}
+	}
+
+	go func() {
+		for path := range watcher.Changes {
+			if filepath.Base(path) == "setting.yaml" {
+				configs.ReloadTransportSettings()
+				configs.ReloadMetricsSettings()
+				resetSharedConnections()
+			}
+			if notify != nil {
+				notify(path)
+			}
+		}
+	}()
+
+	return func() { watcher.Close() }
+}
@@ -0,0 +1,154 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+)
+
+// namePrefix marks a parameter value to be resolved from a name to an id
+const namePrefix = "name:"
+
+// identityResources are id fields whose resources live in the identity service
+var identityResources = map[string]string{
+	"domain_id":          "Domain",
+	"workspace_id":       "Workspace",
+	"project_id":         "Project",
+	"project_group_id":   "ProjectGroup",
+	"service_account_id": "ServiceAccount",
+	"trusted_account_id": "TrustedAccount",
+	"user_id":            "User",
+	"user_group_id":      "UserGroup",
+	"role_id":            "Role",
+}
+
+// otherReferences are reference fields whose resources live in a service other than the referring one
+var otherReferences = map[string][2]string{
+	"provider":     {"identity", "Provider"},
+	"secret_id":    {"secret", "Secret"},
+	"collector_id": {"inventory", "Collector"},
+	"plugin_id":    {"repository", "Plugin"},
+}
+
+// ReferencedResource returns the service and resource referred to by a field of a resource of the service.
+// Fields unknown to cfctl refer to a resource of the same service.
+// Example:
+//
+//	inventory, project_id -> identity, Project
+//	inventory, cloud_service_type_id -> inventory, CloudServiceType
+func ReferencedResource(serviceName, field string) (string, string) {
+	if resource, ok := identityResources[field]; ok {
+		return "identity", resource
+	}
+	if ref, ok := otherReferences[field]; ok {
+		return ref[0], ref[1]
+	}
+	return serviceName, idFieldToResource(field)
+}
+
+// IsReferenceField reports whether a field refers to another resource
+func IsReferenceField(field string) bool {
+	_, ok := otherReferences[field]
+	return ok || strings.HasSuffix(field, "_id")
+}
+
+// resolveNameReferences replaces parameter values like "name:payments-prod" with the id of the
+// resource with that name. Resources outside of identity are looked up in the called service.
+// Example:
+//
+//	-p project_id=name:payments-prod -> project_id=project-1a2b3c4d
+func resolveNameReferences(config *Config, serviceName string, params map[string]interface{}, resolver *endpoints.Resolver) error {
+	for key, value := range params {
+		strValue, ok := value.(string)
+		if !ok || !strings.HasPrefix(strValue, namePrefix) || !strings.HasSuffix(key, "_id") {
+			continue
+		}
+		name := strings.TrimPrefix(strValue, namePrefix)
+
+		refService, refResource := ReferencedResource(serviceName, key)
+		id, err := lookupIDByName(config, refService, refResource, key, name, resolver)
+		if err != nil {
+			return err
+		}
+		params[key] = id
+	}
+
+	return nil
+}
+
+// lookupIDByName finds the id of a resource by its name and fails if the name is ambiguous
+func lookupIDByName(config *Config, serviceName, resourceName, idField, name string, resolver *endpoints.Resolver) (string, error) {
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"filter": []interface{}{
+				map[string]interface{}{"k": "name", "v": name, "o": "eq"},
+			},
+			"only": []string{idField, "name"},
+		},
+	}
+	queryBytes, err := json.Marshal(query)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal name query: %v", err)
+	}
+
+	resp, err := fetchResponse(config, serviceName, "list", resourceName, &FetchOptions{JSONParameter: string(queryBytes)}, resolver)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s '%s': %v", resourceName, name, err)
+	}
+
+	var ids []string
+	if results, ok := resp["results"].([]interface{}); ok {
+		for _, result := range results {
+			if row, ok := result.(map[string]interface{}); ok {
+				if id, ok := row[idField].(string); ok {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("%s named '%s' not found", resourceName, name)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%s name '%s' is ambiguous, matches: %s", resourceName, name, strings.Join(ids, ", "))
+	}
+}
+
+// idFieldToResource converts an id field to its resource name
+// Example:
+//
+//	cloud_service_type_id -> CloudServiceType
+func idFieldToResource(field string) string {
+	parts := strings.Split(strings.TrimSuffix(field, "_id"), "_")
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// ResourceIDField converts a resource name to its id field
+// Example:
+//
+//	CloudServiceType -> cloud_service_type_id
+func ResourceIDField(resourceName string) string {
+	var sb strings.Builder
+	for i, r := range resourceName {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteString("_id")
+	return sb.String()
+}
@@ -0,0 +1,61 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+)
+
+// RevokeSession revokes the cached refresh and access tokens of a user environment on the server.
+// It reports whether any token was revoked, and returns false without an error
+// when the environment has no cached tokens or the identity service cannot revoke tokens.
+func RevokeSession(environment string) (bool, error) {
+	config, err := loadEnvironmentConfig(environment)
+	if err != nil {
+		return false, fmt.Errorf("failed to load config: %v", err)
+	}
+	if !strings.HasSuffix(config.Environment, "-user") {
+		return false, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, fmt.Errorf("failed to get user home directory: %v", err)
+	}
+	cacheDir := filepath.Join(home, ".cfctl", "cache", config.Environment)
+
+	resolver, err := endpoints.NewResolver(config.Environment, config.Environments[config.Environment].Endpoint, config.Environments[config.Environment].Endpoints)
+	if err != nil {
+		return false, err
+	}
+
+	revoked := false
+	// The refresh token goes first, the access token authenticates the calls
+	for _, name := range []string{"refresh_token", "access_token"} {
+		data, err := os.ReadFile(filepath.Join(cacheDir, name))
+		if err != nil {
+			continue
+		}
+
+		params, err := json.Marshal(map[string]string{"token": strings.TrimSpace(string(data))})
+		if err != nil {
+			return revoked, fmt.Errorf("failed to marshal revoke request: %v", err)
+		}
+
+		_, err = fetchResponse(config, "identity", "revoke", "Token", &FetchOptions{JSONParameter: string(params)}, resolver)
+		if errors.Is(err, errMethodNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return revoked, fmt.Errorf("failed to revoke %s: %v", name, err)
+		}
+		revoked = true
+	}
+
+	return revoked, nil
+}
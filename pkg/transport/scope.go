@@ -0,0 +1,141 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+	"github.com/pterm/pterm"
+)
+
+// switchWorkspace switches the token of the config to a token scoped to another workspace for --as-workspace.
+// The token is granted from the refresh token of the domain admin, so the call runs with the
+// privileges of the admin in that workspace, not with those of a member of the workspace.
+func switchWorkspace(config *Config, resolver *endpoints.Resolver, workspaceID string) error {
+	if !strings.HasSuffix(config.Environment, "-user") {
+		return fmt.Errorf("--as-workspace requires a user environment of a domain admin")
+	}
+
+	domainToken, err := adminToken(config, resolver)
+	if err != nil {
+		return fmt.Errorf("--as-workspace is only available to domain admins: %v", err)
+	}
+	claims, err := DecodeTokenClaims(domainToken)
+	if err != nil {
+		return err
+	}
+	domainID, _ := claims["did"].(string)
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get user home directory: %v", err)
+	}
+	refreshToken, err := os.ReadFile(filepath.Join(home, ".cfctl", "cache", config.Environment, "refresh_token"))
+	if err != nil {
+		return fmt.Errorf("no refresh token found. Please run 'cfctl login' first")
+	}
+
+	workspaceToken, err := grantScopedToken(config, resolver, strings.TrimSpace(string(refreshToken)), "WORKSPACE", domainID, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to grant token for workspace %s: %v", workspaceID, err)
+	}
+	env := config.Environments[config.Environment]
+	env.Token = workspaceToken
+	config.Environments[config.Environment] = env
+
+	pterm.Info.WithWriter(os.Stderr).Printf("Calling with your admin role in workspace %s\n", workspaceID)
+	return nil
+}
+
+// checkUserPermission checks whether the role bound to another user grants a call for --as-user.
+// SpaceONE has no API to issue a token on behalf of another user, so the call is not made: the role
+// of the user is read with the domain admin token and compared with the permission of the call.
+// Like --check-permission, a role without explicit permissions is not compared and only reported.
+func checkUserPermission(config *Config, resolver *endpoints.Resolver, userID, workspaceID, serviceName, verb, resourceName string) error {
+	if !strings.HasSuffix(config.Environment, "-user") {
+		return fmt.Errorf("--as-user requires a user environment of a domain admin")
+	}
+
+	domainToken, err := adminToken(config, resolver)
+	if err != nil {
+		return fmt.Errorf("--as-user is only available to domain admins: %v", err)
+	}
+	env := config.Environments[config.Environment]
+	env.Token = domainToken
+	config.Environments[config.Environment] = env
+
+	binding, err := userRoleBinding(config, resolver, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	if workspaceID == "" {
+		workspaceID, _ = binding["workspace_id"].(string)
+	}
+
+	roleID, _ := binding["role_id"].(string)
+	role, err := fetchResponse(config, "identity", "get", "Role", &FetchOptions{
+		Parameters: []string{fmt.Sprintf("role_id=%s", roleID)},
+	}, resolver)
+	if err != nil {
+		return fmt.Errorf("failed to get role %s of user %s: %v", roleID, userID, err)
+	}
+
+	identity := &Identity{UserID: userID, RoleID: roleID, WorkspaceID: workspaceID}
+	identity.RoleName, _ = role["name"].(string)
+	identity.RoleType, _ = role["role_type"].(string)
+	if permissions, ok := role["permissions"].([]interface{}); ok {
+		for _, p := range permissions {
+			identity.Permissions = append(identity.Permissions, fmt.Sprintf("%v", p))
+		}
+	}
+
+	scope := "the domain"
+	if workspaceID != "" {
+		scope = "workspace " + workspaceID
+	}
+	permission := PermissionString(serviceName, verb, resourceName)
+	if len(identity.Permissions) == 0 {
+		pterm.Warning.Printf("Role %s (%s) of user %s lists no permissions, so its role type decides whether %s is granted (the call was not made)\n",
+			identity.RoleName, identity.RoleType, userID, permission)
+		return nil
+	}
+	if !identity.HasPermission(permission) {
+		return fmt.Errorf("user %s with role %s in %s lacks permission %s", userID, identity.RoleName, scope, permission)
+	}
+
+	pterm.Success.Printf("User %s with role %s in %s has permission %s (the call was not made)\n",
+		userID, identity.RoleName, scope, permission)
+	return nil
+}
+
+// userRoleBinding returns the role binding of a user, in the workspace if given
+func userRoleBinding(config *Config, resolver *endpoints.Resolver, userID, workspaceID string) (map[string]interface{}, error) {
+	query := map[string]interface{}{"user_id": userID}
+	if workspaceID != "" {
+		query["workspace_id"] = workspaceID
+	}
+	params, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal role binding query: %v", err)
+	}
+
+	resp, err := fetchResponse(config, "identity", "list", "RoleBinding", &FetchOptions{JSONParameter: string(params)}, resolver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role bindings of user %s: %v", userID, err)
+	}
+
+	results, _ := resp["results"].([]interface{})
+	for _, result := range results {
+		if binding, ok := result.(map[string]interface{}); ok {
+			return binding, nil
+		}
+	}
+
+	if workspaceID != "" {
+		return nil, fmt.Errorf("user %s has no role in workspace %s", userID, workspaceID)
+	}
+	return nil, fmt.Errorf("user %s has no role binding", userID)
+}
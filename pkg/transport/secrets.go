@@ -0,0 +1,192 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SecretProvider resolves references to secrets kept outside of cfctl
+type SecretProvider interface {
+	// Resolve returns the secret value of a reference of the provider, e.g. secret/data/app#password
+	Resolve(ref string) (string, error)
+}
+
+// secretProviders are the providers of secret references by name
+var secretProviders = map[string]SecretProvider{
+	"env":   envSecretProvider{},
+	"file":  fileSecretProvider{},
+	"vault": vaultSecretProvider{},
+}
+
+// valueFromKey is the key of objects whose value is a secret reference
+// Example:
+//
+//	password:
+//	  valueFrom:
+//	    vault: secret/data/db#password
+const valueFromKey = "valueFrom"
+
+// RegisterSecretProvider makes a provider available as key of valueFrom objects
+func RegisterSecretProvider(name string, provider SecretProvider) {
+	secretProviders[name] = provider
+}
+
+// resolveSecretReferences replaces the secret references of the request parameters with their values,
+// so that credentials need not be written in plaintext manifests.
+// Only objects with a single valueFrom key are references, so that plain values which happen to
+// look like <provider>:<ref> are sent as given.
+// Example:
+//
+//	-j '{"password": {"valueFrom": {"vault": "secret/data/db#password"}}}'
+func resolveSecretReferences(params map[string]interface{}) error {
+	for key, value := range params {
+		resolved, err := resolveValueFrom(value)
+		if err != nil {
+			return fmt.Errorf("failed to resolve parameter '%s': %v", key, err)
+		}
+		params[key] = resolved
+	}
+	return nil
+}
+
+// resolveValueFrom replaces the objects with a valueFrom key in a value with their secrets
+func resolveValueFrom(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if from, ok := v[valueFromKey].(map[string]interface{}); ok && len(v) == 1 {
+			return resolveSecretSource(from)
+		}
+		for key, item := range v {
+			resolved, err := resolveValueFrom(item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = resolved
+		}
+		return v, nil
+
+	case []interface{}:
+		for i, item := range v {
+			resolved, err := resolveValueFrom(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+		return v, nil
+
+	default:
+		return value, nil
+	}
+}
+
+// resolveSecretSource resolves the single provider reference of a valueFrom object
+func resolveSecretSource(from map[string]interface{}) (string, error) {
+	if len(from) != 1 {
+		return "", fmt.Errorf("valueFrom needs exactly one provider, got %d", len(from))
+	}
+	for name, ref := range from {
+		provider, ok := secretProviders[name]
+		if !ok {
+			names := make([]string, 0, len(secretProviders))
+			for known := range secretProviders {
+				names = append(names, known)
+			}
+			sort.Strings(names)
+			return "", fmt.Errorf("unknown secret provider '%s', available: %s", name, strings.Join(names, ", "))
+		}
+		refString, ok := ref.(string)
+		if !ok {
+			return "", fmt.Errorf("reference of secret provider '%s' must be a string", name)
+		}
+		return provider.Resolve(refString)
+	}
+	return "", nil
+}
+
+// envSecretProvider reads secrets from environment variables
+type envSecretProvider struct{}
+
+func (envSecretProvider) Resolve(ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", ref)
+	}
+	return value, nil
+}
+
+// fileSecretProvider reads secrets from files, without the trailing newline
+type fileSecretProvider struct{}
+
+func (fileSecretProvider) Resolve(ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %v", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// vaultSecretProvider reads secrets from HashiCorp Vault with VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE.
+// References are <path>#<field>, the field may be left out for secrets with a single field.
+// Secrets of KV version 1 and 2 engines are supported.
+type vaultSecretProvider struct{}
+
+func (vaultSecretProvider) Resolve(ref string) (string, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	token := os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return "", fmt.Errorf("VAULT_ADDR and VAULT_TOKEN must be set to read secrets from Vault")
+	}
+	path, field, _ := strings.Cut(ref, "#")
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Vault request: %v", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from Vault: %v", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to read %s from Vault: %s", path, resp.Status)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode Vault response: %v", err)
+	}
+
+	// KV version 2 nests the secret in data.data
+	data := body.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
+	if field == "" {
+		if len(data) != 1 {
+			return "", fmt.Errorf("secret %s has %d fields, choose one with %s#<field>", path, len(data), path)
+		}
+		for _, value := range data {
+			return fmt.Sprint(value), nil
+		}
+	}
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("secret %s has no field %s", path, field)
+	}
+	return fmt.Sprint(value), nil
+}
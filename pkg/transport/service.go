@@ -3,33 +3,37 @@ package transport
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"os"
-	"os/signal"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
 
 	"github.com/atotto/clipboard"
 	"github.com/cloudforet-io/cfctl/pkg/configs"
 	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/cloudforet-io/cfctl/pkg/i18n"
 	"github.com/eiannone/keyboard"
 	"github.com/pterm/pterm"
 	"github.com/spf13/viper"
 
 	"google.golang.org/grpc/metadata"
 
+	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/dynamic"
-	"github.com/jhump/protoreflect/grpcreflect"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"gopkg.in/yaml.v3"
 )
@@ -38,6 +42,17 @@ type Environment struct {
 	Endpoint string `yaml:"endpoint"`
 	Proxy    string `yaml:"proxy"`
 	Token    string `yaml:"token"`
+	Mode     string `yaml:"mode"`
+	// ExtraHeaders are appended to the metadata of every call (e.g. for auth proxies)
+	ExtraHeaders map[string]string `yaml:"extra_headers"`
+	MaxRecvSize  string            `yaml:"max_recv_size"`
+	MaxSendSize  string            `yaml:"max_send_size"`
+	Compress     string            `yaml:"compress"`
+	Protoset     string            `yaml:"protoset"`
+	// Endpoints routes services to explicit endpoints instead of deriving them from the environment endpoint
+	Endpoints map[string]string `yaml:"endpoints"`
+	// Auth is the metadata carrying the token, the token key by default
+	Auth configs.AuthHeader `yaml:"-"`
 }
 
 type Config struct {
@@ -53,7 +68,18 @@ type FetchOptions struct {
 	APIVersion           string
 	OutputFormat         string
 	OutputFormatExplicit bool
+	NoHeaders            bool
+	Delimiter            string
+	SortedKeys           bool
+	ShowStats            bool
 	CopyToClipboard      bool
+	PasteFromClipboard   bool
+	CopyField            string
+	JQ                   string
+	FailIfEmpty          bool
+	FromGet              string
+	PatchFile            string
+	PatchArrays          string
 	SortBy               string
 	MinimalColumns       bool
 	Columns              string
@@ -61,10 +87,62 @@ type FetchOptions struct {
 	Page                 int
 	PageSize             int
 	NoPaging             bool
+	HumanizeTime         bool
+	RawValues            bool
+	Summary              bool
+	GroupBy              string
+	Aggregation          string
+	Enrich               []string
+	DropUnknownFields    bool
+	AllowUnknownParams   bool
+	CheckPermission      bool
+	Admin                bool
+	Headers              []string
+	MaxRecvSize          string
+	MaxSendSize          string
+	Compress             string
+	Protoset             string
+	NotifyCommand        string
+	NotifyDesktop        bool
+	Resume               bool
+	AllPages             bool
+	Concurrency          int
+	Since                string
+	Until                string
+	TimeField            string
+	Record               string
+	Replay               string
+	AsUser               string
+	AsWorkspace          string
+
+	// Requested marks the call the user asked for on the command line, as opposed to internal lookups
+	// and polls. Only then the hooks of the setting file run, valueFrom secrets are read and a missing id is picked.
+	Requested bool
+
+	hooks configs.Hooks // Hooks of the setting file, loaded for requested calls only
 }
 
 // FetchService handles the execution of gRPC commands for all services
 func FetchService(serviceName string, verb string, resourceName string, options *FetchOptions) (map[string]interface{}, error) {
+	if _, err := csvDelimiter(options); err != nil {
+		return nil, err
+	}
+
+	// Serve the response from a recording without connecting
+	if options.Replay != "" {
+		return replayService(serviceName, verb, resourceName, options)
+	}
+
+	// Identify the call by the arguments as given, before aliases and flags rewrite them
+	var recordRequest map[string]interface{}
+	recordVerb, recordResource := verb, resourceName
+	if options.Record != "" {
+		var err error
+		if recordRequest, err = recordingRequest(options); err != nil {
+			return nil, err
+		}
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user home directory: %v", err)
@@ -103,29 +181,19 @@ func FetchService(serviceName string, verb string, resourceName string, options
 			return nil, nil
 		} else if strings.HasSuffix(config.Environment, "-app") {
 			// App environment message
-			headerBox := pterm.DefaultBox.WithTitle("App Guide").
+			headerBox := pterm.DefaultBox.WithTitle(i18n.T("app_guide.title")).
 				WithTitleTopCenter().
 				WithRightPadding(4).
 				WithLeftPadding(4).
 				WithBoxStyle(pterm.NewStyle(pterm.FgLightCyan))
 
-			appTokenExplain := "Please create a Domain Admin App in SpaceONE Console.\n" +
-				"This requires Domain Admin privilege.\n\n" +
-				"Or Please create a Workspace App in SpaceONE Console.\n" +
-				"This requires Workspace Owner privilege."
+			appTokenExplain := i18n.T("app_token.explain")
 
 			pterm.Info.Printf("Using endpoint: %s\n", endpoint)
 			headerBox.Println(appTokenExplain)
 			fmt.Println()
 
-			steps := []string{
-				"1. Go to SpaceONE Console",
-				"2. Navigate to either 'Admin > App Page' or specific 'Workspace > App page'",
-				"3. Click 'Create' to create your App",
-				"4. Copy value of either 'client_secret' from Client ID or 'token' from Spacectl (CLI)",
-			}
-
-			yamlExample := pterm.DefaultBox.WithTitle("Config Example").
+			yamlExample := pterm.DefaultBox.WithTitle(i18n.T("config_example.title")).
 				WithTitleTopCenter().
 				WithRightPadding(4).
 				WithLeftPadding(4).
@@ -135,126 +203,97 @@ func FetchService(serviceName string, verb string, resourceName string, options
 					endpoint,
 					pterm.FgLightCyan.Sprint("YOUR_COPIED_TOKEN")))
 
-			instructionBox := pterm.DefaultBox.WithTitle("Required Steps").
+			instructionBox := pterm.DefaultBox.WithTitle(i18n.T("required_steps.title")).
 				WithTitleTopCenter().
 				WithRightPadding(4).
 				WithLeftPadding(4)
 
-			allSteps := append(steps,
-				fmt.Sprintf("5. Add the token under the proxy in your config file:\n%s", yamlExample),
-				"6. Run 'cfctl login' again")
-
-			instructionBox.Println(strings.Join(allSteps, "\n\n"))
+			instructionBox.Println(i18n.Steps(
+				i18n.T("steps.go_console"),
+				i18n.T("steps.navigate_app"),
+				i18n.T("steps.create_app"),
+				i18n.T("steps.copy_secret"),
+				i18n.T("steps.add_proxy_token", yamlExample),
+				i18n.T("steps.login_again")))
 
 		} else if strings.HasSuffix(config.Environment, "-user") {
 			// User environment message
-			headerBox := pterm.DefaultBox.WithTitle("Authentication Required").
+			headerBox := pterm.DefaultBox.WithTitle(i18n.T("auth_required.title")).
 				WithTitleTopCenter().
 				WithRightPadding(4).
 				WithLeftPadding(4).
 				WithBoxStyle(pterm.NewStyle(pterm.FgLightCyan))
 
-			authExplain := "Please login to SpaceONE Console first.\n" +
-				"This requires your SpaceONE credentials."
+			authExplain := i18n.T("auth_required.explain")
 
 			headerBox.Println(authExplain)
 			fmt.Println()
 
-			steps := []string{
-				"1. Run 'cfctl login'",
-				"2. Enter your credentials when prompted",
-				"3. Select your scope",
-				"4. Try your command again",
-			}
-
-			instructionBox := pterm.DefaultBox.WithTitle("Required Steps").
+			instructionBox := pterm.DefaultBox.WithTitle(i18n.T("required_steps.title")).
 				WithTitleTopCenter().
 				WithRightPadding(4).
 				WithLeftPadding(4)
 
-			instructionBox.Println(strings.Join(steps, "\n\n"))
+			instructionBox.Println(i18n.Steps(
+				i18n.T("steps.run_login"),
+				i18n.T("steps.enter_credentials"),
+				i18n.T("steps.select_scope"),
+				i18n.T("steps.retry")))
 		}
 
 		return nil, nil
 	}
 
-	// Get hostPort based on environment prefix
-	var hostPort string
-	var apiEndpoint string
-	var identityEndpoint string
-	var hasIdentityService bool
-	if strings.HasPrefix(config.Environments[config.Environment].Endpoint, "grpc://") {
-		hostPort = strings.TrimPrefix(config.Environments[config.Environment].Endpoint, "grpc://")
-	} else {
-		apiEndpoint, err = configs.GetAPIEndpoint(config.Environments[config.Environment].Endpoint)
-		if err != nil {
-			pterm.Error.Printf("Failed to get API endpoint: %v\n", err)
-			os.Exit(1)
-		}
-		// Get identity service endpoint
-		identityEndpoint, hasIdentityService, err = configs.GetIdentityEndpoint(apiEndpoint)
-		if err != nil {
-			pterm.Error.Printf("Failed to get identity endpoint: %v\n", err)
-			os.Exit(1)
-		}
-
-		if !hasIdentityService {
-			urlParts := strings.Split(apiEndpoint, "//")
-			if len(urlParts) != 2 {
-				return nil, fmt.Errorf("invalid API endpoint format: %s", apiEndpoint)
-			}
-
-			domainParts := strings.Split(urlParts[1], ".")
-			if len(domainParts) > 0 {
-				port := extractPortFromParts(domainParts)
-				if strings.Contains(domainParts[len(domainParts)-1], ":") {
-					parts := strings.Split(domainParts[len(domainParts)-1], ":")
-					domainParts[len(domainParts)-1] = parts[0]
-				}
+	// Resolve the service endpoint
+	resolver, err := endpoints.NewResolver(config.Environment, config.Environments[config.Environment].Endpoint, config.Environments[config.Environment].Endpoints)
+	if err != nil {
+		return nil, err
+	}
+	target, err := resolver.Resolve(serviceName)
+	if err != nil {
+		return nil, err
+	}
 
-				domainParts[0] = format.ConvertServiceName(serviceName)
-				hostPort = strings.Join(domainParts, ".") + port
-			}
-		} else {
-			trimmedEndpoint := strings.TrimPrefix(identityEndpoint, "grpc+ssl://")
-			parts := strings.Split(trimmedEndpoint, ".")
-			if len(parts) < 4 {
-				return nil, fmt.Errorf("invalid endpoint format: %s", trimmedEndpoint)
-			}
+	// Discover methods from a protoset instead of server reflection
+	if options.Protoset != "" {
+		env := config.Environments[config.Environment]
+		env.Protoset = options.Protoset
+		config.Environments[config.Environment] = env
+	}
 
-			// Replace 'identity' with the converted service name
-			parts[0] = format.ConvertServiceName(serviceName)
-			hostPort = strings.Join(parts, ".")
+	// Switch to a domain scope token in admin mode
+	if isAdminMode(config, options) {
+		adminTokenValue, err := adminToken(config, resolver)
+		if err != nil {
+			return nil, err
 		}
+		env := config.Environments[config.Environment]
+		env.Token = adminTokenValue
+		config.Environments[config.Environment] = env
+		options.Admin = true
 	}
 
 	// Configure gRPC connection
-	var conn *grpc.ClientConn
-	if strings.HasPrefix(config.Environments[config.Environment].Endpoint, "grpc://") {
-		hostPort := strings.TrimPrefix(config.Environments[config.Environment].Endpoint, "grpc://")
-		// For local environment, use insecure connection
-		conn, err = grpc.Dial(hostPort, grpc.WithInsecure())
-		if err != nil {
-			pterm.Error.Printf("Cannot connect to local gRPC server (%s)\n", hostPort)
+	conn, closeConn, err := dialTarget(target)
+	if err != nil {
+		if strings.HasPrefix(config.Environments[config.Environment].Endpoint, "grpc://") {
+			pterm.Error.Printf("Cannot connect to local gRPC server (%s)\n", target.HostPort)
 			pterm.Info.Println("Please check if your gRPC server is running")
 			return nil, fmt.Errorf("failed to connect to local server: %v", err)
 		}
-	} else {
-		// Existing SSL connection logic for non-local environments
-		tlsConfig := &tls.Config{
-			InsecureSkipVerify: false,
-		}
-		creds := credentials.NewTLS(tlsConfig)
-		conn, err = grpc.Dial(hostPort, grpc.WithTransportCredentials(creds))
-		if err != nil {
-			return nil, fmt.Errorf("connection failed: %v", err)
-		}
+		return nil, fmt.Errorf("connection failed: %v", err)
 	}
-	defer conn.Close()
+	defer closeConn()
 
-	// Create reflection client for both service calls and minimal fields detection
-	ctx := metadata.AppendToOutgoingContext(context.Background(), "token", config.Environments[config.Environment].Token)
-	refClient := grpcreflect.NewClient(ctx, grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+	// Create descriptor source for both service calls and minimal fields detection
+	ctx, err := outgoingContext(config, options)
+	if err != nil {
+		return nil, err
+	}
+	refClient, err := newDescriptorSource(ctx, conn, config)
+	if err != nil {
+		return nil, err
+	}
 	defer refClient.Reset()
 
 	// Check for alias
@@ -286,9 +325,44 @@ func FetchService(serviceName string, verb string, resourceName string, options
 						APIVersion:           options.APIVersion,
 						OutputFormat:         options.OutputFormat,
 						OutputFormatExplicit: options.OutputFormatExplicit,
+						NoHeaders:            options.NoHeaders,
+						Delimiter:            options.Delimiter,
+						SortedKeys:           options.SortedKeys,
+						ShowStats:            options.ShowStats,
 						CopyToClipboard:      options.CopyToClipboard,
+						PasteFromClipboard:   options.PasteFromClipboard,
+						CopyField:            options.CopyField,
+						JQ:                   options.JQ,
+						FailIfEmpty:          options.FailIfEmpty,
+						FromGet:              options.FromGet,
+						PatchFile:            options.PatchFile,
+						PatchArrays:          options.PatchArrays,
+						AllowUnknownParams:   options.AllowUnknownParams,
 						MinimalColumns:       false, // Always show all columns for alias
 						PageSize:             15,    // Default page size
+						HumanizeTime:         options.HumanizeTime,
+						RawValues:            options.RawValues,
+						Summary:              options.Summary,
+						GroupBy:              options.GroupBy,
+						Aggregation:          options.Aggregation,
+						Enrich:               options.Enrich,
+						CheckPermission:      options.CheckPermission,
+						Admin:                options.Admin,
+						Headers:              options.Headers,
+						MaxRecvSize:          options.MaxRecvSize,
+						MaxSendSize:          options.MaxSendSize,
+						Compress:             options.Compress,
+						Protoset:             options.Protoset,
+						AllPages:             options.AllPages,
+						Concurrency:          options.Concurrency,
+						Since:                options.Since,
+						Until:                options.Until,
+						TimeField:            options.TimeField,
+						Record:               options.Record,
+						Replay:               options.Replay,
+						AsUser:               options.AsUser,
+						AsWorkspace:          options.AsWorkspace,
+						Requested:            options.Requested,
 					}
 
 					options = newOptions
@@ -297,8 +371,53 @@ func FetchService(serviceName string, verb string, resourceName string, options
 		}
 	}
 
+	// Accept resource names in any case, with underscores or in plural
+	resourceName = normalizeResourceName(refClient, serviceName, resourceName)
+
+	// Filter list results by a time range of created_at (or --time-field)
+	if verb == "list" && (options.Since != "" || options.Until != "") {
+		options, err = timeRangeOptions(options, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Check the call against the role of another user, without making it
+	if options.AsUser != "" {
+		return nil, checkUserPermission(config, resolver, options.AsUser, options.AsWorkspace, serviceName, verb, resourceName)
+	}
+
+	// Call with the admin role in another workspace
+	if options.AsWorkspace != "" {
+		if err := switchWorkspace(config, resolver, options.AsWorkspace); err != nil {
+			return nil, err
+		}
+	}
+
+	// Fail fast when the role of the caller lacks the permission of the call
+	if options.CheckPermission {
+		if err := checkPermission(serviceName, verb, resourceName); err != nil {
+			return nil, err
+		}
+	}
+
+	// Run the hooks of the setting file around the requested call only, not around lookups
+	if options.Requested {
+		options.hooks = configs.LoadHooks()
+	}
+
+	// Write large exports page by page instead of holding every result in memory
+	if canStreamResults(verb, options) {
+		return streamAllPages(config, serviceName, resourceName, options, resolver)
+	}
+
 	// Call the service
-	jsonBytes, err := fetchJSONResponse(config, serviceName, verb, resourceName, options, apiEndpoint, identityEndpoint, hasIdentityService)
+	var respMap map[string]interface{}
+	if verb == "list" && options.AllPages {
+		respMap, err = fetchAllPages(config, serviceName, resourceName, options, resolver)
+	} else {
+		respMap, err = fetchResponse(config, serviceName, verb, resourceName, options, resolver)
+	}
 	if err != nil {
 		// Check if the error is about missing required parameters
 		if strings.Contains(err.Error(), "ERROR_REQUIRED_PARAMETER") {
@@ -311,82 +430,190 @@ func FetchService(serviceName string, verb string, resourceName string, options
 		return nil, err
 	}
 
-	// Unmarshal JSON bytes to a map
-	var respMap map[string]interface{}
-	if err = json.Unmarshal(jsonBytes, &respMap); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal JSON: %v", err)
+	if err := runPostRequestHook(options.hooks, serviceName, verb, resourceName, respMap); err != nil {
+		pterm.Warning.Println(err)
+	}
+
+	if options.Record != "" {
+		if err := saveRecording(options.Record, serviceName, recordVerb, recordResource, recordRequest, respMap); err != nil {
+			pterm.Warning.Printf("Failed to record response: %v\n", err)
+		}
 	}
 
 	// Print the data if not in watch mode
 	if options.OutputFormat != "" {
-		if options.SortBy != "" && verb == "list" {
-			if results, ok := respMap["results"].([]interface{}); ok {
-				// Sort the results by the specified field
-				sort.Slice(results, func(i, j int) bool {
-					iMap := results[i].(map[string]interface{})
-					jMap := results[j].(map[string]interface{})
-
-					iVal, iOk := iMap[options.SortBy]
-					jVal, jOk := jMap[options.SortBy]
-
-					// Handle cases where the field doesn't exist
-					if !iOk && !jOk {
-						return false
-					} else if !iOk {
-						return false
-					} else if !jOk {
-						return true
-					}
+		if err := printResponse(config, resolver, respMap, options, serviceName, verb, resourceName, refClient); err != nil {
+			return nil, err
+		}
+	}
 
-					// Compare based on type
-					switch v := iVal.(type) {
-					case string:
-						return v < jVal.(string)
-					case float64:
-						return v < jVal.(float64)
-					case bool:
-						return v && !jVal.(bool)
-					default:
-						return false
-					}
-				})
-				respMap["results"] = results
+	if options.FailIfEmpty && verb == "list" {
+		if results, ok := respMap["results"].([]interface{}); ok && len(results) == 0 {
+			return nil, ErrEmptyResults
+		}
+	}
+
+	return respMap, nil
+}
+
+// printResponse enriches, aggregates, sorts and filters the response before printing it.
+// Enrichment is skipped without a resolver, e.g. when replaying a recording.
+func printResponse(config *Config, resolver *endpoints.Resolver, respMap map[string]interface{}, options *FetchOptions, serviceName, verb, resourceName string, refClient DescriptorSource) error {
+	if len(options.Enrich) > 0 && verb == "list" && resolver != nil {
+		if results, ok := respMap["results"].([]interface{}); ok {
+			if err := enrichResults(config, results, options.Enrich, resolver); err != nil {
+				return err
 			}
 		}
+	}
 
-		if options.Rows > 0 && verb == "list" {
-			if results, ok := respMap["results"].([]interface{}); ok {
-				if len(results) > options.Rows {
-					respMap["results"] = results[:options.Rows]
-				}
+	// Aggregate results before sorting so that aggregated values can be sorted
+	if options.GroupBy != "" && verb == "list" {
+		if results, ok := respMap["results"].([]interface{}); ok {
+			aggregated, err := format.AggregateResults(results, options.GroupBy, options.Aggregation)
+			if err != nil {
+				return err
+			}
+			respMap = map[string]interface{}{
+				"results":     aggregated,
+				"total_count": len(aggregated),
 			}
 		}
+	}
 
-		// Filter columns if specified
-		if options.Columns != "" && verb == "list" {
-			if results, ok := respMap["results"].([]interface{}); ok {
-				columns := strings.Split(options.Columns, ",")
-				filteredResults := make([]interface{}, len(results))
-
-				for i, result := range results {
-					if resultMap, ok := result.(map[string]interface{}); ok {
-						filteredMap := make(map[string]interface{})
-						for _, col := range columns {
-							if val, exists := resultMap[strings.TrimSpace(col)]; exists {
-								filteredMap[strings.TrimSpace(col)] = val
-							}
-						}
-						filteredResults[i] = filteredMap
-					}
+	if options.SortBy != "" && verb == "list" {
+		if results, ok := respMap["results"].([]interface{}); ok {
+			// Sort the results by the specified field
+			sort.Slice(results, func(i, j int) bool {
+				iMap := results[i].(map[string]interface{})
+				jMap := results[j].(map[string]interface{})
+
+				iVal, iOk := iMap[options.SortBy]
+				jVal, jOk := jMap[options.SortBy]
+
+				// Handle cases where the field doesn't exist
+				if !iOk && !jOk {
+					return false
+				} else if !iOk {
+					return false
+				} else if !jOk {
+					return true
+				}
+
+				// Compare based on type
+				switch v := iVal.(type) {
+				case string:
+					return v < jVal.(string)
+				case float64:
+					return v < jVal.(float64)
+				case bool:
+					return v && !jVal.(bool)
+				default:
+					return false
 				}
-				respMap["results"] = filteredResults
+			})
+			respMap["results"] = results
+		}
+	}
+
+	if options.Rows > 0 && verb == "list" {
+		if results, ok := respMap["results"].([]interface{}); ok {
+			if len(results) > options.Rows {
+				respMap["results"] = results[:options.Rows]
 			}
 		}
+	}
 
-		printData(respMap, options, serviceName, verb, resourceName, refClient)
+	// Filter columns if specified
+	if options.Columns != "" && verb == "list" {
+		if results, ok := respMap["results"].([]interface{}); ok {
+			respMap["results"] = filterColumns(results, options.Columns)
+		}
 	}
 
-	return respMap, nil
+	// Transform the response with the jq expression before it is formatted
+	if options.JQ != "" {
+		outputs, err := format.ApplyJQ(options.JQ, respMap)
+		if err != nil {
+			return err
+		}
+		if object, ok := jqObject(outputs); ok {
+			respMap = object
+		} else if options.OutputFormat == "json" || options.OutputFormat == "yaml" {
+			return printJQOutputs(outputs, options.OutputFormat)
+		} else {
+			respMap = map[string]interface{}{"results": jqRows(outputs)}
+		}
+	}
+
+	return printData(respMap, options, serviceName, verb, resourceName, refClient)
+}
+
+// jqObject returns the output of a jq expression when it is a single object
+func jqObject(outputs []interface{}) (map[string]interface{}, bool) {
+	if len(outputs) != 1 {
+		return nil, false
+	}
+	object, ok := outputs[0].(map[string]interface{})
+	return object, ok
+}
+
+// jqRows converts the outputs of a jq expression to result rows, scalars become a value column
+func jqRows(outputs []interface{}) []interface{} {
+	rows := make([]interface{}, len(outputs))
+	for i, output := range outputs {
+		if _, ok := output.(map[string]interface{}); ok {
+			rows[i] = output
+		} else {
+			rows[i] = map[string]interface{}{"value": output}
+		}
+	}
+	return rows
+}
+
+// printJQOutputs prints the outputs of a jq expression one after another like jq does
+func printJQOutputs(outputs []interface{}, outputFormat string) error {
+	for i, output := range outputs {
+		if outputFormat == "yaml" {
+			if i > 0 {
+				fmt.Println("---")
+			}
+			doc, err := printYAMLDoc(output)
+			if err != nil {
+				return err
+			}
+			fmt.Print(doc)
+			continue
+		}
+		dataBytes, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal jq output to JSON: %v", err)
+		}
+		fmt.Println(string(dataBytes))
+	}
+	return nil
+}
+
+// filterColumns keeps only the given comma separated columns of each result.
+// Nested paths (e.g. data.os.os_distro) are kept as flat keys.
+func filterColumns(results []interface{}, columns string) []interface{} {
+	columnList := strings.Split(columns, ",")
+	filteredResults := make([]interface{}, len(results))
+
+	for i, result := range results {
+		if resultMap, ok := result.(map[string]interface{}); ok {
+			filteredMap := make(map[string]interface{})
+			for _, col := range columnList {
+				col = strings.TrimSpace(col)
+				if val, exists := format.GetValueByPath(resultMap, col); exists {
+					filteredMap[col] = val
+				}
+			}
+			filteredResults[i] = filteredMap
+		}
+	}
+
+	return filteredResults
 }
 
 // extractParameterName extracts the parameter name from the error message
@@ -401,22 +628,6 @@ func extractParameterName(errMsg string) string {
 	return ""
 }
 
-func extractPortFromParts(parts []string) string {
-	if len(parts) == 0 {
-		return ":443"
-	}
-
-	lastPart := parts[len(parts)-1]
-	if strings.Contains(lastPart, ":") {
-		portParts := strings.Split(lastPart, ":")
-		if len(portParts) == 2 {
-			return ":" + portParts[1]
-		}
-	}
-
-	return ":443"
-}
-
 // promptForParameter prompts the user to enter a value for the given parameter
 func promptForParameter(paramName string) (string, error) {
 	prompt := fmt.Sprintf("Please enter value for '%s'", paramName)
@@ -428,6 +639,11 @@ func promptForParameter(paramName string) (string, error) {
 }
 
 func loadConfig() (*Config, error) {
+	return loadEnvironmentConfig("")
+}
+
+// loadEnvironmentConfig loads the config of an environment, the current one if environment is empty
+func loadEnvironmentConfig(environment string) (*Config, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get home directory: %v", err)
@@ -442,7 +658,10 @@ func loadConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %v", err)
 	}
 
-	currentEnv := mainV.GetString("environment")
+	currentEnv := environment
+	if currentEnv == "" {
+		currentEnv = mainV.GetString("environment")
+	}
 	if currentEnv == "" {
 		return nil, fmt.Errorf("no environment set in config")
 	}
@@ -452,6 +671,18 @@ func loadConfig() (*Config, error) {
 		Endpoint: mainV.GetString(fmt.Sprintf("environments.%s.endpoint", currentEnv)),
 		Proxy:    mainV.GetString(fmt.Sprintf("environments.%s.proxy", currentEnv)),
 		Token:    mainV.GetString(fmt.Sprintf("environments.%s.token", currentEnv)),
+		Mode:     mainV.GetString(fmt.Sprintf("environments.%s.mode", currentEnv)),
+
+		ExtraHeaders: mainV.GetStringMapString(fmt.Sprintf("environments.%s.extra_headers", currentEnv)),
+		MaxRecvSize:  mainV.GetString(fmt.Sprintf("environments.%s.max_recv_size", currentEnv)),
+		MaxSendSize:  mainV.GetString(fmt.Sprintf("environments.%s.max_send_size", currentEnv)),
+		Compress:     mainV.GetString(fmt.Sprintf("environments.%s.compress", currentEnv)),
+		Endpoints:    mainV.GetStringMapString(fmt.Sprintf("environments.%s.endpoints", currentEnv)),
+		Protoset:     mainV.GetString(fmt.Sprintf("environments.%s.protoset", currentEnv)),
+		Auth: configs.AuthHeader{
+			Name:   mainV.GetString(fmt.Sprintf("environments.%s.auth_header", currentEnv)),
+			Scheme: mainV.GetString(fmt.Sprintf("environments.%s.auth_scheme", currentEnv)),
+		},
 	}
 
 	// Handle token based on environment type
@@ -482,10 +713,17 @@ func loadConfig() (*Config, error) {
 	}, nil
 }
 
-func fetchJSONResponse(config *Config, serviceName string, verb string, resourceName string, options *FetchOptions, apiEndpoint, identityEndpoint string, hasIdentityService bool) ([]byte, error) {
-	var conn *grpc.ClientConn
-	var err error
-	var hostPort string
+// ErrEmptyResults is returned for lists without results when --fail-if-empty is set
+var ErrEmptyResults = errors.New("no resources found")
+
+// errMethodNotFound is returned by fetchResponse when the service has no such method
+var errMethodNotFound = errors.New("method not found")
+
+func fetchResponse(config *Config, serviceName string, verb string, resourceName string, options *FetchOptions, resolver *endpoints.Resolver) (map[string]interface{}, error) {
+	callOptions, err := defaultCallOptions(config, options)
+	if err != nil {
+		return nil, err
+	}
 
 	if verb == "list" && options.Page > 0 {
 		options.Parameters = append(options.Parameters,
@@ -493,95 +731,28 @@ func fetchJSONResponse(config *Config, serviceName string, verb string, resource
 			fmt.Sprintf("page_size=%d", options.PageSize))
 	}
 
-	if strings.HasPrefix(config.Environments[config.Environment].Endpoint, "grpc://") {
-		hostPort = strings.TrimPrefix(config.Environments[config.Environment].Endpoint, "grpc://")
-		conn, err = grpc.Dial(hostPort, grpc.WithInsecure(),
-			grpc.WithDefaultCallOptions(
-				grpc.MaxCallRecvMsgSize(10*1024*1024),
-				grpc.MaxCallSendMsgSize(10*1024*1024),
-			))
-		if err != nil {
-			return nil, fmt.Errorf("connection failed: unable to connect to local server: %v", err)
-		}
-	} else {
-		if !hasIdentityService {
-			// Handle gRPC+SSL protocol directly
-			if strings.HasPrefix(config.Environments[config.Environment].Endpoint, "grpc+ssl://") {
-				endpoint := config.Environments[config.Environment].Endpoint
-				parts := strings.Split(endpoint, "/")
-				endpoint = strings.Join(parts[:len(parts)-1], "/")
-				parts = strings.Split(endpoint, "://")
-				if len(parts) != 2 {
-					return nil, fmt.Errorf("invalid endpoint format: %s", endpoint)
-				}
-
-				hostParts := strings.Split(parts[1], ".")
-				if len(hostParts) < 4 {
-					return nil, fmt.Errorf("invalid endpoint format: %s", endpoint)
-				}
-
-				// Replace service name
-				hostParts[0] = format.ConvertServiceName(serviceName)
-				hostPort = strings.Join(hostParts, ".")
-			} else {
-				// Original HTTP/HTTPS handling
-				urlParts := strings.Split(apiEndpoint, "//")
-				if len(urlParts) != 2 {
-					return nil, fmt.Errorf("invalid API endpoint format: %s", apiEndpoint)
-				}
-
-				domainParts := strings.Split(urlParts[1], ".")
-				if len(domainParts) > 0 {
-					port := extractPortFromParts(domainParts)
-					if strings.Contains(domainParts[len(domainParts)-1], ":") {
-						parts := strings.Split(domainParts[len(domainParts)-1], ":")
-						domainParts[len(domainParts)-1] = parts[0]
-					}
-
-					domainParts[0] = format.ConvertServiceName(serviceName)
-					hostPort = strings.Join(domainParts, ".") + port
-				}
-			}
-		} else {
-			trimmedEndpoint := strings.TrimPrefix(identityEndpoint, "grpc+ssl://")
-			parts := strings.Split(trimmedEndpoint, ".")
-			if len(parts) < 4 {
-				return nil, fmt.Errorf("invalid endpoint format: %s", trimmedEndpoint)
-			}
-
-			// Replace 'identity' with the converted service name
-			parts[0] = format.ConvertServiceName(serviceName)
-			hostPort = strings.Join(parts, ".")
-		}
-
-		tlsConfig := &tls.Config{
-			InsecureSkipVerify: false,
-		}
-		creds := credentials.NewTLS(tlsConfig)
-
-		conn, err = grpc.Dial(hostPort,
-			grpc.WithTransportCredentials(creds),
-			grpc.WithDefaultCallOptions(
-				grpc.MaxCallRecvMsgSize(10*1024*1024),
-				grpc.MaxCallSendMsgSize(10*1024*1024),
-			))
-		if err != nil {
-			return nil, fmt.Errorf("connection failed: unable to connect to %s: %v", hostPort, err)
-		}
+	target, err := resolver.Resolve(serviceName)
+	if err != nil {
+		return nil, err
 	}
 
-	defer func(conn *grpc.ClientConn) {
-		err := conn.Close()
-		if err != nil {
-
-		}
-	}(conn)
+	conn, closeConn, err := dialTarget(target)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: unable to connect to %s: %v", target.HostPort, err)
+	}
+	defer closeConn()
 
-	ctx := metadata.AppendToOutgoingContext(context.Background(), "token", config.Environments[config.Environment].Token)
-	refClient := grpcreflect.NewClient(ctx, grpc_reflection_v1alpha.NewServerReflectionClient(conn))
+	ctx, err := outgoingContext(config, options)
+	if err != nil {
+		return nil, err
+	}
+	refClient, err := newDescriptorSource(ctx, conn, config)
+	if err != nil {
+		return nil, err
+	}
 	defer refClient.Reset()
 
-	fullServiceName, err := discoverService(refClient, serviceName, resourceName)
+	fullServiceName, err := discoverService(refClient, serviceName, resourceName, options.APIVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover service: %v", err)
 	}
@@ -593,7 +764,7 @@ func fetchJSONResponse(config *Config, serviceName string, verb string, resource
 
 	methodDesc := serviceDesc.FindMethodByName(verb)
 	if methodDesc == nil {
-		return nil, fmt.Errorf("method not found: %s", verb)
+		return nil, fmt.Errorf("%w: %s%s", errMethodNotFound, verb, notFoundHint(verb, methodNames(serviceDesc), "verbs"))
 	}
 
 	// Create request and response messages
@@ -606,6 +777,84 @@ func fetchJSONResponse(config *Config, serviceName string, verb string, resource
 		return nil, err
 	}
 
+	// Resolve name:<name> values to resource ids
+	if err := resolveNameReferences(config, serviceName, inputParams, resolver); err != nil {
+		return nil, err
+	}
+
+	// Read the secrets referenced by valueFrom objects of the requested call, never of internal lookups
+	if options.Requested {
+		if err := resolveSecretReferences(inputParams); err != nil {
+			return nil, err
+		}
+	}
+
+	// Send the current resource, patched and with the given values over it, for APIs which require full objects
+	if options.FromGet != "" || options.PatchFile != "" {
+		id := options.FromGet
+		if id == "" {
+			id, _ = inputParams[ResourceIDField(resourceName)].(string)
+		}
+		if id == "" {
+			return nil, fmt.Errorf("--patch-file requires the id of the resource with -p %s=<id> or --from-get", ResourceIDField(resourceName))
+		}
+
+		request, err := currentResource(config, serviceName, verb, resourceName, id, methodDesc.GetInputType(), options, resolver)
+		if err != nil {
+			return nil, err
+		}
+		if options.PatchFile != "" {
+			switch options.PatchArrays {
+			case "", patchArraysReplace, patchArraysAppend, patchArraysMerge:
+			default:
+				return nil, fmt.Errorf("unsupported --patch-arrays %s, use replace, append or merge", options.PatchArrays)
+			}
+			patch, err := loadPatch(options.PatchFile)
+			if err != nil {
+				return nil, err
+			}
+			request = mergePatch(request, patch, options.PatchArrays).(map[string]interface{})
+		}
+		for key, value := range inputParams {
+			request[key] = value
+		}
+		inputParams = request
+	}
+
+	// Pick the resource of the call when its id was not given
+	if options.Requested {
+		if err := pickResourceID(config, serviceName, verb, resourceName, methodDesc.GetInputType(), inputParams, resolver); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reject parameters that are not part of the request message, so that typos are not sent silently.
+	// They are dropped instead for edits (e.g. immutable fields on update) and with --strict=false.
+	inputType := methodDesc.GetInputType()
+	for key := range inputParams {
+		if inputType.FindFieldByName(key) != nil || inputType.FindFieldByJSONName(key) != nil {
+			continue
+		}
+		if !options.DropUnknownFields && !options.AllowUnknownParams {
+			return nil, fmt.Errorf("unknown parameter '%s' for %s %s%s", key, verb, resourceName,
+				notFoundHint(key, fieldNames(inputType), "fields"))
+		}
+		pterm.Warning.Printf("Skipping field '%s' which is not accepted by %s\n", key, verb)
+		delete(inputParams, key)
+	}
+
+	if options.Requested {
+		inputParams, err = runPreRequestHook(options.hooks, serviceName, verb, resourceName, inputParams)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Accept enum values by number or in any case
+	if err := normalizeEnumParams(methodDesc.GetInputType(), inputParams); err != nil {
+		return nil, err
+	}
+
 	// Marshal the inputParams map to JSON
 	jsonBytes, err := json.Marshal(inputParams)
 	if err != nil {
@@ -618,6 +867,7 @@ func fetchJSONResponse(config *Config, serviceName string, verb string, resource
 	}
 
 	fullMethod := fmt.Sprintf("/%s/%s", fullServiceName, verb)
+	recordLastRequest(ctx, fullMethod, target.HostPort, inputParams)
 
 	// Handle client streaming
 	if !methodDesc.IsClientStreaming() && methodDesc.IsServerStreaming() {
@@ -627,7 +877,8 @@ func fetchJSONResponse(config *Config, serviceName string, verb string, resource
 			ClientStreams: false,
 		}
 
-		stream, err := conn.NewStream(ctx, streamDesc, fullMethod)
+		stats := startCallStats(fullMethod, target.HostPort)
+		stream, err := conn.NewStream(ctx, streamDesc, fullMethod, callOptions...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create stream: %v", err)
 		}
@@ -640,7 +891,7 @@ func fetchJSONResponse(config *Config, serviceName string, verb string, resource
 			return nil, fmt.Errorf("failed to close send: %v", err)
 		}
 
-		var allResponses []string
+		var allResponses []interface{}
 		for {
 			respMsg := dynamic.NewMessage(methodDesc.GetOutputType())
 			err := stream.RecvMsg(respMsg)
@@ -648,97 +899,153 @@ func fetchJSONResponse(config *Config, serviceName string, verb string, resource
 				break
 			}
 			if err != nil {
+				if options.ShowStats {
+					stats.print(err)
+				}
 				return nil, fmt.Errorf("failed to receive response: %v", err)
 			}
 
-			jsonBytes, err := respMsg.MarshalJSON()
+			size := 0
+			if options.ShowStats {
+				size = messageSize(respMsg)
+			}
+			resp, err := decodeMessage(respMsg)
 			if err != nil {
-				return nil, fmt.Errorf("failed to marshal response: %v", err)
+				return nil, err
 			}
+			stats.addResponse(size, resp)
+
+			allResponses = append(allResponses, resp)
+		}
 
-			allResponses = append(allResponses, string(jsonBytes))
+		if options.ShowStats {
+			stats.print(nil)
 		}
 
 		if len(allResponses) == 1 {
-			return []byte(allResponses[0]), nil
+			return allResponses[0].(map[string]interface{}), nil
 		}
 
-		combinedJSON := fmt.Sprintf("{\"results\": [%s]}", strings.Join(allResponses, ","))
-		return []byte(combinedJSON), nil
+		return map[string]interface{}{"results": allResponses}, nil
 	}
 
 	// Regular unary call
-	err = conn.Invoke(ctx, fullMethod, reqMsg, respMsg)
+	stats := startCallStats(fullMethod, target.HostPort)
+	err = conn.Invoke(ctx, fullMethod, reqMsg, respMsg, callOptions...)
 	if err != nil {
+		if options.ShowStats {
+			stats.print(err)
+		}
 		if strings.Contains(err.Error(), "ERROR_AUTHENTICATE_FAILURE") ||
 			strings.Contains(err.Error(), "Token is invalid or expired") {
 
 			// Check if current environment is app type
 			if strings.HasSuffix(config.Environment, "-app") {
-				headerBox := pterm.DefaultBox.WithTitle("App Token Required").
+				headerBox := pterm.DefaultBox.WithTitle(i18n.T("app_token_required.title")).
 					WithTitleTopCenter().
 					WithRightPadding(4).
 					WithLeftPadding(4).
 					WithBoxStyle(pterm.NewStyle(pterm.FgLightRed))
 
-				appTokenExplain := "Please create a Domain Admin App in SpaceONE Console.\n" +
-					"This requires Domain Admin privilege.\n\n" +
-					"Or Please create a Workspace App in SpaceONE Console.\n" +
-					"This requires Workspace Owner privilege."
+				appTokenExplain := i18n.T("app_token.explain")
 
 				headerBox.Println(appTokenExplain)
 				fmt.Println()
 
-				steps := []string{
-					"1. Go to SpaceONE Console",
-					"2. Navigate to either 'Admin > App Page' or specific 'Workspace > App page'",
-					"3. Click 'Create' to create your App",
-					"4. Copy the generated App Token",
-					fmt.Sprintf("5. Update token in your config file:\n   Path: ~/.cfctl/setting.yaml\n   Environment: %s", config.Environment),
-				}
+				steps := i18n.Steps(
+					i18n.T("steps.go_console"),
+					i18n.T("steps.navigate_app"),
+					i18n.T("steps.create_app"),
+					i18n.T("steps.copy_token"),
+					i18n.T("steps.update_token", config.Environment))
 
-				instructionBox := pterm.DefaultBox.WithTitle("Required Steps").
+				instructionBox := pterm.DefaultBox.WithTitle(i18n.T("required_steps.title")).
 					WithTitleTopCenter().
 					WithRightPadding(4).
 					WithLeftPadding(4)
 
-				instructionBox.Println(strings.Join(steps, "\n\n"))
+				instructionBox.Println(steps)
 
 				return nil, fmt.Errorf("app token required")
 			} else {
 				// Original user authentication error message
-				headerBox := pterm.DefaultBox.WithTitle("Authentication Error").
+				headerBox := pterm.DefaultBox.WithTitle(i18n.T("auth_error.title")).
 					WithTitleTopCenter().
 					WithRightPadding(4).
 					WithLeftPadding(4).
 					WithBoxStyle(pterm.NewStyle(pterm.FgLightRed))
 
-				errorExplain := "Your authentication token has expired or is invalid.\n" +
-					"Please login again to refresh your credentials."
+				errorExplain := i18n.T("auth_error.explain")
 
 				headerBox.Println(errorExplain)
 				fmt.Println()
 
-				steps := []string{
-					"1. Run 'cfctl login'",
-					"2. Enter your credentials when prompted",
-					"3. Try your command again",
-				}
+				steps := i18n.Steps(
+					i18n.T("steps.run_login"),
+					i18n.T("steps.enter_credentials"),
+					i18n.T("steps.retry"))
 
-				instructionBox := pterm.DefaultBox.WithTitle("Required Steps").
+				instructionBox := pterm.DefaultBox.WithTitle(i18n.T("required_steps.title")).
 					WithTitleTopCenter().
 					WithRightPadding(4).
 					WithLeftPadding(4)
 
-				instructionBox.Println(strings.Join(steps, "\n\n"))
+				instructionBox.Println(steps)
 
 				return nil, fmt.Errorf("authentication required")
 			}
 		}
+		if status.Code(err) == codes.ResourceExhausted {
+			return nil, fmt.Errorf("failed to invoke method %s: %v (try a larger --max-recv-size or --compress gzip)", fullMethod, err)
+		}
 		return nil, fmt.Errorf("failed to invoke method %s: %v", fullMethod, err)
 	}
 
-	return respMsg.MarshalJSON()
+	if !options.ShowStats {
+		return decodeMessage(respMsg)
+	}
+	size := messageSize(respMsg)
+	resp, err := decodeMessage(respMsg)
+	if err != nil {
+		return nil, err
+	}
+	stats.addResponse(size, resp)
+	stats.print(nil)
+	return resp, nil
+}
+
+// outgoingContext builds the metadata of a call from the token, admin mode and extra headers.
+// Headers given with --header override the extra_headers of the environment.
+func outgoingContext(config *Config, options *FetchOptions) (context.Context, error) {
+	env := config.Environments[config.Environment]
+	authKey, authValue := env.Auth.Metadata(env.Token)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), authKey, authValue)
+
+	if options.Admin {
+		// Mirror the console admin mode which calls APIs in the domain scope
+		if claims, err := DecodeTokenClaims(env.Token); err == nil {
+			if domainID, ok := claims["did"].(string); ok {
+				ctx = metadata.AppendToOutgoingContext(ctx, "x-domain-id", domainID)
+			}
+		}
+	}
+
+	headers := make(map[string]string)
+	for key, value := range env.ExtraHeaders {
+		headers[strings.ToLower(key)] = value
+	}
+	for _, header := range options.Headers {
+		parts := strings.SplitN(header, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid header format '%s'. Use key=value", header)
+		}
+		headers[strings.ToLower(parts[0])] = parts[1]
+	}
+	for key, value := range headers {
+		ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+	}
+
+	return ctx, nil
 }
 
 func parseParameters(options *FetchOptions) (map[string]interface{}, error) {
@@ -770,6 +1077,22 @@ func parseParameters(options *FetchOptions) (map[string]interface{}, error) {
 		}
 	}
 
+	// Load the request body copied to the clipboard, JSON is read as YAML
+	if options.PasteFromClipboard {
+		text, err := clipboard.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read clipboard: %v", err)
+		}
+
+		var pasted map[string]interface{}
+		if err := yaml.Unmarshal([]byte(text), &pasted); err != nil {
+			return nil, fmt.Errorf("clipboard does not hold a JSON or YAML object: %v", err)
+		}
+		for key, value := range pasted {
+			parsed[key] = value
+		}
+	}
+
 	// Load from JSON parameter if provided
 	if options.JSONParameter != "" {
 		if err := json.Unmarshal([]byte(options.JSONParameter), &parsed); err != nil {
@@ -798,179 +1121,266 @@ func parseParameters(options *FetchOptions) (map[string]interface{}, error) {
 	return parsed, nil
 }
 
-func discoverService(refClient *grpcreflect.Client, serviceName string, resourceName string) (string, error) {
+// discoverService finds the full name of the service of a resource. With an API version
+// (e.g. v1), only the service of that version is accepted.
+func discoverService(refClient DescriptorSource, serviceName string, resourceName string, apiVersion string) (string, error) {
 	services, err := refClient.ListServices()
 	if err != nil {
 		return "", fmt.Errorf("failed to list services: %v", err)
 	}
 
+	var versions []string
 	for _, service := range services {
 		if strings.Contains(service, ".plugin.") && strings.HasSuffix(service, resourceName) {
-			return service, nil
+			if apiVersion == "" || serviceVersion(service) == apiVersion {
+				return service, nil
+			}
+			if !slices.Contains(versions, serviceVersion(service)) {
+				versions = append(versions, serviceVersion(service))
+			}
 		}
 	}
 
 	for _, service := range services {
 		if strings.Contains(service, fmt.Sprintf("spaceone.api.%s", serviceName)) &&
 			strings.HasSuffix(service, resourceName) {
-			return service, nil
+			if apiVersion == "" || serviceVersion(service) == apiVersion {
+				return service, nil
+			}
+			if !slices.Contains(versions, serviceVersion(service)) {
+				versions = append(versions, serviceVersion(service))
+			}
 		}
 	}
 
-	return "", fmt.Errorf("service not found for %s.%s", serviceName, resourceName)
-}
-
-// WatchResource monitors a resource for changes and prints updates
-func WatchResource(serviceName, verb, resource string, options *FetchOptions) error {
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
-
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt)
-
-	seenItems := make(map[string]bool)
-
-	initialData, err := FetchService(serviceName, verb, resource, &FetchOptions{
-		Parameters:      options.Parameters,
-		JSONParameter:   options.JSONParameter,
-		FileParameter:   options.FileParameter,
-		APIVersion:      options.APIVersion,
-		OutputFormat:    "",
-		CopyToClipboard: false,
-	})
-	if err != nil {
-		return err
+	if len(versions) > 0 {
+		return "", fmt.Errorf("%s.%s is not available in API version %s, the server exposes: %s",
+			serviceName, resourceName, apiVersion, strings.Join(versions, ", "))
 	}
+	return "", fmt.Errorf("service not found for %s.%s%s", serviceName, resourceName,
+		notFoundHint(resourceName, serviceResources(services, serviceName), "resources"))
+}
 
-	if results, ok := initialData["results"].([]interface{}); ok {
-		var recentItems []map[string]interface{}
-
-		for _, item := range results {
-			if m, ok := item.(map[string]interface{}); ok {
-				identifier := format.GenerateIdentifier(m)
-				seenItems[identifier] = true
-
-				recentItems = append(recentItems, m)
-				if len(recentItems) > 20 {
-					recentItems = recentItems[1:]
-				}
-			}
-		}
-
-		if len(recentItems) > 0 {
-			fmt.Printf("Recent items:\n")
-			format.PrintNewItems(recentItems)
+// serviceVersion returns the API version of a full service name
+// Example:
+//
+//	spaceone.api.inventory.v2.CloudService -> v2
+func serviceVersion(service string) string {
+	for _, part := range strings.Split(service, ".") {
+		if len(part) > 1 && part[0] == 'v' && part[1] >= '0' && part[1] <= '9' {
+			return part
 		}
 	}
+	return ""
+}
 
-	fmt.Printf("\nWatching for changes... (Ctrl+C to quit)\n\n")
-
-	for {
-		select {
-		case <-ticker.C:
-			newData, err := FetchService(serviceName, verb, resource, &FetchOptions{
-				Parameters:      options.Parameters,
-				JSONParameter:   options.JSONParameter,
-				FileParameter:   options.FileParameter,
-				APIVersion:      options.APIVersion,
-				OutputFormat:    "",
-				CopyToClipboard: false,
-			})
-			if err != nil {
-				continue
-			}
+// printData prints the response in the output format of the options
+func printData(data map[string]interface{}, options *FetchOptions, serviceName, verbName, resourceName string, refClient DescriptorSource) error {
+	var output string
 
-			var newItems []map[string]interface{}
-			if results, ok := newData["results"].([]interface{}); ok {
-				for _, item := range results {
-					if m, ok := item.(map[string]interface{}); ok {
-						identifier := format.GenerateIdentifier(m)
-						if !seenItems[identifier] {
-							newItems = append(newItems, m)
-							seenItems[identifier] = true
-						}
-					}
-				}
+	// Print only the summary of list results if requested
+	if options.Summary {
+		if results, ok := data["results"].([]interface{}); ok {
+			summary := format.SummarizeResults(data, results)
+			summary.Page = options.Page
+			if options.Page > 0 {
+				summary.PageSize = options.PageSize
 			}
 
-			if len(newItems) > 0 {
-				fmt.Printf("Found %d new items at %s:\n",
-					len(newItems),
-					time.Now().Format("2006-01-02 15:04:05"))
-
-				format.PrintNewItems(newItems)
-				fmt.Println()
+			dataBytes, err := json.MarshalIndent(summary, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal summary to JSON: %v", err)
 			}
-
-		case <-sigChan:
-			fmt.Println("\nStopping watch...")
+			fmt.Println(string(dataBytes))
 			return nil
 		}
 	}
-}
 
-func printData(data map[string]interface{}, options *FetchOptions, serviceName, verbName, resourceName string, refClient *grpcreflect.Client) {
-	var output string
+	// Print a valid empty structure instead of an empty document for lists without results
+	if results, ok := data["results"].([]interface{}); ok && len(results) == 0 && verbName == "list" &&
+		!isOutputPlugin(options.OutputFormat) {
+		return printEmptyResults(data, options, resourceName)
+	}
 
-	switch options.OutputFormat {
-	case "json":
-		dataBytes, err := json.MarshalIndent(data, "", "  ")
+	switch {
+	case isOutputPlugin(options.OutputFormat):
+		pluginOutput, err := runOutputPlugin(options.OutputFormat, data, serviceName, verbName, resourceName)
 		if err != nil {
-			log.Fatalf("Failed to marshal response to JSON: %v", err)
+			return err
+		}
+		output = pluginOutput
+		fmt.Print(output)
+
+	case options.OutputFormat == "json":
+		var value interface{} = data
+		if options.SortedKeys {
+			value = sortKeys(data, outputDescriptor(options, serviceName, verbName, resourceName, refClient))
+		}
+		dataBytes, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal response to JSON: %v", err)
 		}
 		output = string(dataBytes)
 		fmt.Println(output)
 
-	case "yaml":
+	case options.OutputFormat == "yaml":
+		var respDesc *desc.MessageDescriptor
+		if options.SortedKeys {
+			respDesc = outputDescriptor(options, serviceName, verbName, resourceName, refClient)
+		}
 		if results, ok := data["results"].([]interface{}); ok && len(results) > 0 {
 			var sb strings.Builder
 
+			var itemDesc *desc.MessageDescriptor
+			if respDesc != nil {
+				itemDesc = fieldMessage(respDesc.FindFieldByName("results"))
+			}
 			for i, item := range results {
 				if i > 0 {
 					sb.WriteString("---\n")
 				}
-				sb.WriteString(printYAMLDoc(item))
+				if options.SortedKeys {
+					item = sortKeys(item, itemDesc)
+				}
+				doc, err := printYAMLDoc(item)
+				if err != nil {
+					return err
+				}
+				sb.WriteString(doc)
 			}
 			output = sb.String()
 			fmt.Print(output)
 		} else {
-			output = printYAMLDoc(data)
+			var value interface{} = data
+			if options.SortedKeys {
+				value = sortKeys(data, respDesc)
+			}
+			doc, err := printYAMLDoc(value)
+			if err != nil {
+				return err
+			}
+			output = doc
 			fmt.Print(output)
 		}
 
-	case "table":
+	case options.OutputFormat == "table":
 		output = printTable(data, options, serviceName, verbName, resourceName, refClient)
 
-	case "csv":
-		output = printCSV(data)
+	case options.OutputFormat == "csv" || options.OutputFormat == "tsv":
+		output = printCSV(data, options)
+
+	case options.OutputFormat == "name":
+		output = printNames(data, resourceName)
+		fmt.Print(output)
+
+	case options.OutputFormat == "ndjson":
+		results, ok := data["results"].([]interface{})
+		if !ok {
+			results = []interface{}{data}
+		}
+		var sb strings.Builder
+		if err := writeNDJSON(&sb, results); err != nil {
+			return err
+		}
+		output = sb.String()
+		fmt.Print(output)
 
 	default:
-		output = printYAMLDoc(data)
+		doc, err := printYAMLDoc(data)
+		if err != nil {
+			return err
+		}
+		output = doc
 		fmt.Print(output)
 	}
 
 	// Copy to clipboard if requested
-	if options.CopyToClipboard && output != "" {
+	if options.CopyField != "" {
+		return copyFieldToClipboard(data, options.CopyField)
+	} else if options.CopyToClipboard && output != "" {
 		if err := clipboard.WriteAll(output); err != nil {
-			log.Fatalf("Failed to copy to clipboard: %v", err)
+			return fmt.Errorf("failed to copy to clipboard: %v", err)
 		}
 		pterm.Success.Println("The output has been copied to your clipboard.")
 	}
+	return nil
 }
 
-func printYAMLDoc(v interface{}) string {
+// copyFieldToClipboard copies a single value of the response to the clipboard.
+// Scalars are copied as they are and objects and lists as JSON.
+func copyFieldToClipboard(data map[string]interface{}, path string) error {
+	value, ok := format.GetValueByPath(data, path)
+	if !ok {
+		return fmt.Errorf("field %s not found in the response", path)
+	}
+
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case map[string]interface{}, []interface{}:
+		dataBytes, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal field %s to JSON: %v", path, err)
+		}
+		text = string(dataBytes)
+	default:
+		text = fmt.Sprintf("%v", v)
+	}
+
+	if err := clipboard.WriteAll(text); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %v", err)
+	}
+	pterm.Success.WithWriter(os.Stderr).Printf("The value of %s has been copied to your clipboard.\n", path)
+	return nil
+}
+
+// printEmptyResults tells on stderr that a list has no results and prints
+// an empty structure of the output format on stdout, so that scripts can parse it
+func printEmptyResults(data map[string]interface{}, options *FetchOptions, resourceName string) error {
+	pterm.Info.WithWriter(os.Stderr).Printf("No %s resources found\n", resourceName)
+
+	switch options.OutputFormat {
+	case "json":
+		dataBytes, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal response to JSON: %v", err)
+		}
+		fmt.Println(string(dataBytes))
+	case "yaml":
+		fmt.Println("[]")
+	case "csv", "tsv":
+		if options.Columns != "" && !options.NoHeaders {
+			writer := newCSVWriter(os.Stdout, options)
+			headers := strings.Split(options.Columns, ",")
+			for i := range headers {
+				headers[i] = strings.TrimSpace(headers[i])
+			}
+			writer.Write(headers)
+			writer.Flush()
+			return writer.Error()
+		}
+	}
+	return nil
+}
+
+// printYAMLDoc formats a value as a YAML document indented by two spaces
+func printYAMLDoc(v interface{}) (string, error) {
 	var buf bytes.Buffer
 	encoder := yaml.NewEncoder(&buf)
 	encoder.SetIndent(2)
 	if err := encoder.Encode(v); err != nil {
-		log.Fatalf("Failed to marshal response to YAML: %v", err)
+		return "", fmt.Errorf("failed to marshal response to YAML: %v", err)
 	}
-	return buf.String()
+	return buf.String(), nil
 }
 
-func getMinimalFields(serviceName, resourceName string, refClient *grpcreflect.Client) []string {
+func getMinimalFields(serviceName, resourceName string, refClient DescriptorSource) []string {
 	// Default minimal fields that should always be included if they exist
 	defaultFields := []string{"name", "created_at"}
+	if refClient == nil {
+		return defaultFields
+	}
 
 	// Try to get message descriptor for the resource
 	fullServiceName := fmt.Sprintf("spaceone.api.%s.v1.%s", serviceName, resourceName)
@@ -1008,6 +1418,11 @@ func getMinimalFields(serviceName, resourceName string, refClient *grpcreflect.C
 		return defaultFields
 	}
 
+	// Prefer the field behavior annotations of the proto over guessing by field names
+	if annotatedFields, ok := annotatedMinimalFields(itemMsgDesc); ok {
+		return annotatedFields
+	}
+
 	// Collect required fields and important fields
 	minimalFields := make([]string, 0)
 	fields := itemMsgDesc.GetFields()
@@ -1044,7 +1459,7 @@ func getMinimalFields(serviceName, resourceName string, refClient *grpcreflect.C
 	return minimalFields
 }
 
-func printTable(data map[string]interface{}, options *FetchOptions, serviceName, verbName, resourceName string, refClient *grpcreflect.Client) string {
+func printTable(data map[string]interface{}, options *FetchOptions, serviceName, verbName, resourceName string, refClient DescriptorSource) string {
 	if results, ok := data["results"].([]interface{}); ok {
 		// Set default page size if not specified and paging is enabled
 		if !options.NoPaging {
@@ -1056,16 +1471,10 @@ func printTable(data map[string]interface{}, options *FetchOptions, serviceName,
 			options.PageSize = len(results)
 		}
 
-		// Initialize keyboard
-		if err := keyboard.Open(); err != nil {
-			fmt.Println("Failed to initialize keyboard:", err)
-			return ""
-		}
-		defer keyboard.Close()
-
 		currentPage := 0
 		searchTerm := ""
 		filteredResults := results
+		humanizeTime := options.HumanizeTime
 
 		// Extract headers
 		headers := make(map[string]bool)
@@ -1098,6 +1507,21 @@ func printTable(data map[string]interface{}, options *FetchOptions, serviceName,
 			}
 		}
 
+		// Columns kept first when the table is wider than the terminal
+		fitPriority := getMinimalFields(serviceName, resourceName, refClient)
+
+		// Print a static table where the keyboard driven pager can not run,
+		// e.g. for redirected output or Windows consoles without ANSI support
+		if !format.IsInteractive() || !format.SupportsANSI() {
+			printStaticTable(data, headerSlice, results, humanizeTime, options.RawValues, options.NoHeaders, fitPriority)
+			return ""
+		}
+		if err := keyboard.Open(); err != nil {
+			printStaticTable(data, headerSlice, results, humanizeTime, options.RawValues, options.NoHeaders, fitPriority)
+			return ""
+		}
+		defer keyboard.Close()
+
 		for {
 			if searchTerm != "" {
 				filteredResults = filterResults(results, searchTerm)
@@ -1117,8 +1541,7 @@ func printTable(data map[string]interface{}, options *FetchOptions, serviceName,
 				endIdx = totalItems
 			}
 
-			// Clear screen
-			fmt.Print("\033[H\033[2J")
+			format.ClearScreen()
 
 			if searchTerm != "" {
 				fmt.Printf("Search: %s (Found: %d items)\n", searchTerm, totalItems)
@@ -1130,36 +1553,46 @@ func printTable(data map[string]interface{}, options *FetchOptions, serviceName,
 				if row, ok := result.(map[string]interface{}); ok {
 					rowData := make([]string, len(headerSlice))
 					for i, key := range headerSlice {
-						rowData[i] = FormatTableValue(row[key])
+						rowData[i] = formatTableCell(key, row[key], humanizeTime, options.RawValues)
 					}
 					tableData = append(tableData, rowData)
 				}
 			}
 
-			// Print table
-			pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+			// Print table, fitted to the terminal instead of wrapping rows
+			tableData, hiddenColumns := format.FitTable(tableData, format.TerminalWidth(), fitPriority)
+			renderTable(tableData, options.NoHeaders)
+			printHiddenColumns(hiddenColumns)
 
 			fmt.Printf("\nPage %d of %d (Total items: %d)\n", currentPage+1, totalPages, totalItems)
-			fmt.Println("Navigation: [h]previous page, [l]next page, [/]search, [c]lear search, [q]uit")
 
-			// Handle keyboard input
-			char, _, err := keyboard.GetKey()
+			printResultsSummary(data, filteredResults, searchTerm == "")
+			fmt.Println("Navigation: [h/←]previous page, [l/→]next page, [/]search, [c]lear search, [t]oggle time format, [q/Esc]uit")
+
+			// Handle keyboard input. Arrow and page keys are read as keys, which consoles of
+			// Windows report without a character.
+			char, key, err := keyboard.GetKey()
 			if err != nil {
 				fmt.Println("Error reading keyboard input:", err)
 				return ""
 			}
+			if totalPages == 0 {
+				totalPages = 1
+			}
 
-			switch char {
-			case 'l', 'L':
+			switch {
+			case char == 'l' || char == 'L' || key == keyboard.KeyArrowRight || key == keyboard.KeyPgdn:
 				currentPage = (currentPage + 1) % totalPages
-			case 'h', 'H':
+			case char == 'h' || char == 'H' || key == keyboard.KeyArrowLeft || key == keyboard.KeyPgup:
 				currentPage = (currentPage - 1 + totalPages) % totalPages
-			case 'q', 'Q':
+			case char == 'q' || char == 'Q' || key == keyboard.KeyEsc || key == keyboard.KeyCtrlC:
 				return ""
-			case 'c', 'C':
+			case char == 'c' || char == 'C':
 				searchTerm = ""
 				currentPage = 0
-			case '/':
+			case char == 't' || char == 'T':
+				humanizeTime = !humanizeTime
+			case char == '/':
 				fmt.Print("\nEnter search term: ")
 				keyboard.Close()
 				var input string
@@ -1183,14 +1616,64 @@ func printTable(data map[string]interface{}, options *FetchOptions, serviceName,
 	}
 
 	for _, header := range headers {
-		value := FormatTableValue(data[header])
+		value := formatTableCell(header, data[header], options.HumanizeTime, options.RawValues)
 		tableData = append(tableData, []string{header, value})
 	}
 
-	pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+	renderTable(tableData, options.NoHeaders)
 	return ""
 }
 
+// renderTable renders a table whose first row is the header, leaving the header out if requested
+func renderTable(tableData pterm.TableData, noHeaders bool) {
+	if !noHeaders {
+		pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		return
+	}
+	if len(tableData) > 1 {
+		pterm.DefaultTable.WithData(tableData[1:]).Render()
+	}
+}
+
+// printStaticTable prints all results in a single table without the pager
+func printStaticTable(data map[string]interface{}, headers []string, results []interface{}, humanizeTime, rawValues, noHeaders bool, fitPriority []string) {
+	tableData := pterm.TableData{headers}
+	for _, result := range results {
+		if row, ok := result.(map[string]interface{}); ok {
+			rowData := make([]string, len(headers))
+			for i, key := range headers {
+				rowData[i] = formatTableCell(key, row[key], humanizeTime, rawValues)
+			}
+			tableData = append(tableData, rowData)
+		}
+	}
+	tableData, hiddenColumns := format.FitTable(tableData, format.TerminalWidth(), fitPriority)
+	renderTable(tableData, noHeaders)
+	printHiddenColumns(hiddenColumns)
+	printResultsSummary(data, results, true)
+}
+
+// printResultsSummary prints how many items were fetched compared to the server-side total
+// and the count of items per status below a table
+func printResultsSummary(data map[string]interface{}, results []interface{}, showTotal bool) {
+	summary := format.SummarizeResults(data, results)
+	if summary.Total > summary.Shown && showTotal {
+		fmt.Printf("Showing %d of %d items\n", summary.Shown, summary.Total)
+	}
+	if statusLine := summary.StatusLine(); statusLine != "" {
+		fmt.Printf("%s: %s\n", strings.ToUpper(summary.StatusField[:1])+summary.StatusField[1:], statusLine)
+	}
+}
+
+// printHiddenColumns tells which columns did not fit in the terminal and how to show them
+func printHiddenColumns(hiddenColumns []string) {
+	if len(hiddenColumns) == 0 {
+		return
+	}
+	fmt.Printf("Hidden columns (terminal too narrow): %s\n", strings.Join(hiddenColumns, ", "))
+	fmt.Println("Show them with --columns or a wider terminal")
+}
+
 func filterResults(results []interface{}, searchTerm string) []interface{} {
 	var filtered []interface{}
 	searchTerm = strings.ToLower(searchTerm)
@@ -1209,24 +1692,53 @@ func filterResults(results []interface{}, searchTerm string) []interface{} {
 	return filtered
 }
 
+// formatTableCell formats a table value for display.
+// Unless raw values are requested, timestamps are rendered as relative time,
+// size fields are humanized (KiB, MiB, ...) and numbers get thousands separators.
+func formatTableCell(field string, val interface{}, humanizeTime, rawValues bool) string {
+	if rawValues {
+		return formatFieldValue(field, val)
+	}
+
+	if humanizeTime {
+		if relative, ok := format.HumanizeTime(val); ok {
+			return relative
+		}
+	}
+
+	switch v := val.(type) {
+	case float64:
+		if format.IsSizeField(field) {
+			return format.FormatBytes(v)
+		}
+		return format.FormatNumber(v)
+	case string:
+		// int64 values are encoded as strings in protobuf JSON
+		if format.IsSizeField(field) {
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				return format.FormatBytes(n)
+			}
+		}
+	}
+
+	return formatFieldValue(field, val)
+}
+
+// formatFieldValue formats a table value, coloring strings by the theme rules of the field
+func formatFieldValue(field string, val interface{}) string {
+	if s, ok := val.(string); ok {
+		return format.ColorValue(field, s)
+	}
+	return FormatTableValue(val)
+}
+
 func FormatTableValue(val interface{}) string {
 	switch v := val.(type) {
 	case nil:
 		return ""
 	case string:
 		// Add colors for status values
-		switch strings.ToUpper(v) {
-		case "SUCCESS":
-			return pterm.FgGreen.Sprint(v)
-		case "FAILURE":
-			return pterm.FgRed.Sprint(v)
-		case "PENDING":
-			return pterm.FgYellow.Sprint(v)
-		case "RUNNING":
-			return pterm.FgBlue.Sprint(v)
-		default:
-			return v
-		}
+		return format.ColorValue("", v)
 	case float64, float32, int, int32, int64, uint, uint32, uint64:
 		return fmt.Sprintf("%v", v)
 	case bool:
@@ -1242,9 +1754,11 @@ func FormatTableValue(val interface{}) string {
 	}
 }
 
-func printCSV(data map[string]interface{}) string {
-	writer := csv.NewWriter(os.Stdout)
+// printCSV writes the results, or the fields of a single resource, as CSV without colors
+func printCSV(data map[string]interface{}, options *FetchOptions) string {
+	writer := newCSVWriter(os.Stdout, options)
 	defer writer.Flush()
+	noHeaders := options.NoHeaders
 
 	if results, ok := data["results"].([]interface{}); ok {
 		if len(results) == 0 {
@@ -1257,21 +1771,24 @@ func printCSV(data map[string]interface{}) string {
 				headers = append(headers, key)
 			}
 			sort.Strings(headers)
-			writer.Write(headers)
+			if !noHeaders {
+				writer.Write(headers)
+			}
 		}
 
 		for _, result := range results {
 			if row, ok := result.(map[string]interface{}); ok {
 				rowData := make([]string, len(headers))
 				for i, header := range headers {
-					rowData[i] = FormatTableValue(row[header])
+					rowData[i] = formatCSVValue(row[header])
 				}
 				writer.Write(rowData)
 			}
 		}
 	} else {
-		headers := []string{"Field", "Value"}
-		writer.Write(headers)
+		if !noHeaders {
+			writer.Write([]string{"Field", "Value"})
+		}
 
 		fields := make([]string, 0)
 		for field := range data {
@@ -1280,7 +1797,7 @@ func printCSV(data map[string]interface{}) string {
 		sort.Strings(fields)
 
 		for _, field := range fields {
-			row := []string{field, FormatTableValue(data[field])}
+			row := []string{field, formatCSVValue(data[field])}
 			writer.Write(row)
 		}
 	}
@@ -1288,12 +1805,76 @@ func printCSV(data map[string]interface{}) string {
 	return ""
 }
 
+// printNames returns the primary identifiers of the results, or of a single resource, one per line
+// so that they can be piped to other commands. Resources without an id field are named by their name.
+func printNames(data map[string]interface{}, resourceName string) string {
+	results, ok := data["results"].([]interface{})
+	if !ok {
+		results = []interface{}{data}
+	}
+
+	var sb strings.Builder
+	for _, result := range results {
+		if id := resourceIdentifier(result, resourceName); id != "" {
+			sb.WriteString(id)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// resourceIdentifier returns the id field of a resource, falling back to its name
+func resourceIdentifier(result interface{}, resourceName string) string {
+	item, ok := result.(map[string]interface{})
+	if !ok {
+		return formatCSVValue(result)
+	}
+	for _, field := range []string{ResourceIDField(resourceName), "name"} {
+		if value, ok := item[field]; ok && value != nil && value != "" {
+			return formatCSVValue(value)
+		}
+	}
+	return ""
+}
+
+// csvDelimiter returns the field delimiter of csv and tsv output: the --delimiter character,
+// a tab for tsv and a comma otherwise. Tabs can be given as "\t" or "tab".
+func csvDelimiter(options *FetchOptions) (rune, error) {
+	switch options.Delimiter {
+	case "":
+		if options.OutputFormat == "tsv" {
+			return '\t', nil
+		}
+		return ',', nil
+	case `\t`, "tab":
+		return '\t', nil
+	}
+
+	delimiter, size := utf8.DecodeRuneInString(options.Delimiter)
+	if size != len(options.Delimiter) || delimiter == utf8.RuneError || delimiter == '"' ||
+		delimiter == '\r' || delimiter == '\n' {
+		return 0, fmt.Errorf("invalid --delimiter %q, use a single character other than a quote or newline", options.Delimiter)
+	}
+	return delimiter, nil
+}
+
+// newCSVWriter returns a CSV writer with the delimiter of the output format
+func newCSVWriter(out io.Writer, options *FetchOptions) *csv.Writer {
+	writer := csv.NewWriter(out)
+	if delimiter, err := csvDelimiter(options); err == nil {
+		writer.Comma = delimiter
+	}
+	return writer
+}
+
+// formatCSVValue formats a CSV value. ANSI codes are removed from strings so that
+// CSV files never contain colors, whether or not the output is a terminal.
 func formatCSVValue(val interface{}) string {
 	switch v := val.(type) {
 	case nil:
 		return ""
 	case string:
-		return v
+		return pterm.RemoveColorFromString(v)
 	case float64, float32, int, int32, int64, uint, uint32, uint64:
 		return fmt.Sprintf("%v", v)
 	case bool:
@@ -0,0 +1,131 @@
+package transport
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudforet-io/cfctl/pkg/configs"
+)
+
+// writeSetting points the home directory to a temporary directory with the given setting file
+func writeSetting(t *testing.T, content string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	dir := filepath.Join(home, ".cfctl")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if content != "" {
+		if err := os.WriteFile(filepath.Join(dir, "setting.yaml"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configs.ReloadTransportSettings()
+	t.Cleanup(configs.ReloadTransportSettings)
+}
+
+// The failures of FetchService are returned to the caller, which decides the exit status.
+// A library function exiting the process would end the test binary and fail the package.
+func TestFetchServiceReturnsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting string
+		options *FetchOptions
+		want    string
+	}{
+		{
+			name:    "missing setting file",
+			options: &FetchOptions{},
+			want:    "cfctl login",
+		},
+		{
+			name:    "no environment",
+			setting: "environments: {}\n",
+			options: &FetchOptions{},
+			want:    "no environment set",
+		},
+		{
+			name: "unresolvable endpoint",
+			setting: `environment: test-app
+environments:
+  test-app:
+    endpoint: grpc://127.0.0.1:1
+    token: test-token
+    endpoints:
+      identity: ftp://identity.example.com
+`,
+			options: &FetchOptions{},
+			want:    "unsupported scheme",
+		},
+		{
+			name: "unreachable server",
+			setting: `environment: test-app
+environments:
+  test-app:
+    endpoint: grpc://127.0.0.1:1
+    token: test-token
+    dial_timeout: 200ms
+`,
+			options: &FetchOptions{},
+			want:    "failed to connect",
+		},
+		{
+			name:    "invalid delimiter",
+			options: &FetchOptions{OutputFormat: "csv", Delimiter: ";;"},
+			want:    "delimiter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeSetting(t, tt.setting)
+
+			_, err := FetchService("identity", "list", "Workspace", tt.options)
+			if err == nil {
+				t.Fatal("FetchService() succeeded, want an error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("FetchService() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunOutputPluginReturnsErrors(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "missing name", format: "plugin:", want: "name is required"},
+		{name: "missing plugin", format: "plugin:missing", want: "not found in PATH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runOutputPlugin(tt.format, map[string]interface{}{}, "identity", "list", "Workspace")
+			if err == nil {
+				t.Fatal("runOutputPlugin() succeeded, want an error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("runOutputPlugin() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintDataReturnsErrors(t *testing.T) {
+	options := &FetchOptions{OutputFormat: "plugin:missing"}
+	t.Setenv("PATH", t.TempDir())
+
+	if err := printData(map[string]interface{}{"results": []interface{}{}}, options, "identity", "list", "Workspace", nil); err == nil {
+		t.Error("printData() with a missing output plugin succeeded, want an error")
+	}
+}
@@ -0,0 +1,138 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+)
+
+// ErrLoginRequired is returned when the session cannot be renewed without logging in again
+var ErrLoginRequired = errors.New("please run 'cfctl login' first")
+
+// SessionStatus describes the tokens of a user environment after a refresh
+type SessionStatus struct {
+	Environment    string
+	Scope          string
+	ExpiresAt      time.Time // Expiry of the renewed access token
+	RefreshExpires time.Time // Expiry of the refresh token, the session ends then
+}
+
+// RefreshSession grants a new access token for the scope of the current one with the cached refresh token
+// of the current user environment and replaces the cached access token atomically,
+// so that concurrent cfctl calls never read a partially written token.
+func RefreshSession() (*SessionStatus, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %v", err)
+	}
+	if !strings.HasSuffix(config.Environment, "-user") {
+		return nil, fmt.Errorf("session refresh is only available for user environments, %s is not one", config.Environment)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user home directory: %v", err)
+	}
+	cacheDir := filepath.Join(home, ".cfctl", "cache", config.Environment)
+
+	refreshData, err := os.ReadFile(filepath.Join(cacheDir, "refresh_token"))
+	if err != nil {
+		return nil, fmt.Errorf("no refresh token found: %w", ErrLoginRequired)
+	}
+	refreshToken := strings.TrimSpace(string(refreshData))
+	refreshExpires, err := tokenExpiry(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(refreshExpires) {
+		return nil, fmt.Errorf("the refresh token expired at %s: %w", refreshExpires.Format(time.RFC3339), ErrLoginRequired)
+	}
+
+	claims, err := DecodeTokenClaims(config.Environments[config.Environment].Token)
+	if err != nil {
+		return nil, fmt.Errorf("no valid access token found: %w", ErrLoginRequired)
+	}
+
+	// Keep the scope and workspace of the current token
+	domainID, _ := claims["did"].(string)
+	workspaceID, _ := claims["wid"].(string)
+	scope := "DOMAIN"
+	if workspaceID != "" {
+		scope = "WORKSPACE"
+	}
+
+	resolver, err := endpoints.NewResolver(config.Environment, config.Environments[config.Environment].Endpoint, config.Environments[config.Environment].Endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := grantScopedToken(config, resolver, refreshToken, scope, domainID, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant token: %v", err)
+	}
+	expiresAt, err := tokenExpiry(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeFileAtomic(filepath.Join(cacheDir, "access_token"), []byte(accessToken), 0600); err != nil {
+		return nil, fmt.Errorf("failed to save access token: %v", err)
+	}
+
+	return &SessionStatus{
+		Environment:    config.Environment,
+		Scope:          scope,
+		ExpiresAt:      expiresAt,
+		RefreshExpires: refreshExpires,
+	}, nil
+}
+
+// AccessTokenExpiry returns the expiry of the access token of the current environment
+func AccessTokenExpiry() (time.Time, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load config: %v", err)
+	}
+	return tokenExpiry(config.Environments[config.Environment].Token)
+}
+
+// tokenExpiry returns the expiry of a token from its exp claim
+func tokenExpiry(token string) (time.Time, error) {
+	claims, err := DecodeTokenClaims(token)
+	if err != nil {
+		return time.Time{}, err
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return time.Time{}, fmt.Errorf("token has no expiry")
+	}
+	return time.Unix(int64(exp), 0), nil
+}
+
+// writeFileAtomic writes the data to a temporary file next to the path and renames it over the path
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
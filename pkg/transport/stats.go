@@ -0,0 +1,56 @@
+package transport
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/format"
+	"github.com/jhump/protoreflect/dynamic"
+	"google.golang.org/grpc/status"
+)
+
+// callStats describes a call for --show-stats
+type callStats struct {
+	method   string
+	hostPort string
+	start    time.Time
+	size     int
+	items    int
+}
+
+// startCallStats starts timing a call of a method on a server
+func startCallStats(method, hostPort string) *callStats {
+	return &callStats{method: method, hostPort: hostPort, start: time.Now()}
+}
+
+// addResponse counts the size and items of a response message, before it is decoded
+func (s *callStats) addResponse(size int, resp map[string]interface{}) {
+	s.size += size
+	if results, ok := resp["results"].([]interface{}); ok {
+		s.items += len(results)
+	} else if resp != nil {
+		s.items++
+	}
+}
+
+// print writes the duration, payload size, server and number of items of the call to stderr,
+// so that the statistics do not mix with the output
+func (s *callStats) print(err error) {
+	duration := time.Since(s.start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Stats: %s on %s failed with %s after %s\n", s.method, s.hostPort, status.Code(err), duration)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Stats: %s on %s took %s, %s, %d items\n",
+		s.method, s.hostPort, duration, format.FormatBytes(float64(s.size)), s.items)
+}
+
+// messageSize returns the size of a message on the wire
+func messageSize(msg *dynamic.Message) int {
+	data, err := msg.Marshal()
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
@@ -0,0 +1,150 @@
+package transport
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/cloudforet-io/cfctl/pkg/endpoints"
+)
+
+// resultWriter writes list results incrementally as the pages arrive
+type resultWriter interface {
+	WriteResults(results []interface{}) error
+	Flush() error
+}
+
+// isStreamingFormat reports whether the output format can be written row by row
+func isStreamingFormat(outputFormat string) bool {
+	return outputFormat == "csv" || outputFormat == "tsv" || outputFormat == "ndjson" || outputFormat == "name"
+}
+
+// canStreamResults reports whether list results can be written page by page.
+// Sorting, grouping, enrichment, summaries, row limits and recordings need all results at once.
+func canStreamResults(verb string, options *FetchOptions) bool {
+	return verb == "list" && options.AllPages && isStreamingFormat(options.OutputFormat) &&
+		options.SortBy == "" && options.GroupBy == "" && len(options.Enrich) == 0 &&
+		!options.Summary && options.Rows == 0 && !options.CopyToClipboard && options.CopyField == "" && options.JQ == "" && options.Record == ""
+}
+
+// streamAllPages writes every page of a list call to stdout as soon as it is fetched,
+// so that memory stays flat regardless of the number of results.
+// The post_request hook is not run since the complete response is never held in memory.
+func streamAllPages(config *Config, serviceName, resourceName string, options *FetchOptions, resolver *endpoints.Resolver) (map[string]interface{}, error) {
+	out := bufio.NewWriter(os.Stdout)
+
+	var writer resultWriter
+	switch options.OutputFormat {
+	case "csv", "tsv":
+		writer = &csvResultWriter{writer: newCSVWriter(out, options), noHeaders: options.NoHeaders}
+	case "name":
+		writer = &nameResultWriter{writer: out, resourceName: resourceName}
+	default:
+		writer = &ndjsonResultWriter{writer: out}
+	}
+
+	totalCount, err := forEachPage(config, serviceName, resourceName, options, resolver, func(results []interface{}) error {
+		if options.Columns != "" {
+			results = filterColumns(results, options.Columns)
+		}
+		return writer.WriteResults(results)
+	})
+	if flushErr := writer.Flush(); err == nil {
+		err = flushErr
+	}
+	if flushErr := out.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"total_count": totalCount}, nil
+}
+
+// csvResultWriter writes results as CSV rows.
+// The columns are taken from the first result, like printCSV does.
+type csvResultWriter struct {
+	writer    *csv.Writer
+	headers   []string
+	noHeaders bool
+}
+
+func (w *csvResultWriter) WriteResults(results []interface{}) error {
+	for _, result := range results {
+		row, ok := result.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if w.headers == nil {
+			for key := range row {
+				w.headers = append(w.headers, key)
+			}
+			sort.Strings(w.headers)
+			if !w.noHeaders {
+				if err := w.writer.Write(w.headers); err != nil {
+					return fmt.Errorf("failed to write CSV header: %v", err)
+				}
+			}
+		}
+
+		rowData := make([]string, len(w.headers))
+		for i, header := range w.headers {
+			rowData[i] = formatCSVValue(row[header])
+		}
+		if err := w.writer.Write(rowData); err != nil {
+			return fmt.Errorf("failed to write CSV row: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (w *csvResultWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// nameResultWriter writes the primary identifier of each result on its own line
+type nameResultWriter struct {
+	writer       io.Writer
+	resourceName string
+}
+
+func (w *nameResultWriter) WriteResults(results []interface{}) error {
+	_, err := io.WriteString(w.writer, printNames(map[string]interface{}{"results": results}, w.resourceName))
+	return err
+}
+
+func (w *nameResultWriter) Flush() error {
+	return nil
+}
+
+// ndjsonResultWriter writes each result as a JSON object on its own line
+type ndjsonResultWriter struct {
+	writer io.Writer
+}
+
+func (w *ndjsonResultWriter) WriteResults(results []interface{}) error {
+	return writeNDJSON(w.writer, results)
+}
+
+func (w *ndjsonResultWriter) Flush() error {
+	return nil
+}
+
+// writeNDJSON writes each result as a JSON object on its own line
+func writeNDJSON(writer io.Writer, results []interface{}) error {
+	encoder := json.NewEncoder(writer)
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("failed to write NDJSON: %v", err)
+		}
+	}
+	return nil
+}
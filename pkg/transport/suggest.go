@@ -0,0 +1,184 @@
+package transport
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+)
+
+// maxSuggestions is the number of names suggested for a misspelled name
+const maxSuggestions = 3
+
+// notFoundHint suggests the candidates closest to a name that was not found and lists the valid ones
+// Example:
+//
+//	CloudServices -> did you mean 'CloudService'? Available resources: CloudService, CloudServiceType, ...
+func notFoundHint(name string, candidates []string, kind string) string {
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	var hint strings.Builder
+	if suggestions := suggestNames(name, candidates); len(suggestions) > 0 {
+		quoted := make([]string, len(suggestions))
+		for i, s := range suggestions {
+			quoted[i] = fmt.Sprintf("'%s'", s)
+		}
+		hint.WriteString(fmt.Sprintf("\n\nDid you mean %s?", strings.Join(quoted, " or ")))
+	}
+
+	sorted := append([]string(nil), candidates...)
+	sort.Strings(sorted)
+	hint.WriteString(fmt.Sprintf("\n\nAvailable %s: %s", kind, strings.Join(sorted, ", ")))
+	return hint.String()
+}
+
+// suggestNames returns the candidates within a small edit distance of the name, closest first
+func suggestNames(name string, candidates []string) []string {
+	type match struct {
+		name     string
+		distance int
+	}
+
+	lowerName := strings.ToLower(name)
+	// Allow roughly one typo per three characters
+	threshold := len(name) / 3
+	if threshold < 2 {
+		threshold = 2
+	}
+
+	var matches []match
+	for _, candidate := range candidates {
+		lowerCandidate := strings.ToLower(candidate)
+		distance := editDistance(lowerName, lowerCandidate)
+		if strings.HasPrefix(lowerCandidate, lowerName) || strings.HasPrefix(lowerName, lowerCandidate) {
+			distance = min(distance, 1)
+		}
+		if distance <= threshold {
+			matches = append(matches, match{candidate, distance})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	var names []string
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		names = append(names, matches[i].name)
+	}
+	return names
+}
+
+// editDistance returns the Levenshtein distance of two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// serviceResources returns the resources of a service in the list of full service names
+func serviceResources(services []string, serviceName string) []string {
+	seen := make(map[string]bool)
+	var resources []string
+	for _, service := range services {
+		if !strings.Contains(service, fmt.Sprintf("spaceone.api.%s.", serviceName)) {
+			continue
+		}
+		resource := service[strings.LastIndex(service, ".")+1:]
+		if !seen[resource] {
+			seen[resource] = true
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+// methodNames returns the names of the methods of a service
+func methodNames(serviceDesc *desc.ServiceDescriptor) []string {
+	var names []string
+	for _, method := range serviceDesc.GetMethods() {
+		names = append(names, method.GetName())
+	}
+	return names
+}
+
+// fieldNames returns the names of the top-level fields of a message
+func fieldNames(msgDesc *desc.MessageDescriptor) []string {
+	var names []string
+	for _, field := range msgDesc.GetFields() {
+		names = append(names, field.GetName())
+	}
+	return names
+}
+
+// normalizeResourceName returns the resource of the service that a resource name was meant for,
+// ignoring case, underscores and plurals, or the name unchanged when none matches
+// Example:
+//
+//	cloud_services, cloudservice, CloudServices -> CloudService
+func normalizeResourceName(refClient DescriptorSource, serviceName, resourceName string) string {
+	services, err := refClient.ListServices()
+	if err != nil {
+		return resourceName
+	}
+
+	resources := serviceResources(services, serviceName)
+	for _, resource := range resources {
+		if resource == resourceName {
+			return resourceName
+		}
+	}
+
+	forms := singularForms(resourceKey(resourceName))
+	for _, resource := range resources {
+		key := resourceKey(resource)
+		for _, form := range forms {
+			if key == form {
+				return resource
+			}
+		}
+	}
+	return resourceName
+}
+
+// resourceKey folds case, underscores and hyphens of a resource name
+func resourceKey(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
+
+// singularForms returns the name and the singulars it may be the plural of
+func singularForms(name string) []string {
+	forms := []string{name}
+	if strings.HasSuffix(name, "ies") {
+		forms = append(forms, strings.TrimSuffix(name, "ies")+"y")
+	}
+	if strings.HasSuffix(name, "es") {
+		forms = append(forms, strings.TrimSuffix(name, "es"))
+	}
+	if strings.HasSuffix(name, "s") {
+		forms = append(forms, strings.TrimSuffix(name, "s"))
+	}
+	return forms
+}
@@ -0,0 +1,279 @@
+package transport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudforet-io/cfctl/pkg/format"
+)
+
+// watchInterval is the polling interval of watch mode
+const watchInterval = 2 * time.Second
+
+// WatchResource monitors a resource for changes and prints updates
+func WatchResource(serviceName, verb, resource string, options *FetchOptions) error {
+	return WatchResources(serviceName, verb, []string{resource}, options)
+}
+
+// WatchResources monitors several resources of a service concurrently over shared connections.
+// New items of each resource are prefixed with the resource name and Ctrl+C stops all watchers.
+// Changes of the setting file and the token cache are picked up without restarting.
+func WatchResources(serviceName, verb string, resources []string, options *FetchOptions) error {
+	releaseConns := shareConnections()
+	defer releaseConns()
+
+	var printMu sync.Mutex
+	stopReload := hotReload(func(path string) {
+		printMu.Lock()
+		defer printMu.Unlock()
+		fmt.Printf("Reloaded %s at %s\n", filepath.Base(path), time.Now().Format("2006-01-02 15:04:05"))
+	})
+	defer stopReload()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+	defer signal.Stop(sigChan)
+
+	var initial, wg sync.WaitGroup
+	errs := make(chan error, len(resources))
+
+	for _, resource := range resources {
+		prefix := ""
+		if len(resources) > 1 {
+			prefix = fmt.Sprintf("[%s] ", resource)
+		}
+
+		initial.Add(1)
+		wg.Add(1)
+		go func(resource, prefix string) {
+			defer wg.Done()
+			if err := watchLoop(ctx, serviceName, verb, resource, options, prefix, &printMu, initial.Done); err != nil {
+				errs <- err
+				cancel()
+			}
+		}(resource, prefix)
+	}
+
+	initial.Wait()
+	if ctx.Err() == nil {
+		fmt.Printf("\nWatching %s for changes... (Ctrl+C to quit)\n\n", strings.Join(resources, ", "))
+	}
+
+	go func() {
+		select {
+		case <-sigChan:
+			fmt.Println("\nStopping watch...")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
+
+// watchLoop polls one resource until the context is canceled.
+// ready is called once the initial items have been printed.
+func watchLoop(ctx context.Context, serviceName, verb, resource string, options *FetchOptions, prefix string, printMu *sync.Mutex, ready func()) error {
+	readyOnce := sync.Once{}
+	defer readyOnce.Do(ready)
+
+	seenItems := make(map[string]bool)
+
+	var state *watchState
+	if options.Resume {
+		loaded, err := loadWatchState(resource)
+		if err != nil {
+			return err
+		}
+		state = loaded
+		for _, key := range state.Seen {
+			seenItems[key] = true
+		}
+	}
+
+	pollStart := time.Now().UTC()
+	initialData, full, err := fetchInitialWatchData(serviceName, verb, resource, options, state)
+	if err != nil {
+		return err
+	}
+
+	// Items are only reported as missed when a previous run has been recorded
+	resuming := state != nil && !state.LastPoll.IsZero()
+
+	if results, ok := initialData["results"].([]interface{}); ok {
+		var recentItems, missedItems []map[string]interface{}
+		current := make(map[string]bool, len(results))
+
+		for _, item := range results {
+			if m, ok := item.(map[string]interface{}); ok {
+				key := itemKey(m)
+				if resuming && !seenItems[key] && !current[key] {
+					missedItems = append(missedItems, m)
+				}
+				current[key] = true
+
+				recentItems = append(recentItems, m)
+				if len(recentItems) > 20 {
+					recentItems = recentItems[1:]
+				}
+			}
+		}
+		seenItems = mergeSeenItems(seenItems, current, full)
+
+		printMu.Lock()
+		switch {
+		case resuming && len(missedItems) > 0:
+			fmt.Printf("%sFound %d new items since %s:\n", prefix, len(missedItems), state.lastPollString())
+			format.PrintNewItems(missedItems)
+		case !resuming && len(recentItems) > 0:
+			fmt.Printf("%sRecent items:\n", prefix)
+			format.PrintNewItems(recentItems)
+		}
+		printMu.Unlock()
+
+		if len(missedItems) > 0 {
+			notifyNewItems(serviceName, resource, missedItems, options)
+		}
+	}
+	saveWatchState(state, resource, seenItems, pollStart)
+	readyOnce.Do(ready)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			pollStart := time.Now().UTC()
+			newData, err := FetchService(serviceName, verb, resource, watchOptions(options))
+			if err != nil {
+				continue
+			}
+
+			var newItems []map[string]interface{}
+			if results, ok := newData["results"].([]interface{}); ok {
+				current := make(map[string]bool, len(results))
+				for _, item := range results {
+					if m, ok := item.(map[string]interface{}); ok {
+						key := itemKey(m)
+						if !seenItems[key] && !current[key] {
+							newItems = append(newItems, m)
+						}
+						current[key] = true
+					}
+				}
+				seenItems = mergeSeenItems(seenItems, current, true)
+			}
+
+			if len(newItems) > 0 {
+				printMu.Lock()
+				fmt.Printf("%sFound %d new items at %s:\n",
+					prefix,
+					len(newItems),
+					time.Now().Format("2006-01-02 15:04:05"))
+
+				format.PrintNewItems(newItems)
+				fmt.Println()
+				printMu.Unlock()
+
+				notifyNewItems(serviceName, resource, newItems, options)
+			}
+			saveWatchState(state, resource, seenItems, pollStart)
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// watchOptions returns the options of a polling call, which makes the same request as the command
+// but fetches every item without printing it. Only the options of the output, paging and
+// side effects of the command are reset.
+func watchOptions(options *FetchOptions) *FetchOptions {
+	poll := *options
+
+	poll.OutputFormat = ""
+	poll.OutputFormatExplicit = false
+	poll.CopyToClipboard = false
+	poll.CopyField = ""
+	poll.JQ = ""
+	poll.FailIfEmpty = false
+	poll.ShowStats = false
+	poll.Summary = false
+	poll.GroupBy = ""
+	poll.Aggregation = ""
+	poll.Enrich = nil
+	poll.NotifyCommand = ""
+	poll.NotifyDesktop = false
+	poll.Resume = false
+	poll.Record = ""
+	poll.Replay = ""
+
+	poll.Rows = 0
+	poll.Page = 0
+	poll.PageSize = 0
+	poll.NoPaging = false
+	poll.AllPages = false
+
+	poll.Requested = false
+	return &poll
+}
+
+// fetchInitialWatchData fetches the items to start watching from and reports whether they are the full list.
+// When resuming, only items created since the last poll are requested and the
+// full list is fetched if the resource cannot be filtered by created_at.
+func fetchInitialWatchData(serviceName, verb, resource string, options *FetchOptions, state *watchState) (map[string]interface{}, bool, error) {
+	if state != nil && !state.LastPoll.IsZero() {
+		sinceOptions, err := createdSinceOptions(watchOptions(options), state.LastPoll)
+		if err == nil {
+			if data, err := FetchService(serviceName, verb, resource, sinceOptions); err == nil {
+				return data, false, nil
+			}
+		}
+	}
+
+	data, err := FetchService(serviceName, verb, resource, watchOptions(options))
+	return data, true, err
+}
+
+// mergeSeenItems returns the seen items after a poll. The items of a full list replace the seen items,
+// so that deleted and changed items are forgotten and the seen set does not grow without bound,
+// while the items of a partial list are added.
+func mergeSeenItems(seenItems, current map[string]bool, full bool) map[string]bool {
+	if full {
+		return current
+	}
+	for key := range current {
+		seenItems[key] = true
+	}
+	return seenItems
+}
+
+// createdSinceOptions adds a created_at filter to the query of the call parameters
+func createdSinceOptions(options *FetchOptions, since time.Time) (*FetchOptions, error) {
+	return queryFilterOptions(options, map[string]interface{}{
+		"k": "created_at",
+		"v": since.Format(time.RFC3339),
+		"o": "datetime_gt",
+	})
+}
+
+// itemKey identifies an item by the hash of its fields, so that changed items are reported again
+func itemKey(item map[string]interface{}) string {
+	sum := sha256.Sum256([]byte(format.GenerateIdentifier(item)))
+	return hex.EncodeToString(sum[:16])
+}
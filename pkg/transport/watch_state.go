@@ -0,0 +1,98 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+// watchState is the progress of a watcher persisted with --resume
+type watchState struct {
+	LastPoll time.Time `json:"last_poll"`
+	Seen     []string  `json:"seen"`
+
+	path string
+}
+
+// watchStatePath returns ~/.cfctl/cache/<env>/watch/<resource>.state
+func watchStatePath(resource string) (string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %v", err)
+	}
+
+	return filepath.Join(home, ".cfctl", "cache", config.Environment, "watch", resource+".state"), nil
+}
+
+// loadWatchState reads the persisted state of the resource, a missing state starts from scratch
+func loadWatchState(resource string) (*watchState, error) {
+	path, err := watchStatePath(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &watchState{path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch state: %v", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse watch state %s: %v", path, err)
+	}
+
+	return state, nil
+}
+
+// saveWatchState persists the seen items and poll time, it does nothing when not resuming.
+// The file is only written when the seen items changed, since a poll time which is
+// older than the last poll only widens the created_at filter when resuming.
+func saveWatchState(state *watchState, resource string, seenItems map[string]bool, lastPoll time.Time) {
+	if state == nil {
+		return
+	}
+
+	seen := make([]string, 0, len(seenItems))
+	for key := range seenItems {
+		seen = append(seen, key)
+	}
+	sort.Strings(seen)
+	if !state.LastPoll.IsZero() && slices.Equal(seen, state.Seen) {
+		return
+	}
+
+	state.LastPoll = lastPoll
+	state.Seen = seen
+
+	data, err := json.Marshal(state)
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(state.path), 0700); err == nil {
+			err = os.WriteFile(state.path, data, 0600)
+		}
+	}
+	if err != nil {
+		pterm.Warning.Printf("Failed to save watch state of %s: %v\n", resource, err)
+	}
+}
+
+// lastPollString formats the last poll time in local time
+func (s *watchState) lastPollString() string {
+	if s.LastPoll.IsZero() {
+		return "the last run"
+	}
+	return s.LastPoll.Local().Format("2006-01-02 15:04:05")
+}